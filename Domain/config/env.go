@@ -3,6 +3,8 @@ package config
 import(
 	"github.com/joho/godotenv"
 	"log"
+	"os"
+	"strings"
 )
 
 func LoadEnv() {
@@ -10,4 +12,14 @@ func LoadEnv() {
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
-}
\ No newline at end of file
+}
+
+// IsDevMode true jika APP_ENV development/dev/local/test; selain itu dianggap production
+func IsDevMode() bool {
+	switch strings.ToLower(os.Getenv("APP_ENV")) {
+	case "development", "dev", "local", "test":
+		return true
+	default:
+		return false
+	}
+}
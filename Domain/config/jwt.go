@@ -0,0 +1,246 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultJWTSecret secret bawaan yang hanya boleh dipakai di mode development
+const defaultJWTSecret = "default_secret_change_me"
+
+// JWTKey satu kunci penandatangan/verifikasi token
+// Private nil berarti kunci lama yang hanya dipakai untuk verifikasi
+type JWTKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// JWTKeySet kumpulan kunci JWT yang aktif
+// Mode asimetris (RS256/EdDSA): token ditandatangani kunci aktif dengan header "kid",
+// semua kunci di set dipakai untuk verifikasi sehingga rotasi tidak me-logout user.
+// Tanpa kunci asimetris, token memakai HS256 dengan JWT_SECRET.
+type JWTKeySet struct {
+	active     *JWTKey
+	keys       map[string]*JWTKey
+	hmacSecret []byte
+}
+
+var (
+	jwtKeySet   *JWTKeySet
+	jwtKeySetMu sync.RWMutex
+)
+
+// SetJWTKeySet mengganti key set yang dipakai SignJWT/ParseJWT
+func SetJWTKeySet(ks *JWTKeySet) {
+	jwtKeySetMu.Lock()
+	defer jwtKeySetMu.Unlock()
+	jwtKeySet = ks
+}
+
+// currentJWTKeySet key set aktif; default HS256 dengan JWT_SECRET jika belum dikonfigurasi
+func currentJWTKeySet() *JWTKeySet {
+	jwtKeySetMu.RLock()
+	defer jwtKeySetMu.RUnlock()
+	if jwtKeySet != nil {
+		return jwtKeySet
+	}
+	return &JWTKeySet{hmacSecret: []byte(GetJWTSecret())}
+}
+
+// LoadJWTKeySet membaca kunci JWT dari environment
+//
+// JWT_KEYS_DIR  : folder berisi file PEM "<kid>.pem" (private key RSA/Ed25519, atau public key untuk kunci yang sudah pensiun)
+// JWT_ACTIVE_KID: kid yang dipakai untuk menandatangani token baru
+//
+// Jika JWT_KEYS_DIR kosong, dipakai HS256 dengan JWT_SECRET; secret bawaan ditolak di luar mode development.
+func LoadJWTKeySet() (*JWTKeySet, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if (secret == "" || secret == defaultJWTSecret) && !IsDevMode() {
+			return nil, errors.New("JWT_SECRET belum diatur (masih memakai secret bawaan); set JWT_SECRET atau JWT_KEYS_DIR, atau APP_ENV=development untuk development")
+		}
+		return &JWTKeySet{hmacSecret: []byte(GetJWTSecret())}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &JWTKeySet{keys: make(map[string]*JWTKey)}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseJWTKeyPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("kunci JWT %s: %w", file, err)
+		}
+		ks.keys[kid] = key
+	}
+
+	if len(ks.keys) == 0 {
+		return nil, errors.New("tidak ada file kunci .pem di JWT_KEYS_DIR " + dir)
+	}
+
+	activeKID := os.Getenv("JWT_ACTIVE_KID")
+	if activeKID == "" {
+		// Boleh kosong hanya jika ada tepat satu private key
+		for kid, key := range ks.keys {
+			if key.Private == nil {
+				continue
+			}
+			if activeKID != "" {
+				return nil, errors.New("JWT_ACTIVE_KID harus diisi jika ada lebih dari satu private key")
+			}
+			activeKID = kid
+		}
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok || active.Private == nil {
+		return nil, errors.New("private key untuk JWT_ACTIVE_KID tidak ditemukan: " + activeKID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// ParseJWTKeyPEM membaca private/public key RSA atau Ed25519 dari PEM
+func ParseJWTKeyPEM(kid string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("format PEM tidak valid")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, errors.New("tipe PEM tidak didukung: " + block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errors.New("hanya kunci RSA dan Ed25519 yang didukung")
+	}
+
+	if rsaKey, ok := key.Public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < 2048 {
+		return nil, errors.New("kunci RSA minimal 2048 bit")
+	}
+
+	return key, nil
+}
+
+// SignJWT menandatangani claims dengan kunci aktif
+func SignJWT(claims jwt.Claims) (string, error) {
+	ks := currentJWTKeySet()
+
+	if ks.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.Private)
+}
+
+// ParseJWT memvalidasi token dan mengembalikan token yang sudah di-parse
+// Algoritma dibatasi sesuai kunci (mencegah alg confusion), dan kid wajib dikenal di mode asimetris
+func ParseJWT(tokenString string) (*jwt.Token, error) {
+	ks := currentJWTKeySet()
+
+	if ks.active == nil {
+		return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			return ks.hmacSecret, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	}
+
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, errors.New("kid tidak dikenal")
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("algoritma token tidak sesuai dengan kunci")
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+}
+
+// JWKS mengembalikan public key dalam format JSON Web Key Set (RFC 7517)
+// Kosong pada mode HS256 karena secret tidak boleh dipublikasikan
+func JWKS() map[string]interface{} {
+	ks := currentJWTKeySet()
+
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]map[string]string, 0, len(kids))
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		jwk := map[string]string{
+			"kid": key.ID,
+			"use": "sig",
+			"alg": key.Method.Alg(),
+		}
+
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return map[string]interface{}{"keys": keys}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Mailer interface pengiriman email (reset password, notifikasi)
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailer membuat mailer sesuai MAIL_DRIVER: "log" (default, development) atau "smtp"
+func NewMailer() (Mailer, error) {
+	driver := os.Getenv("MAIL_DRIVER")
+	switch driver {
+	case "", "log":
+		return LogMailer{}, nil
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, errors.New("SMTP_HOST harus diisi untuk MAIL_DRIVER=smtp")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTPMailer{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     GetMailFrom(),
+		}, nil
+	default:
+		return nil, errors.New("mail driver tidak dikenal: " + driver)
+	}
+}
+
+func GetMailFrom() string {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		return "no-reply@localhost"
+	}
+	return from
+}
+
+// LogMailer hanya menulis email ke log (development)
+type LogMailer struct{}
+
+// Send menulis email ke log
+func (LogMailer) Send(to, subject, body string) error {
+	log.Printf("[mail] to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+// SMTPMailer mengirim email lewat server SMTP (STARTTLS otomatis jika didukung server)
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send mengirim email plain text
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errors.New("header email tidak valid")
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.From, to, subject, body)
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(msg))
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrFileNotFound dikembalikan storage jika file dengan key tersebut tidak ada
+var ErrFileNotFound = errors.New("file tidak ditemukan")
+
+// FileStorage interface penyimpanan file lampiran (local filesystem atau S3-compatible)
+// Key berupa path relatif dengan pemisah "/", misalnya "achievements/<id>/<file>"
+type FileStorage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// NewFileStorage membuat storage sesuai STORAGE_DRIVER: "local" (default) atau "s3"
+func NewFileStorage() (FileStorage, error) {
+	driver := os.Getenv("STORAGE_DRIVER")
+	switch driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocalFileStorage(dir), nil
+	case "s3":
+		endpoint := os.Getenv("S3_ENDPOINT")
+		bucket := os.Getenv("S3_BUCKET")
+		if endpoint == "" || bucket == "" {
+			return nil, errors.New("S3_ENDPOINT dan S3_BUCKET harus diisi untuk STORAGE_DRIVER=s3")
+		}
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return &S3FileStorage{
+			Endpoint:        endpoint,
+			Region:          region,
+			Bucket:          bucket,
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") != "false", // MinIO memakai path-style
+		}, nil
+	default:
+		return nil, errors.New("storage driver tidak dikenal: " + driver)
+	}
+}
+
+// GetAttachmentMaxSize batas ukuran satu file lampiran dalam byte (ATTACHMENT_MAX_SIZE_MB, default 5)
+func GetAttachmentMaxSize() int64 {
+	mb, err := strconv.Atoi(os.Getenv("ATTACHMENT_MAX_SIZE_MB"))
+	if err != nil || mb <= 0 {
+		mb = 5
+	}
+	return int64(mb) << 20
+}
+
+// validStorageKey menolak key kosong, absolut, atau yang keluar dari root storage
+func validStorageKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+var errInvalidStorageKey = errors.New("storage key tidak valid")
+
+// LocalFileStorage menyimpan file di folder lokal
+type LocalFileStorage struct {
+	Dir string
+}
+
+// NewLocalFileStorage membuat storage di folder dir
+func NewLocalFileStorage(dir string) *LocalFileStorage {
+	return &LocalFileStorage{Dir: dir}
+}
+
+func (s *LocalFileStorage) path(key string) (string, error) {
+	if !validStorageKey(key) {
+		return "", errInvalidStorageKey
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+// Put menulis file lewat file sementara lalu rename, agar tidak ada file setengah jadi
+func (s *LocalFileStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open membuka file untuk dibaca
+func (s *LocalFileStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete menghapus file; file yang sudah tidak ada tidak dianggap error
+func (s *LocalFileStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
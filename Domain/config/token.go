@@ -3,13 +3,15 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// GetJWTSecret secret HS256; fallback ke secret bawaan hanya diizinkan di mode development (lihat LoadJWTKeySet)
 func GetJWTSecret() string {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
-		secret = "default_secret_change_me"
+		secret = defaultJWTSecret
 	}
 	return secret
 }
@@ -24,4 +26,147 @@ func GetJWTExpiry() time.Duration {
 		return 24 * time.Minute
 	}
 	return time.Duration(hi) * time.Minute
-}
\ No newline at end of file
+}
+func GetRefreshTokenExpiry() time.Duration {
+	h := os.Getenv("REFRESH_TOKEN_EXPIRE_HOURS")
+	if h == "" {
+		return 7 * 24 * time.Hour
+	}
+	hi, err := strconv.Atoi(h)
+	if err != nil || hi <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(hi) * time.Hour
+}
+
+// GetTokenBlacklistDriver menentukan storage blacklist token: "postgres" (default) atau "memory"
+func GetTokenBlacklistDriver() string {
+	driver := os.Getenv("TOKEN_BLACKLIST_DRIVER")
+	if driver == "" {
+		return "postgres"
+	}
+	return driver
+}
+
+func GetTokenBlacklistCleanupInterval() time.Duration {
+	m := os.Getenv("TOKEN_BLACKLIST_CLEANUP_MINUTES")
+	if m == "" {
+		return 10 * time.Minute
+	}
+	mi, err := strconv.Atoi(m)
+	if err != nil || mi <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(mi) * time.Minute
+}
+
+// GetLoginThrottleDriver menentukan storage percobaan login: "postgres" (default) atau "memory"
+func GetLoginThrottleDriver() string {
+	driver := os.Getenv("LOGIN_THROTTLE_DRIVER")
+	if driver == "" {
+		return "postgres"
+	}
+	return driver
+}
+
+func GetLoginMaxFailures() int {
+	return getEnvInt("LOGIN_MAX_FAILURES", 5)
+}
+
+func GetLoginIPMaxFailures() int {
+	return getEnvInt("LOGIN_IP_MAX_FAILURES", 20)
+}
+
+func GetLoginLockoutDuration() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+}
+
+// getEnvInt membaca env bertipe int positif dengan nilai default
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 {
+		return fallback
+	}
+	return i
+}
+
+// GetTwoFactorIssuer nama issuer yang tampil di aplikasi authenticator
+func GetTwoFactorIssuer() string {
+	issuer := os.Getenv("TWO_FACTOR_ISSUER")
+	if issuer == "" {
+		return "Achievement Management System"
+	}
+	return issuer
+}
+
+func GetTwoFactorChallengeExpiry() time.Duration {
+	return time.Duration(getEnvInt("TWO_FACTOR_CHALLENGE_MINUTES", 5)) * time.Minute
+}
+
+// GetTwoFactorRequiredPermissions daftar permission yang mewajibkan 2FA untuk role pemiliknya
+func GetTwoFactorRequiredPermissions() []string {
+	v := os.Getenv("TWO_FACTOR_REQUIRED_PERMISSIONS")
+	if v == "" {
+		return []string{"verify_achievements", "manage_users"}
+	}
+
+	var permissions []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions
+}
+
+func GetPasswordResetExpiry() time.Duration {
+	return time.Duration(getEnvInt("PASSWORD_RESET_EXPIRE_MINUTES", 30)) * time.Minute
+}
+
+// GetPasswordResetURL URL halaman reset password di frontend; token ditambahkan sebagai query ?token=
+func GetPasswordResetURL() string {
+	url := os.Getenv("PASSWORD_RESET_URL")
+	if url == "" {
+		return "http://localhost:3000/reset-password"
+	}
+	return url
+}
+
+// GetPermissionCacheCheckInterval seberapa sering cache permission mengecek versi role ke database
+func GetPermissionCacheCheckInterval() time.Duration {
+	return time.Duration(getEnvInt("PERMISSION_CACHE_CHECK_SECONDS", 5)) * time.Second
+}
+
+// GetImpersonationExpiry masa berlaku token impersonation (tanpa refresh token)
+func GetImpersonationExpiry() time.Duration {
+	return time.Duration(getEnvInt("IMPERSONATION_EXPIRE_MINUTES", 15)) * time.Minute
+}
+
+// GetAttachmentURLExpiry masa berlaku link download lampiran yang ditandatangani
+func GetAttachmentURLExpiry() time.Duration {
+	return time.Duration(getEnvInt("ATTACHMENT_URL_EXPIRE_MINUTES", 15)) * time.Minute
+}
+
+// GetPublicBaseURL base URL API untuk link yang dibuka di browser (kosong = path relatif)
+func GetPublicBaseURL() string {
+	return strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/")
+}
+
+// GetReviewSLADays batas waktu review bawaan (hari sejak submit) jika tidak ada aturan SLA yang cocok
+func GetReviewSLADays() int {
+	return getEnvInt("REVIEW_SLA_DAYS", 7)
+}
+
+// GetReviewEscalationEmail penerima eskalasi bawaan (misalnya ketua departemen); kosong = hanya log
+func GetReviewEscalationEmail() string {
+	return strings.TrimSpace(os.Getenv("REVIEW_ESCALATION_EMAIL"))
+}
+
+// GetReviewEscalationInterval seberapa sering scheduler eskalasi review berjalan
+func GetReviewEscalationInterval() time.Duration {
+	return time.Duration(getEnvInt("REVIEW_ESCALATION_INTERVAL_MINUTES", 60)) * time.Minute
+}
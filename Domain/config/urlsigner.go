@@ -0,0 +1,77 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultURLSigningSecret secret bawaan yang hanya boleh dipakai di mode development
+const defaultURLSigningSecret = "default_url_secret_change_me"
+
+var (
+	ErrURLSignatureInvalid = errors.New("signature URL tidak valid")
+	ErrURLExpired          = errors.New("URL sudah kedaluwarsa")
+)
+
+// URLSigner membuat dan memverifikasi URL yang ditandatangani HMAC-SHA256 dengan batas waktu
+// Dipakai untuk link download lampiran yang bisa dibuka tanpa bearer token.
+type URLSigner struct {
+	secret []byte
+}
+
+// NewURLSigner membuat signer dengan secret tertentu
+func NewURLSigner(secret []byte) *URLSigner {
+	return &URLSigner{secret: secret}
+}
+
+// LoadURLSigner membuat signer dari ATTACHMENT_URL_SECRET; secret bawaan hanya diizinkan di mode development
+func LoadURLSigner() (*URLSigner, error) {
+	secret := os.Getenv("ATTACHMENT_URL_SECRET")
+	if secret == "" || secret == defaultURLSigningSecret {
+		if !IsDevMode() {
+			return nil, errors.New("ATTACHMENT_URL_SECRET belum diatur; set ATTACHMENT_URL_SECRET, atau APP_ENV=development untuk development")
+		}
+		secret = defaultURLSigningSecret
+	}
+	return NewURLSigner([]byte(secret)), nil
+}
+
+func (s *URLSigner) signature(path string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Sign mengembalikan path dengan query expires (unix) dan signature
+func (s *URLSigner) Sign(path string, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signature(path, expires))
+	return path + "?" + query.Encode()
+}
+
+// Verify mengecek signature dan masa berlaku URL pada waktu now
+func (s *URLSigner) Verify(path, expires, signature string, now time.Time) error {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || signature == "" {
+		return ErrURLSignatureInvalid
+	}
+
+	expected := s.signature(path, expiresUnix)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrURLSignatureInvalid
+	}
+
+	// Expiry dicek setelah signature agar nilai expires tidak bisa diubah
+	if now.Unix() > expiresUnix {
+		return ErrURLExpired
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// localsAllowImpersonatedWrite penanda route yang boleh dipanggil dengan method tulis saat impersonation
+const localsAllowImpersonatedWrite = "allow_impersonated_write"
+
+var errImpersonationInactive = errors.New("impersonation tidak aktif")
+
+// AllowImpersonatedWrite mengizinkan request tulis (POST/PUT/DELETE) dari token impersonation
+// pada route ini, mis. untuk mengakhiri impersonation. Dipasang sebelum JWTAuth.
+func AllowImpersonatedWrite() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(localsAllowImpersonatedWrite, true)
+		return c.Next()
+	}
+}
+
+// verifyImpersonation memastikan token impersonation (claim "imp" dan "act") masih berlaku:
+// impersonation belum diakhiri/kadaluarsa, cocok dengan user dan impersonator di token,
+// dan impersonator masih memiliki permission impersonate_users
+func verifyImpersonation(claims jwt.MapClaims, userID string) (*model.Impersonations, error) {
+	impID, _ := claims["imp"].(string)
+	impUUID, err := uuid.Parse(impID)
+	if err != nil {
+		return nil, errImpersonationInactive
+	}
+
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok {
+		return nil, errImpersonationInactive
+	}
+	actorID, _ := act["sub"].(string)
+	actorRoleID, _ := act["role_id"].(string)
+
+	imp, err := repository.GetImpersonationByID(impUUID)
+	if err != nil {
+		return nil, errImpersonationInactive
+	}
+
+	if imp.EndedAt != nil || !time.Now().Before(imp.ExpiresAt) ||
+		imp.TargetUserID.String() != userID || imp.ImpersonatorID.String() != actorID {
+		return nil, errImpersonationInactive
+	}
+
+	roleUUID, err := uuid.Parse(actorRoleID)
+	if err != nil {
+		return nil, errImpersonationInactive
+	}
+	actorPermissions, _, err := repository.RolePermissionCache().Get(roleUUID, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range actorPermissions {
+		if p == repository.ImpersonateUsersPermission {
+			return imp, nil
+		}
+	}
+
+	return nil, errImpersonationInactive
+}
+
+// impersonatedWriteBlocked mengecek (dan mencatat) request tulis dari token impersonation
+// Impersonation hanya untuk melihat apa yang dilihat user, bukan bertindak atas namanya
+func impersonatedWriteBlocked(c *fiber.Ctx, imp *model.Impersonations) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return false
+	}
+
+	if allowed, _ := c.Locals(localsAllowImpersonatedWrite).(bool); allowed {
+		return false
+	}
+
+	_ = repository.RecordImpersonationEvent(&model.ImpersonationEvents{
+		ImpersonationID: imp.ID,
+		Event:           repository.ImpersonationEventWriteBlocked,
+		Method:          c.Method(),
+		Path:            c.Path(),
+		IPAddress:       c.IP(),
+	})
+
+	return true
+}
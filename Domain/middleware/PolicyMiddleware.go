@@ -0,0 +1,363 @@
+package middleware
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Policy layer untuk akses per baris (row-level)
+//
+// RequirePermission hanya mengecek "boleh memakai endpoint ini". Authorize mengecek
+// "boleh mengakses data yang ini": route mendeklarasikan resource dan action, data dimuat
+// oleh ResourceLoader, lalu rule policy dievaluasi. Semua aturan kepemilikan ada di sini.
+
+// Permission yang menandakan admin (akses semua data tanpa cek kepemilikan)
+const (
+	AdminAchievementsPermission = "manage_achievements"
+	AdminStudentsPermission     = "manage_students"
+)
+
+// Key Locals tempat data yang sudah dimuat policy disimpan untuk dipakai service
+const (
+	localsAchievementReference = "achievement_reference"
+	localsAchievementStudent   = "achievement_student"
+	localsStudent              = "student"
+	localsLecturer             = "lecturer"
+	localsPolicyRule           = "policy_rule"
+)
+
+var (
+	ErrInvalidResourceID = errors.New("id resource tidak valid")
+	ErrResourceNotFound  = errors.New("resource tidak ditemukan")
+)
+
+// Subject identitas pemanggil yang dievaluasi policy
+type Subject struct {
+	UserID      uuid.UUID
+	Permissions map[string]bool
+	StudentID   *uuid.UUID // profil mahasiswa pemanggil, nil jika bukan mahasiswa
+	LecturerID  *uuid.UUID // profil dosen pemanggil, nil jika bukan dosen
+}
+
+// HasPermission mengecek permission pemanggil
+func (s *Subject) HasPermission(permission string) bool {
+	return s.Permissions[permission]
+}
+
+// Resource ringkasan data yang diakses, cukup untuk mengevaluasi rule
+type Resource struct {
+	Type           string
+	OwnerStudentID uuid.UUID // mahasiswa pemilik data
+	AdvisorID      uuid.UUID // dosen wali mahasiswa pemilik data
+	LecturerID     uuid.UUID // dosen (resource lecturer)
+	Status         string
+	ApprovalStage  *model.ApprovalStage // tahap persetujuan achievement saat ini, nil = tahap dosen wali
+	BackupReviewer uuid.UUID            // dosen pengganti hasil eskalasi review (tahap dosen wali)
+	StageMissing   bool                 // current_stage tidak ada di workflow: tidak ada approver yang berlaku
+}
+
+// Rule satu aturan akses; Name dicatat di Locals agar service tahu jalur aksesnya
+type Rule struct {
+	Name  string
+	Allow func(sub *Subject, res *Resource) bool
+}
+
+// ResourceLoader memuat resource dari request (biasanya dari parameter :id)
+// Mengembalikan ErrInvalidResourceID atau ErrResourceNotFound untuk input yang salah
+type ResourceLoader func(c *fiber.Ctx) (*Resource, error)
+
+// AdminRule mengizinkan pemanggil yang memiliki permission admin
+func AdminRule(permission string) Rule {
+	return Rule{
+		Name: "admin",
+		Allow: func(sub *Subject, res *Resource) bool {
+			return sub.HasPermission(permission)
+		},
+	}
+}
+
+// OwnerRule mengizinkan mahasiswa pemilik data
+var OwnerRule = Rule{
+	Name: "owner",
+	Allow: func(sub *Subject, res *Resource) bool {
+		return sub.StudentID != nil && *sub.StudentID == res.OwnerStudentID
+	},
+}
+
+// AdvisorRule mengizinkan dosen wali dari mahasiswa pemilik data
+var AdvisorRule = Rule{
+	Name: "advisor",
+	Allow: func(sub *Subject, res *Resource) bool {
+		return sub.LecturerID != nil && res.AdvisorID != uuid.Nil && *sub.LecturerID == res.AdvisorID
+	},
+}
+
+// SelfLecturerRule mengizinkan dosen mengakses datanya sendiri
+var SelfLecturerRule = Rule{
+	Name: "self",
+	Allow: func(sub *Subject, res *Resource) bool {
+		return sub.LecturerID != nil && *sub.LecturerID == res.LecturerID
+	},
+}
+
+// approvalStageAllows mengecek pemanggil adalah approver tahap persetujuan saat ini:
+// memiliki permission tahap, dan untuk tahap advisor_only juga dosen wali pemilik data
+// (atau dosen pengganti jika review sudah dieskalasi)
+func approvalStageAllows(sub *Subject, res *Resource) bool {
+	if res.StageMissing {
+		return false
+	}
+	stage := res.ApprovalStage
+	if stage == nil {
+		stage = &model.DefaultApprovalStages[0]
+	}
+	if !sub.HasPermission(stage.RequiredPermission) {
+		return false
+	}
+	return !stage.AdvisorOnly || AdvisorRule.Allow(sub, res) || backupReviewerAllows(sub, res)
+}
+
+// backupReviewerAllows pemanggil adalah dosen pengganti yang ditunjuk eskalasi review
+func backupReviewerAllows(sub *Subject, res *Resource) bool {
+	return sub.LecturerID != nil && res.BackupReviewer != uuid.Nil && *sub.LecturerID == res.BackupReviewer
+}
+
+// ApprovalStageRule mengizinkan approver tahap persetujuan achievement saat ini
+var ApprovalStageRule = Rule{
+	Name:  "approver",
+	Allow: approvalStageAllows,
+}
+
+// PendingApproverRule mengizinkan approver tahap saat ini membaca achievement yang sedang menunggu persetujuannya
+var PendingApproverRule = Rule{
+	Name: "approver",
+	Allow: func(sub *Subject, res *Resource) bool {
+		return res.Status == "submitted" && approvalStageAllows(sub, res)
+	},
+}
+
+type resourcePolicy struct {
+	loader  ResourceLoader
+	actions map[string][]Rule
+}
+
+// policies daftar policy per resource dan action
+var policies = map[string]*resourcePolicy{
+	"achievement": {
+		loader: loadAchievementResource,
+		actions: map[string][]Rule{
+			"read":              {AdminRule(AdminAchievementsPermission), OwnerRule, AdvisorRule, PendingApproverRule},
+			"update":            {OwnerRule},
+			"delete":            {OwnerRule},
+			"submit":            {OwnerRule},
+			"upload":            {OwnerRule},
+			"delete_attachment": {OwnerRule},
+			"verify":            {ApprovalStageRule},
+			"reject":            {ApprovalStageRule},
+			"request_revision":  {ApprovalStageRule},
+			"withdraw":          {OwnerRule},
+			"revoke":            {AdminRule(AdminAchievementsPermission)},
+			"comment":           {OwnerRule, AdvisorRule},
+			"history":           {AdminRule(AdminAchievementsPermission), OwnerRule, AdvisorRule, PendingApproverRule},
+		},
+	},
+	"student": {
+		loader: loadStudentResource,
+		actions: map[string][]Rule{
+			"read":              {AdminRule(AdminStudentsPermission), OwnerRule, AdvisorRule},
+			"read_achievements": {AdminRule(AdminAchievementsPermission), OwnerRule, AdvisorRule},
+			"set_advisor":       {AdminRule(AdminStudentsPermission)},
+		},
+	},
+	"lecturer": {
+		loader: loadLecturerResource,
+		actions: map[string][]Rule{
+			"read_advisees": {AdminRule(AdminStudentsPermission), SelfLecturerRule},
+		},
+	},
+}
+
+// EvaluatePolicy mengevaluasi rule resource/action; mengembalikan nama rule yang mengizinkan
+func EvaluatePolicy(resourceType, action string, sub *Subject, res *Resource) (string, bool) {
+	policy, ok := policies[resourceType]
+	if !ok {
+		return "", false
+	}
+
+	for _, rule := range policy.actions[action] {
+		if rule.Allow(sub, res) {
+			return rule.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// Authorize middleware akses per baris untuk resource dan action tertentu
+// Dipasang setelah JWTAuth (dan RequirePermission jika perlu). Panic saat registrasi
+// route jika policy belum didefinisikan, agar salah ketik tidak lolos ke production.
+func Authorize(resourceType, action string) fiber.Handler {
+	policy, ok := policies[resourceType]
+	if !ok {
+		panic("policy tidak terdaftar untuk resource: " + resourceType)
+	}
+	if _, ok := policy.actions[action]; !ok {
+		panic("policy tidak terdaftar untuk action: " + resourceType + ":" + action)
+	}
+
+	return func(c *fiber.Ctx) error {
+		res, err := policy.loader(c)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidResourceID):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + resourceType + " ID",
+				})
+			case errors.Is(err, ErrResourceNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": "Data tidak ditemukan",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal memuat data",
+			})
+		}
+
+		sub, err := subjectFromContext(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid user ID",
+			})
+		}
+
+		ruleName, allowed := EvaluatePolicy(resourceType, action, sub, res)
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "Akses ditolak",
+				"message": "Anda tidak memiliki akses ke data ini",
+			})
+		}
+
+		c.Locals(localsPolicyRule, ruleName)
+		return c.Next()
+	}
+}
+
+// subjectFromContext membangun Subject dari Locals JWTAuth beserta profil mahasiswa/dosen
+func subjectFromContext(c *fiber.Ctx) (*Subject, error) {
+	userIDStr, _ := c.Locals("id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subject{
+		UserID:      userID,
+		Permissions: make(map[string]bool),
+	}
+
+	if perms, ok := c.Locals("permissions").([]interface{}); ok {
+		for _, perm := range perms {
+			if permStr, ok := perm.(string); ok {
+				sub.Permissions[permStr] = true
+			}
+		}
+	}
+
+	if student, err := repository.GetStudentByUserID(userID); err == nil {
+		sub.StudentID = &student.ID
+	}
+	if lecturer, err := repository.GetLecturerByUserID(userID); err == nil {
+		sub.LecturerID = &lecturer.ID
+	}
+
+	return sub, nil
+}
+
+// loadAchievementResource memuat reference achievement (:id = MongoDB ObjectID) dan mahasiswanya
+func loadAchievementResource(c *fiber.Ctx) (*Resource, error) {
+	achievementID := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(achievementID); err != nil {
+		return nil, ErrInvalidResourceID
+	}
+
+	reference, err := repository.GetAchievementReferenceByMongoID(achievementID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	student, err := repository.GetStudentByID(reference.StudentID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	// Tahap yang tidak ada di workflow tidak diberikan ke approver mana pun; pemilik, dosen wali, dan admin
+	// tetap bisa membaca prestasi tersebut
+	stage, _, err := repository.GetCurrentApprovalStage(reference)
+	stageMissing := errors.Is(err, model.ErrApprovalStageOutOfRange)
+	if err != nil && !stageMissing {
+		return nil, err
+	}
+
+	c.Locals(localsAchievementReference, reference)
+	c.Locals(localsAchievementStudent, student)
+
+	resource := &Resource{
+		Type:           "achievement",
+		OwnerStudentID: student.ID,
+		AdvisorID:      student.AdvisorID,
+		Status:         string(reference.Status),
+		ApprovalStage:  stage,
+		StageMissing:   stageMissing,
+	}
+	if reference.EscalatedTo != nil {
+		resource.BackupReviewer = *reference.EscalatedTo
+	}
+	return resource, nil
+}
+
+// loadStudentResource memuat data mahasiswa (:id = UUID students.id)
+func loadStudentResource(c *fiber.Ctx) (*Resource, error) {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, ErrInvalidResourceID
+	}
+
+	student, err := repository.GetStudentByID(studentID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	c.Locals(localsStudent, student)
+
+	return &Resource{
+		Type:           "student",
+		OwnerStudentID: student.ID,
+		AdvisorID:      student.AdvisorID,
+	}, nil
+}
+
+// loadLecturerResource memuat data dosen (:id = UUID lecturers.id)
+func loadLecturerResource(c *fiber.Ctx) (*Resource, error) {
+	lecturerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, ErrInvalidResourceID
+	}
+
+	lecturer, err := repository.GetLecturerByID(lecturerID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	c.Locals(localsLecturer, lecturer)
+
+	return &Resource{
+		Type:       "lecturer",
+		LecturerID: lecturer.ID,
+	}, nil
+}
@@ -22,6 +22,16 @@ func CallService(serviceName, methodName string) fiber.Handler {
 			return callLecturerService(c, methodName)
 		case "ReportService":
 			return callReportService(c, methodName)
+		case "RoleService":
+			return callRoleService(c, methodName)
+		case "PointRuleService":
+			return callPointRuleService(c, methodName)
+		case "AchievementTypeService":
+			return callAchievementTypeService(c, methodName)
+		case "ApprovalWorkflowService":
+			return callApprovalWorkflowService(c, methodName)
+		case "ReviewSLAService":
+			return callReviewSLAService(c, methodName)
 		default:
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Service not found: " + serviceName,
@@ -38,15 +48,45 @@ func callAuthService(c *fiber.Ctx, methodName string) error {
 	case "Logout":
 		return service.LogoutService(c)
 	case "Refresh":
-		// TODO: Implement refresh token service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Refresh token not implemented yet",
-		})
+		return service.RefreshTokenService(c)
 	case "GetProfile":
-		// TODO: Implement get profile service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Get profile not implemented yet",
-		})
+		return service.GetProfileService(c)
+	case "GetSessions":
+		return service.GetMySessionsService(c)
+	case "RevokeSession":
+		return service.RevokeMySessionService(c)
+	case "RevokeOtherSessions":
+		return service.RevokeOtherSessionsService(c)
+	case "LoginTwoFactor":
+		return service.LoginTwoFactorService(c)
+	case "GetTwoFactorStatus":
+		return service.GetTwoFactorStatusService(c)
+	case "EnrollTwoFactor":
+		return service.EnrollTwoFactorService(c)
+	case "EnableTwoFactor":
+		return service.EnableTwoFactorService(c)
+	case "DisableTwoFactor":
+		return service.DisableTwoFactorService(c)
+	case "RegenerateRecoveryCodes":
+		return service.RegenerateRecoveryCodesService(c)
+	case "ForgotPassword":
+		return service.ForgotPasswordService(c)
+	case "ResetPassword":
+		return service.ResetPasswordService(c)
+	case "ChangePassword":
+		return service.ChangePasswordService(c)
+	case "JWKS":
+		return service.JWKSService(c)
+	case "StartImpersonation":
+		return service.StartImpersonationService(c)
+	case "StopImpersonation":
+		return service.StopImpersonationService(c)
+	case "GetImpersonations":
+		return service.GetImpersonationsService(c)
+	case "GetImpersonationDetail":
+		return service.GetImpersonationDetailService(c)
+	case "EndImpersonation":
+		return service.EndImpersonationService(c)
 	default:
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Method not found: " + methodName,
@@ -73,6 +113,14 @@ func callUserService(c *fiber.Ctx, methodName string) error {
 		return service.SetStudentProfileService(c)
 	case "SetLecturerProfile":
 		return service.SetLecturerProfileService(c)
+	case "GetUserSessions":
+		return service.GetUserSessionsService(c)
+	case "ForceLogout":
+		return service.ForceLogoutUserService(c)
+	case "UnlockUser":
+		return service.UnlockUserService(c)
+	case "ResetTwoFactor":
+		return service.ResetUserTwoFactorService(c)
 	default:
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Method not found: " + methodName,
@@ -104,25 +152,33 @@ func callAchievementService(c *fiber.Ctx, methodName string) error {
 	case "GetAllAchievementStats":
 		return service.GetAllAchievementStatsService(c)
 	case "GetAchievementDetail":
-		// TODO: Implement get achievement detail service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Get achievement detail not implemented yet",
-		})
+		return service.GetAchievementDetailService(c)
 	case "UpdateAchievement":
-		// TODO: Implement update achievement service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Update achievement not implemented yet",
-		})
+		return service.UpdateAchievementService(c)
 	case "GetAchievementHistory":
-		// TODO: Implement get achievement history service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Get achievement history not implemented yet",
-		})
+		return service.GetAchievementHistoryService(c)
 	case "UploadAttachments":
-		// TODO: Implement upload attachments service
-		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-			"error": "Upload attachments not implemented yet",
-		})
+		return service.UploadAttachmentService(c)
+	case "DownloadAttachment":
+		return service.DownloadAttachmentService(c)
+	case "DeleteAttachment":
+		return service.DeleteAttachmentService(c)
+	case "DownloadSignedAttachment":
+		return service.DownloadSignedAttachmentService(c)
+	case "PreviewAchievementPoints":
+		return service.PreviewAchievementPointsService(c)
+	case "GetAchievementComments":
+		return service.GetAchievementCommentsService(c)
+	case "CreateAchievementComment":
+		return service.CreateAchievementCommentService(c)
+	case "RequestRevision":
+		return service.RequestRevisionService(c)
+	case "WithdrawAchievement":
+		return service.WithdrawAchievementService(c)
+	case "RevokeAchievementVerification":
+		return service.RevokeAchievementVerificationService(c)
+	case "GetPendingApprovals":
+		return service.GetPendingApprovalsService(c)
 	default:
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Method not found: " + methodName,
@@ -199,3 +255,113 @@ func callReportService(c *fiber.Ctx, methodName string) error {
 		})
 	}
 }
+
+// Role Service Calls
+func callRoleService(c *fiber.Ctx, methodName string) error {
+	switch methodName {
+	case "GetRoles":
+		return service.GetRolesService(c)
+	case "GetRoleDetail":
+		return service.GetRoleDetailService(c)
+	case "CreateRole":
+		return service.CreateRoleService(c)
+	case "UpdateRole":
+		return service.UpdateRoleService(c)
+	case "DeleteRole":
+		return service.DeleteRoleService(c)
+	case "AttachRolePermission":
+		return service.AttachRolePermissionService(c)
+	case "DetachRolePermission":
+		return service.DetachRolePermissionService(c)
+	case "GetPermissions":
+		return service.GetPermissionsService(c)
+	case "CreatePermission":
+		return service.CreatePermissionService(c)
+	case "UpdatePermission":
+		return service.UpdatePermissionService(c)
+	case "DeletePermission":
+		return service.DeletePermissionService(c)
+	default:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Method not found: " + methodName,
+		})
+	}
+}
+
+// Point Rule Service Calls
+func callPointRuleService(c *fiber.Ctx, methodName string) error {
+	switch methodName {
+	case "GetPointRuleSets":
+		return service.GetPointRuleSetsService(c)
+	case "GetActivePointRuleSet":
+		return service.GetActivePointRuleSetService(c)
+	case "GetPointRuleSet":
+		return service.GetPointRuleSetService(c)
+	case "CreatePointRuleSet":
+		return service.CreatePointRuleSetService(c)
+	case "ActivatePointRuleSet":
+		return service.ActivatePointRuleSetService(c)
+	case "RecomputePoints":
+		return service.RecomputePointsService(c)
+	default:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Method not found: " + methodName,
+		})
+	}
+}
+
+// Achievement Type Service Calls
+func callAchievementTypeService(c *fiber.Ctx, methodName string) error {
+	switch methodName {
+	case "GetAchievementTypes":
+		return service.GetAchievementTypesService(c)
+	case "PutAchievementTypeSchema":
+		return service.PutAchievementTypeSchemaService(c)
+	case "DeleteAchievementTypeSchema":
+		return service.DeleteAchievementTypeSchemaService(c)
+	default:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Method not found: " + methodName,
+		})
+	}
+}
+
+// Approval Workflow Service Calls
+func callApprovalWorkflowService(c *fiber.Ctx, methodName string) error {
+	switch methodName {
+	case "GetApprovalWorkflows":
+		return service.GetApprovalWorkflowsService(c)
+	case "GetApprovalWorkflow":
+		return service.GetApprovalWorkflowService(c)
+	case "CreateApprovalWorkflow":
+		return service.CreateApprovalWorkflowService(c)
+	case "UpdateApprovalWorkflow":
+		return service.UpdateApprovalWorkflowService(c)
+	case "DeleteApprovalWorkflow":
+		return service.DeleteApprovalWorkflowService(c)
+	default:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Method not found: " + methodName,
+		})
+	}
+}
+
+// Review SLA Service Calls
+func callReviewSLAService(c *fiber.Ctx, methodName string) error {
+	switch methodName {
+	case "GetReviewSLAs":
+		return service.GetReviewSLAsService(c)
+	case "GetReviewSLA":
+		return service.GetReviewSLAService(c)
+	case "CreateReviewSLA":
+		return service.CreateReviewSLAService(c)
+	case "UpdateReviewSLA":
+		return service.UpdateReviewSLAService(c)
+	case "DeleteReviewSLA":
+		return service.DeleteReviewSLAService(c)
+	default:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Method not found: " + methodName,
+		})
+	}
+}
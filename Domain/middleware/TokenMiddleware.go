@@ -1,16 +1,21 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
 	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
 	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func JWTAuth() fiber.Handler {
+// JWTAuth memvalidasi bearer token dan menyimpan identitas user ke context
+// Blacklist di-inject agar semua replica memakai storage yang sama
+func JWTAuth(blacklist repository.TokenBlacklistRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -25,7 +30,7 @@ func JWTAuth() fiber.Handler {
 		tokenString := parts[1]
 
 		// Cek apakah token ada di blacklist
-		isBlacklisted, err := repository.IsTokenBlacklisted(tokenString)
+		isBlacklisted, err := blacklist.Exists(tokenString)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memvalidasi token"})
 		}
@@ -34,9 +39,7 @@ func JWTAuth() fiber.Handler {
 		}
 
 		// Parse dan validasi token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.GetJWTSecret()), nil
-		})
+		token, err := config.ParseJWT(tokenString)
 		if err != nil || !token.Valid {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
 		}
@@ -46,6 +49,11 @@ func JWTAuth() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token claims"})
 		}
 
+		// Token khusus (mis. challenge 2FA) tidak boleh dipakai sebagai access token
+		if _, exists := claims["typ"]; exists {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
+		}
+
 		// Ambil data dari claims
 		var userID string
 		var roleID string
@@ -64,8 +72,53 @@ func JWTAuth() fiber.Handler {
 			username = u
 		}
 
-		if p, exists := claims["permissions"].([]interface{}); exists {
-			permissions = p
+		// Permission diambil dari cache role -> permission (bukan dari claim token),
+		// sehingga pencabutan permission berlaku langsung tanpa menunggu token expired
+		permissions = []interface{}{}
+		if tfaSetup, _ := claims["tfa_setup"].(bool); !tfaSetup {
+			if roleUUID, err := uuid.Parse(roleID); err == nil {
+				var minVersion int64
+				if pv, exists := claims["pv"].(float64); exists {
+					minVersion = int64(pv)
+				}
+
+				rolePermissions, _, err := repository.RolePermissionCache().Get(roleUUID, minVersion)
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memuat permission"})
+				}
+				for _, p := range rolePermissions {
+					permissions = append(permissions, p)
+				}
+			}
+		}
+
+		// Token yang terikat ke sesi ditolak jika sesinya sudah dicabut
+		var sessionID string
+		if sid, exists := claims["sid"].(string); exists {
+			sessionUUID, err := uuid.Parse(sid)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token claims"})
+			}
+
+			session, err := repository.GetSessionByID(sessionUUID)
+			if err != nil || session.RevokedAt != nil || session.UserID.String() != userID {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Sesi telah berakhir, silakan login ulang"})
+			}
+
+			_ = repository.TouchSession(sessionUUID)
+			sessionID = sid
+		}
+
+		// Token impersonation ("login as"): impersonation harus masih aktif
+		var impersonation *model.Impersonations
+		if _, exists := claims["imp"]; exists {
+			impersonation, err = verifyImpersonation(claims, userID)
+			if err != nil {
+				if errors.Is(err, errImpersonationInactive) {
+					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Impersonation telah berakhir"})
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memvalidasi impersonation"})
+			}
 		}
 
 		// Simpan ke context
@@ -73,6 +126,22 @@ func JWTAuth() fiber.Handler {
 		c.Locals("role_id", roleID)
 		c.Locals("username", username)
 		c.Locals("permissions", permissions)
+		c.Locals("session_id", sessionID)
+		c.Locals("token_blacklist", blacklist)
+
+		// Identitas impersonator; "id" di atas tetap user yang di-impersonate
+		if impersonation != nil {
+			c.Locals("impersonation_id", impersonation.ID.String())
+			c.Locals("impersonator_id", impersonation.ImpersonatorID.String())
+			c.Locals("impersonator_username", impersonation.ImpersonatorUsername)
+
+			if impersonatedWriteBlocked(c, impersonation) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Aksi tidak diizinkan selama impersonation",
+					"message": "Token impersonation hanya bisa dipakai untuk melihat data",
+				})
+			}
+		}
 
 		return c.Next()
 	}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis komentar di thread prestasi
+const (
+	CommentKindComment         = "comment"
+	CommentKindRevisionRequest = "revision_request"
+)
+
+type AchievementComments struct {
+	ID                     uuid.UUID  `json:"id"`
+	AchievementReferenceID uuid.UUID  `json:"achievement_reference_id"`
+	ParentID               *uuid.UUID `json:"parent_id"`
+	AuthorID               *uuid.UUID `json:"author_id"`
+	AuthorName             *string    `json:"author_name"`
+	AuthorRole             string     `json:"author_role"`
+	Kind                   string     `json:"kind"`
+	Body                   string     `json:"body"`
+	AttachmentIDs          []string   `json:"attachment_ids"`
+	CreatedAt              time.Time  `json:"created_at"`
+}
+
+// CreateCommentRequest body untuk menambah komentar
+// AttachmentIDs merujuk ke lampiran achievement yang dibahas (id dari achievement.attachments)
+type CreateCommentRequest struct {
+	Body          string   `json:"body"`
+	ParentID      string   `json:"parent_id,omitempty"`
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
+}
+
+// RequestRevisionRequest body untuk aksi request revision oleh dosen wali
+type RequestRevisionRequest struct {
+	Body          string   `json:"body"`
+	AttachmentIDs []string `json:"attachment_ids,omitempty"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import "errors"
+
+// AchievementStatus status prestasi di achievement_references
+type AchievementStatus string
+
+const (
+	AchievementStatusDraft             AchievementStatus = "draft"
+	AchievementStatusSubmitted         AchievementStatus = "submitted"
+	AchievementStatusRevisionRequested AchievementStatus = "revision_requested"
+	AchievementStatusVerified          AchievementStatus = "verified"
+	AchievementStatusRejected          AchievementStatus = "rejected"
+	AchievementStatusRevoked           AchievementStatus = "revoked"
+)
+
+// AchievementAction aksi yang mengubah status prestasi
+type AchievementAction string
+
+const (
+	AchievementActionSubmit          AchievementAction = "submit"
+	AchievementActionApproveStage    AchievementAction = "approve_stage"
+	AchievementActionVerify          AchievementAction = "verify"
+	AchievementActionReject          AchievementAction = "reject"
+	AchievementActionRequestRevision AchievementAction = "request_revision"
+	AchievementActionReopen          AchievementAction = "reopen"
+	AchievementActionDelete          AchievementAction = "delete"
+	AchievementActionWithdraw        AchievementAction = "withdraw"
+	AchievementActionRevoke          AchievementAction = "revoke"
+)
+
+// AchievementActor pihak yang boleh menjalankan transisi; sama dengan nama rule policy
+// yang mengizinkan request (Locals "policy_rule")
+type AchievementActor string
+
+const (
+	AchievementActorOwner    AchievementActor = "owner"
+	AchievementActorApprover AchievementActor = "approver"
+	AchievementActorAdmin    AchievementActor = "admin"
+)
+
+// AchievementTransition satu transisi yang diizinkan. To kosong berarti reference dihapus.
+type AchievementTransition struct {
+	Action AchievementAction
+	From   AchievementStatus
+	To     AchievementStatus
+	Actor  AchievementActor
+}
+
+// AchievementTransitions tabel transisi status prestasi. Transisi yang tidak ada di sini ditolak.
+var AchievementTransitions = []AchievementTransition{
+	{AchievementActionSubmit, AchievementStatusDraft, AchievementStatusSubmitted, AchievementActorOwner},
+	{AchievementActionSubmit, AchievementStatusRevisionRequested, AchievementStatusSubmitted, AchievementActorOwner},
+	{AchievementActionApproveStage, AchievementStatusSubmitted, AchievementStatusSubmitted, AchievementActorApprover},
+	{AchievementActionVerify, AchievementStatusSubmitted, AchievementStatusVerified, AchievementActorApprover},
+	{AchievementActionReject, AchievementStatusSubmitted, AchievementStatusRejected, AchievementActorApprover},
+	{AchievementActionRequestRevision, AchievementStatusSubmitted, AchievementStatusRevisionRequested, AchievementActorApprover},
+	{AchievementActionReopen, AchievementStatusRejected, AchievementStatusDraft, AchievementActorOwner},
+	{AchievementActionDelete, AchievementStatusDraft, "", AchievementActorOwner},
+	{AchievementActionWithdraw, AchievementStatusSubmitted, AchievementStatusDraft, AchievementActorOwner},
+	{AchievementActionRevoke, AchievementStatusVerified, AchievementStatusRevoked, AchievementActorAdmin},
+}
+
+// EditableAchievementStatuses status yang masih boleh diedit mahasiswa (tanpa mengubah status,
+// kecuali rejected yang dibuka kembali ke draft lewat aksi reopen)
+var EditableAchievementStatuses = map[AchievementStatus]bool{
+	AchievementStatusDraft:             true,
+	AchievementStatusRejected:          true,
+	AchievementStatusRevisionRequested: true,
+}
+
+var ErrAchievementTransitionNotAllowed = errors.New("transisi status achievement tidak diizinkan")
+
+// FindAchievementTransition mencari transisi untuk aksi dari status tertentu
+func FindAchievementTransition(action AchievementAction, from AchievementStatus) (*AchievementTransition, error) {
+	for i := range AchievementTransitions {
+		t := &AchievementTransitions[i]
+		if t.Action == action && t.From == from {
+			return t, nil
+		}
+	}
+	return nil, ErrAchievementTransitionNotAllowed
+}
+
+// AchievementActionSources status asal yang diizinkan untuk aksi, urut sesuai tabel
+func AchievementActionSources(action AchievementAction) []AchievementStatus {
+	var sources []AchievementStatus
+	for _, t := range AchievementTransitions {
+		if t.Action == action {
+			sources = append(sources, t.From)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AchievementStatusHistory struct {
+	ID                     uuid.UUID  `json:"id"`
+	AchievementReferenceID uuid.UUID  `json:"achievement_reference_id"`
+	FromStatus             *string    `json:"from_status"`
+	ToStatus               string     `json:"to_status"`
+	ActorID                *uuid.UUID `json:"actor_id"`
+	ActorName              *string    `json:"actor_name"`
+	ActorRole              string     `json:"actor_role"`
+	Note                   *string    `json:"note"`
+	Stage                  *int       `json:"stage"`
+	CreatedAt              time.Time  `json:"created_at"`
+}
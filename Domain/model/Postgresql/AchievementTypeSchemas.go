@@ -0,0 +1,15 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AchievementTypeSchemas struct {
+	AchievementType    string          `json:"achievement_type"`
+	CustomFieldsSchema json.RawMessage `json:"custom_fields_schema"`
+	UpdatedBy          *uuid.UUID      `json:"updated_by"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApprovalWorkflows struct {
+	ID               uuid.UUID       `json:"id"`
+	AchievementType  string          `json:"achievement_type"`
+	CompetitionLevel *string         `json:"competition_level"`
+	Name             string          `json:"name"`
+	Stages           []ApprovalStage `json:"stages"`
+	UpdatedBy        *uuid.UUID      `json:"updated_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+type ApprovalStage struct {
+	Order              int    `json:"order"`
+	Name               string `json:"name"`
+	RequiredPermission string `json:"required_permission"`
+	AdvisorOnly        bool   `json:"advisor_only"`
+}
+
+// DefaultApprovalStages alur bawaan tanpa workflow: cukup diverifikasi dosen wali
+var DefaultApprovalStages = []ApprovalStage{
+	{Order: 1, Name: "Dosen Wali", RequiredPermission: "verify_achievements", AdvisorOnly: true},
+}
+
+// ErrApprovalStageOutOfRange current_stage tidak ada di tahap workflow reference
+var ErrApprovalStageOutOfRange = errors.New("tahap persetujuan tidak ada di workflow")
+
+// ResolveApprovalStage mengambil tahap ke-currentStage (mulai dari 1) dan apakah tahap itu yang terakhir
+// Tahap di luar batas tidak pernah dianggap tahap terakhir agar persetujuan tidak melompati tahap.
+func ResolveApprovalStage(stages []ApprovalStage, currentStage int) (*ApprovalStage, bool, error) {
+	if currentStage < 1 || currentStage > len(stages) {
+		return nil, false, ErrApprovalStageOutOfRange
+	}
+	stage := stages[currentStage-1]
+	return &stage, currentStage == len(stages), nil
+}
+
+type ApprovalWorkflowInput struct {
+	AchievementType  string               `json:"achievement_type"`
+	CompetitionLevel *string              `json:"competition_level"`
+	Name             string               `json:"name"`
+	Stages           []ApprovalStageInput `json:"stages"`
+}
+
+type ApprovalStageInput struct {
+	Name               string `json:"name"`
+	RequiredPermission string `json:"required_permission"`
+	AdvisorOnly        bool   `json:"advisor_only"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Impersonations struct {
+	ID                   uuid.UUID  `json:"id"`
+	ImpersonatorID       uuid.UUID  `json:"impersonator_id"`
+	ImpersonatorUsername string     `json:"impersonator_username"`
+	TargetUserID         uuid.UUID  `json:"target_user_id"`
+	TargetUsername       string     `json:"target_username"`
+	Reason               string     `json:"reason"`
+	IPAddress            string     `json:"ip_address"`
+	UserAgent            string     `json:"user_agent"`
+	StartedAt            time.Time  `json:"started_at"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	EndedAt              *time.Time `json:"ended_at"`
+	EndReason            *string    `json:"end_reason"`
+}
+
+type ImpersonationEvents struct {
+	ID              uuid.UUID `json:"id"`
+	ImpersonationID uuid.UUID `json:"impersonation_id"`
+	Event           string    `json:"event"`
+	Method          string    `json:"method"`
+	Path            string    `json:"path"`
+	IPAddress       string    `json:"ip_address"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type ImpersonateRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
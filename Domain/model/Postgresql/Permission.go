@@ -9,3 +9,10 @@ type Permissions struct {
 	Action      string    `json:"action"`
 	Description string    `json:"description"`
 }
+
+type PermissionRequest struct {
+	Name        string `json:"name"`
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PointRuleSets struct {
+	ID          uuid.UUID    `json:"id"`
+	Version     int          `json:"version"`
+	Description string       `json:"description"`
+	IsActive    bool         `json:"is_active"`
+	CreatedBy   *uuid.UUID   `json:"created_by"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ActivatedAt *time.Time   `json:"activated_at"`
+	RuleCount   int          `json:"rule_count"`
+	Rules       []PointRules `json:"rules,omitempty"`
+}
+
+type PointRules struct {
+	ID              uuid.UUID         `json:"id"`
+	RuleSetID       uuid.UUID         `json:"rule_set_id"`
+	AchievementType string            `json:"achievement_type"`
+	Conditions      map[string]string `json:"conditions"`
+	Points          int               `json:"points"`
+	Description     string            `json:"description"`
+}
+
+type CreatePointRuleSetRequest struct {
+	Description string           `json:"description"`
+	Activate    bool             `json:"activate"`
+	Rules       []PointRuleInput `json:"rules"`
+}
+
+type PointRuleInput struct {
+	AchievementType string            `json:"achievement_type"`
+	Conditions      map[string]string `json:"conditions"`
+	Points          int               `json:"points"`
+	Description     string            `json:"description"`
+}
+
+type RecomputePointsRequest struct {
+	Version int `json:"version"` // 0 = versi aktif
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewSLAs struct {
+	ID               uuid.UUID  `json:"id"`
+	AchievementType  string     `json:"achievement_type"`
+	CompetitionLevel *string    `json:"competition_level"`
+	DueDays          int        `json:"due_days"`
+	EscalationEmail  *string    `json:"escalation_email"`
+	BackupReviewerID *uuid.UUID `json:"backup_reviewer_id"` // lecturers.id
+	UpdatedBy        *uuid.UUID `json:"updated_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type ReviewSLAInput struct {
+	AchievementType  string     `json:"achievement_type"`
+	CompetitionLevel *string    `json:"competition_level"`
+	DueDays          int        `json:"due_days"`
+	EscalationEmail  *string    `json:"escalation_email"`
+	BackupReviewerID *uuid.UUID `json:"backup_reviewer_id"`
+}
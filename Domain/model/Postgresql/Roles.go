@@ -7,8 +7,18 @@ import (
 )
 
 type Roles struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name"`
+	Description        string    `json:"description"`
+	PermissionsVersion int64     `json:"permissions_version"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type RoleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermissionRequest struct {
+	PermissionID string `json:"permission_id"`
 }
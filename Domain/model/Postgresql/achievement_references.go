@@ -7,14 +7,23 @@ import (
 )
 
 type AchievementReferences struct {
-	ID                 uuid.UUID  `json:"id"`
-	StudentID          uuid.UUID  `json:"student_id"`
-	MongoAchievementID string     `json:"mongo_achievement_id"`
-	Status             string     `json:"status"`
-	SubmittedAt        *time.Time `json:"submitted_at"`
-	VerifiedAt         *time.Time `json:"verified_at"`
-	VerifiedBy         *uuid.UUID `json:"verified_by"`
-	RejectionNote      *string    `json:"rejection_note"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
-}
\ No newline at end of file
+	ID                 uuid.UUID         `json:"id"`
+	StudentID          uuid.UUID         `json:"student_id"`
+	MongoAchievementID string            `json:"mongo_achievement_id"`
+	Status             AchievementStatus `json:"status"`
+	SubmittedAt        *time.Time        `json:"submitted_at"`
+	VerifiedAt         *time.Time        `json:"verified_at"`
+	VerifiedBy         *uuid.UUID        `json:"verified_by"`
+	RejectionNote      *string           `json:"rejection_note"`
+	WorkflowID         *uuid.UUID        `json:"workflow_id"`
+	CurrentStage       int               `json:"current_stage"`
+	RevokedAt          *time.Time        `json:"revoked_at"`
+	RevokedBy          *uuid.UUID        `json:"revoked_by"`
+	RevocationReason   *string           `json:"revocation_reason"`
+	ReviewDueAt        *time.Time        `json:"review_due_at"`
+	ReviewSLAID        *uuid.UUID        `json:"review_sla_id"`
+	EscalatedAt        *time.Time        `json:"escalated_at"`
+	EscalatedTo        *uuid.UUID        `json:"escalated_to"` // lecturers.id dosen pengganti
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
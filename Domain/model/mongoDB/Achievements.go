@@ -18,6 +18,7 @@ type Achievement struct {
 	Attachments     []Attachment       `bson:"attachments" json:"attachments"`
 	Tags            []string           `bson:"tags" json:"tags"`
 	Points          int                `bson:"points" json:"points"`
+	PointsVersion   int                `bson:"pointsVersion,omitempty" json:"pointsVersion,omitempty"` // versi rule set poin yang dipakai
 	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
 	DeletedAt       *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
@@ -61,8 +62,27 @@ type Period struct {
 }
 
 type Attachment struct {
+	ID         string    `bson:"id,omitempty" json:"id,omitempty"`
 	FileName   string    `bson:"fileName" json:"fileName"`
 	FileUrl    string    `bson:"fileUrl" json:"fileUrl"`
 	FileType   string    `bson:"fileType" json:"fileType"`
+	Size       int64     `bson:"size,omitempty" json:"size,omitempty"`
+	SHA256     string    `bson:"sha256,omitempty" json:"sha256,omitempty"`
+	StorageKey string    `bson:"storageKey,omitempty" json:"-"` // lokasi file di FileStorage, tidak diekspos
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
+
+	// DownloadUrl link download bertanda tangan dengan masa berlaku; hanya diisi di response
+	DownloadUrl string `bson:"-" json:"downloadUrl,omitempty"`
+}
+
+// UpdateAchievementRequest body untuk edit prestasi oleh mahasiswa
+// UpdatedAt wajib berisi nilai updatedAt terakhir yang dibaca client (optimistic concurrency)
+type UpdateAchievementRequest struct {
+	AchievementType string             `json:"achievementType"`
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	Details         AchievementDetails `json:"details"`
+	CustomFields    map[string]any     `json:"customFields,omitempty"`
+	Tags            []string           `json:"tags"`
+	UpdatedAt       *time.Time         `json:"updatedAt"`
 }
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// insertAchievementComment menyimpan satu komentar lewat *sql.DB atau *sql.Tx
+func insertAchievementComment(exec interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, comment *model.AchievementComments) error {
+	comment.ID = uuid.New()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+	if comment.AttachmentIDs == nil {
+		comment.AttachmentIDs = []string{}
+	}
+
+	attachmentIDs, err := json.Marshal(comment.AttachmentIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = exec.Exec(`
+		INSERT INTO achievement_comments
+		(id, achievement_reference_id, parent_id, author_id, author_role, kind, body, attachment_ids, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, comment.ID, comment.AchievementReferenceID, comment.ParentID, comment.AuthorID,
+		comment.AuthorRole, comment.Kind, comment.Body, attachmentIDs, comment.CreatedAt)
+
+	return err
+}
+
+// CreateAchievementComment menyimpan komentar baru
+func CreateAchievementComment(comment *model.AchievementComments) error {
+	return insertAchievementComment(config.DB, comment)
+}
+
+// GetAchievementCommentReferenceID mengambil reference id dari komentar (untuk validasi parent_id)
+func GetAchievementCommentReferenceID(commentID uuid.UUID) (uuid.UUID, error) {
+	var referenceID uuid.UUID
+	err := config.DB.QueryRow(`
+		SELECT achievement_reference_id FROM achievement_comments WHERE id = $1
+	`, commentID).Scan(&referenceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errors.New("komentar tidak ditemukan")
+		}
+		return uuid.Nil, err
+	}
+	return referenceID, nil
+}
+
+// GetAchievementComments mengambil thread komentar achievement, urut dari yang paling lama
+func GetAchievementComments(referenceID uuid.UUID) ([]model.AchievementComments, error) {
+	rows, err := config.DB.Query(`
+		SELECT c.id, c.achievement_reference_id, c.parent_id, c.author_id, u.full_name,
+		       c.author_role, c.kind, c.body, c.attachment_ids, c.created_at
+		FROM achievement_comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.achievement_reference_id = $1
+		ORDER BY c.created_at ASC, c.id ASC
+	`, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComments{}
+	for rows.Next() {
+		var comment model.AchievementComments
+		var attachmentIDs []byte
+		err := rows.Scan(
+			&comment.ID,
+			&comment.AchievementReferenceID,
+			&comment.ParentID,
+			&comment.AuthorID,
+			&comment.AuthorName,
+			&comment.AuthorRole,
+			&comment.Kind,
+			&comment.Body,
+			&attachmentIDs,
+			&comment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(attachmentIDs, &comment.AttachmentIDs); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
@@ -11,8 +11,9 @@ import (
 	"github.com/google/uuid"
 )
 
-// CreateAchievementReference menyimpan reference ke PostgreSQL
-func CreateAchievementReference(ref *model.AchievementReferences) error {
+// CreateAchievementReference menyimpan reference ke PostgreSQL beserta status awalnya
+// di achievement_status_history dalam satu transaksi
+func CreateAchievementReference(ref *model.AchievementReferences, history *model.AchievementStatusHistory) error {
 	query := `
 		INSERT INTO achievement_references 
 		(id, student_id, mongo_achievement_id, status, submitted_at, created_at, updated_at)
@@ -21,10 +22,17 @@ func CreateAchievementReference(ref *model.AchievementReferences) error {
 
 	now := time.Now()
 	ref.ID = uuid.New()
+	ref.CurrentStage = 1
 	ref.CreatedAt = now
 	ref.UpdatedAt = now
 
-	_, err := config.DB.Exec(
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
 		query,
 		ref.ID,
 		ref.StudentID,
@@ -34,8 +42,18 @@ func CreateAchievementReference(ref *model.AchievementReferences) error {
 		ref.CreatedAt,
 		ref.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	history.AchievementReferenceID = ref.ID
+	history.ToStatus = string(ref.Status)
+	history.CreatedAt = now
+	if err := insertAchievementStatusHistoryTx(tx, history); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetAchievementReferenceByID mengambil reference berdasarkan ID
@@ -43,8 +61,9 @@ func GetAchievementReferenceByID(id uuid.UUID) (*model.AchievementReferences, er
 	var ref model.AchievementReferences
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
-		       submitted_at, verified_at, verified_by, rejection_note,
-		       created_at, updated_at
+		       submitted_at, verified_at, verified_by, rejection_note, workflow_id, current_stage,
+		       revoked_at, revoked_by, revocation_reason,
+		       review_due_at, review_sla_id, escalated_at, escalated_to, created_at, updated_at
 		FROM achievement_references
 		WHERE id = $1
 	`
@@ -58,6 +77,15 @@ func GetAchievementReferenceByID(id uuid.UUID) (*model.AchievementReferences, er
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
 		&ref.RejectionNote,
+		&ref.WorkflowID,
+		&ref.CurrentStage,
+		&ref.RevokedAt,
+		&ref.RevokedBy,
+		&ref.RevocationReason,
+		&ref.ReviewDueAt,
+		&ref.ReviewSLAID,
+		&ref.EscalatedAt,
+		&ref.EscalatedTo,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -77,8 +105,9 @@ func GetAchievementReferenceByMongoID(mongoID string) (*model.AchievementReferen
 	var ref model.AchievementReferences
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
-		       submitted_at, verified_at, verified_by, rejection_note,
-		       created_at, updated_at
+		       submitted_at, verified_at, verified_by, rejection_note, workflow_id, current_stage,
+		       revoked_at, revoked_by, revocation_reason,
+		       review_due_at, review_sla_id, escalated_at, escalated_to, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = $1
 	`
@@ -92,6 +121,15 @@ func GetAchievementReferenceByMongoID(mongoID string) (*model.AchievementReferen
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
 		&ref.RejectionNote,
+		&ref.WorkflowID,
+		&ref.CurrentStage,
+		&ref.RevokedAt,
+		&ref.RevokedBy,
+		&ref.RevocationReason,
+		&ref.ReviewDueAt,
+		&ref.ReviewSLAID,
+		&ref.EscalatedAt,
+		&ref.EscalatedTo,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -106,48 +144,116 @@ func GetAchievementReferenceByMongoID(mongoID string) (*model.AchievementReferen
 	return &ref, nil
 }
 
-// UpdateAchievementReferenceStatus update status reference
-func UpdateAchievementReferenceStatus(id uuid.UUID, status string) error {
-	query := `
-		UPDATE achievement_references
-		SET status = $1, updated_at = $2
-		WHERE id = $3
-	`
-
-	_, err := config.DB.Exec(query, status, time.Now(), id)
-	return err
-}
-
-// UpdateAchievementReference update full reference data
-func UpdateAchievementReference(ref *model.AchievementReferences) error {
+// TransitionAchievementReference menyimpan transisi status dengan compare-and-set: UPDATE hanya
+// berlaku jika status dan tahap di database masih fromStatus/fromStage, sehingga dari dua request
+// bersamaan hanya satu yang berhasil. Riwayat status (dan komentar, jika ada) ditulis dalam
+// transaksi yang sama. Mengembalikan false jika reference sudah diubah request lain.
+func TransitionAchievementReference(ref *model.AchievementReferences, fromStatus model.AchievementStatus, fromStage int, history *model.AchievementStatusHistory, comment *model.AchievementComments) (bool, error) {
 	query := `
 		UPDATE achievement_references
 		SET status = $1, submitted_at = $2, verified_at = $3, 
-		    verified_by = $4, rejection_note = $5, updated_at = $6
-		WHERE id = $7
+		    verified_by = $4, rejection_note = $5, updated_at = $6,
+		    workflow_id = $7, current_stage = $8,
+		    revoked_at = $9, revoked_by = $10, revocation_reason = $11,
+		    review_due_at = $12, review_sla_id = $13, escalated_at = $14, escalated_to = $15
+		WHERE id = $16 AND status = $17 AND current_stage = $18
 	`
 
-	ref.UpdatedAt = time.Now()
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
 
-	_, err := config.DB.Exec(
+	now := time.Now()
+	result, err := tx.Exec(
 		query,
 		ref.Status,
 		ref.SubmittedAt,
 		ref.VerifiedAt,
 		ref.VerifiedBy,
 		ref.RejectionNote,
-		ref.UpdatedAt,
+		now,
+		ref.WorkflowID,
+		ref.CurrentStage,
+		ref.RevokedAt,
+		ref.RevokedBy,
+		ref.RevocationReason,
+		ref.ReviewDueAt,
+		ref.ReviewSLAID,
+		ref.EscalatedAt,
+		ref.EscalatedTo,
 		ref.ID,
+		fromStatus,
+		fromStage,
 	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if comment != nil {
+		comment.AchievementReferenceID = ref.ID
+		comment.CreatedAt = now
+		if err := insertAchievementComment(tx, comment); err != nil {
+			return false, err
+		}
+	}
+
+	history.AchievementReferenceID = ref.ID
+	history.ToStatus = string(ref.Status)
+	history.CreatedAt = now
+	if err := insertAchievementStatusHistoryTx(tx, history); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	ref.UpdatedAt = now
+	return true, nil
+}
+
+// DeleteAchievementReference menghapus reference dari PostgreSQL jika statusnya masih status
+// (compare-and-set). Mengembalikan false jika status sudah berubah.
+func DeleteAchievementReference(id uuid.UUID, status model.AchievementStatus) (bool, error) {
+	query := `DELETE FROM achievement_references WHERE id = $1 AND status = $2`
+	result, err := config.DB.Exec(query, id, status)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// AchievementReferenceRepository interface penyimpanan transisi status reference
+type AchievementReferenceRepository interface {
+	TransitionAchievementReference(ref *model.AchievementReferences, fromStatus model.AchievementStatus, fromStage int, history *model.AchievementStatusHistory, comment *model.AchievementComments) (bool, error)
+	DeleteAchievementReference(id uuid.UUID, status model.AchievementStatus) (bool, error)
+}
+
+// PostgresAchievementReferences implementasi AchievementReferenceRepository dengan PostgreSQL
+type PostgresAchievementReferences struct{}
 
-	return err
+// TransitionAchievementReference lihat TransitionAchievementReference
+func (PostgresAchievementReferences) TransitionAchievementReference(ref *model.AchievementReferences, fromStatus model.AchievementStatus, fromStage int, history *model.AchievementStatusHistory, comment *model.AchievementComments) (bool, error) {
+	return TransitionAchievementReference(ref, fromStatus, fromStage, history, comment)
 }
 
-// DeleteAchievementReference menghapus reference dari PostgreSQL
-func DeleteAchievementReference(id uuid.UUID) error {
-	query := `DELETE FROM achievement_references WHERE id = $1`
-	_, err := config.DB.Exec(query, id)
-	return err
+// DeleteAchievementReference lihat DeleteAchievementReference
+func (PostgresAchievementReferences) DeleteAchievementReference(id uuid.UUID, status model.AchievementStatus) (bool, error) {
+	return DeleteAchievementReference(id, status)
 }
 
 // GetAchievementReferencesByStudentIDs mengambil references berdasarkan list student IDs dengan pagination
@@ -157,8 +263,9 @@ func GetAchievementReferencesByStudentIDs(studentIDs []uuid.UUID, limit, offset
 	// Build query with ANY clause untuk array
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
-		       submitted_at, verified_at, verified_by, rejection_note,
-		       created_at, updated_at
+		       submitted_at, verified_at, verified_by, rejection_note, workflow_id, current_stage,
+		       revoked_at, revoked_by, revocation_reason,
+		       review_due_at, review_sla_id, escalated_at, escalated_to, created_at, updated_at
 		FROM achievement_references
 		WHERE student_id = ANY($1)
 		ORDER BY created_at DESC
@@ -188,6 +295,15 @@ func GetAchievementReferencesByStudentIDs(studentIDs []uuid.UUID, limit, offset
 			&ref.VerifiedAt,
 			&ref.VerifiedBy,
 			&ref.RejectionNote,
+			&ref.WorkflowID,
+			&ref.CurrentStage,
+			&ref.RevokedAt,
+			&ref.RevokedBy,
+			&ref.RevocationReason,
+			&ref.ReviewDueAt,
+			&ref.ReviewSLAID,
+			&ref.EscalatedAt,
+			&ref.EscalatedTo,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
@@ -227,6 +343,15 @@ func GetAllAchievementReferences(StudentID uuid.UUID) ([]model.AchievementRefere
             verified_at, 
             verified_by, 
             rejection_note, 
+            workflow_id, 
+            current_stage, 
+            revoked_at, 
+            revoked_by, 
+            revocation_reason, 
+            review_due_at, 
+            review_sla_id, 
+            escalated_at, 
+            escalated_to, 
             created_at, 
             updated_at 
         FROM achievement_references 
@@ -256,6 +381,15 @@ func GetAllAchievementReferences(StudentID uuid.UUID) ([]model.AchievementRefere
 			&ref.VerifiedAt,    // Pointer otomatis menangani NULL
 			&ref.VerifiedBy,    // Pointer otomatis menangani NULL
 			&ref.RejectionNote, // Pointer otomatis menangani NULL
+			&ref.WorkflowID,
+			&ref.CurrentStage,
+			&ref.RevokedAt,
+			&ref.RevokedBy,
+			&ref.RevocationReason,
+			&ref.ReviewDueAt,
+			&ref.ReviewSLAID,
+			&ref.EscalatedAt,
+			&ref.EscalatedTo,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
@@ -282,8 +416,9 @@ func GetAllAchievementReferencesWithFilters(limit, offset int, status, studentID
 	// Build query dengan filters
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
-		       submitted_at, verified_at, verified_by, rejection_note,
-		       created_at, updated_at
+		       submitted_at, verified_at, verified_by, rejection_note, workflow_id, current_stage,
+		       revoked_at, revoked_by, revocation_reason,
+		       review_due_at, review_sla_id, escalated_at, escalated_to, created_at, updated_at
 		FROM achievement_references
 		WHERE 1=1
 	`
@@ -359,6 +494,15 @@ func GetAllAchievementReferencesWithFilters(limit, offset int, status, studentID
 			&ref.VerifiedAt,
 			&ref.VerifiedBy,
 			&ref.RejectionNote,
+			&ref.WorkflowID,
+			&ref.CurrentStage,
+			&ref.RevokedAt,
+			&ref.RevokedBy,
+			&ref.RevocationReason,
+			&ref.ReviewDueAt,
+			&ref.ReviewSLAID,
+			&ref.EscalatedAt,
+			&ref.EscalatedTo,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
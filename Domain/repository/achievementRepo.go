@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // CreateAchievement menyimpan achievement ke MongoDB
@@ -68,6 +69,44 @@ func UpdateAchievement(id primitive.ObjectID, achievement *mongodb.Achievement)
 	return err
 }
 
+// UpdateAchievementIfUnmodified update konten achievement hanya jika updatedAt masih sama
+// dengan yang dibaca client. Mengembalikan false jika dokumen sudah diubah (atau dihapus)
+// oleh request lain sehingga penyimpanan kedua ditolak.
+func UpdateAchievementIfUnmodified(id primitive.ObjectID, achievement *mongodb.Achievement, expectedUpdatedAt time.Time) (bool, error) {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// MongoDB menyimpan waktu dengan presisi milidetik
+	achievement.UpdatedAt = time.Now().Truncate(time.Millisecond)
+
+	result, err := collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":       id,
+			"updatedAt": expectedUpdatedAt,
+			"deletedAt": bson.M{"$exists": false},
+		},
+		bson.M{
+			"$set": bson.M{
+				"achievementType": achievement.AchievementType,
+				"title":           achievement.Title,
+				"description":     achievement.Description,
+				"details":         achievement.Details,
+				"customFields":    achievement.CustomFields,
+				"tags":            achievement.Tags,
+				"updatedAt":       achievement.UpdatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
 // DeleteAchievement menghapus achievement dari MongoDB (hard delete)
 func DeleteAchievement(id primitive.ObjectID) error {
 	collection := config.GetMongoDB().Collection("achievements")
@@ -286,7 +325,7 @@ func GetCompetitionLevelDistribution(mongoIDs []string) (map[string]int, error)
 		},
 		{
 			"$group": bson.M{
-				"_id":   "$details.level",
+				"_id":   "$details.competitionLevel",
 				"count": bson.M{"$sum": 1},
 			},
 		},
@@ -315,3 +354,134 @@ func GetCompetitionLevelDistribution(mongoIDs []string) (map[string]int, error)
 
 	return stats, nil
 }
+
+// AddAchievementAttachment menambahkan metadata lampiran ke achievement yang belum dihapus
+// Mengembalikan false jika achievement tidak ditemukan
+func AddAchievementAttachment(id primitive.ObjectID, attachment mongodb.Attachment) (bool, error) {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}},
+		bson.M{
+			"$push": bson.M{"attachments": attachment},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// RemoveAchievementAttachment menghapus metadata lampiran berdasarkan id lampiran
+// Mengembalikan false jika lampiran tidak ada di achievement
+func RemoveAchievementAttachment(id primitive.ObjectID, attachmentID string) (bool, error) {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "attachments.id": attachmentID},
+		bson.M{
+			"$pull": bson.M{"attachments": bson.M{"id": attachmentID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// SetAchievementPoints menyimpan poin hasil perhitungan beserta versi rule set yang dipakai
+// updatedAt tidak diubah karena poin bukan konten yang diedit mahasiswa
+func SetAchievementPoints(id primitive.ObjectID, points, version int) error {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"points": points, "pointsVersion": version}},
+	)
+
+	return err
+}
+
+// AchievementDocumentRepository interface dokumen achievement yang dipakai transisi status dan poin
+type AchievementDocumentRepository interface {
+	GetAchievementByID(id primitive.ObjectID) (*mongodb.Achievement, error)
+	SetAchievementPoints(id primitive.ObjectID, points, version int) error
+}
+
+// MongoAchievementDocuments implementasi AchievementDocumentRepository dengan MongoDB
+type MongoAchievementDocuments struct{}
+
+// GetAchievementByID lihat GetAchievementByID
+func (MongoAchievementDocuments) GetAchievementByID(id primitive.ObjectID) (*mongodb.Achievement, error) {
+	return GetAchievementByID(id)
+}
+
+// SetAchievementPoints lihat SetAchievementPoints
+func (MongoAchievementDocuments) SetAchievementPoints(id primitive.ObjectID, points, version int) error {
+	return SetAchievementPoints(id, points, version)
+}
+
+// EachAchievementTaxonomy memanggil fn untuk setiap achievement yang memiliki details.competitionLevel atau details.medalType
+// Hanya _id dan kedua field tersebut yang dimuat.
+func EachAchievementTaxonomy(fn func(achievement *mongodb.Achievement) error) error {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx := context.Background()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"details.competitionLevel": bson.M{"$exists": true}},
+			{"details.medalType": bson.M{"$exists": true}},
+		},
+	}
+	projection := bson.M{"_id": 1, "details.competitionLevel": 1, "details.medalType": 1}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var achievement mongodb.Achievement
+		if err := cursor.Decode(&achievement); err != nil {
+			return err
+		}
+		if err := fn(&achievement); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// SetAchievementDetailFields mengganti nilai beberapa field details (tanpa mengubah updatedAt)
+func SetAchievementDetailFields(id primitive.ObjectID, fields map[string]string) error {
+	collection := config.GetMongoDB().Collection("achievements")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set := bson.M{}
+	for field, value := range fields {
+		set["details."+field] = value
+	}
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// insertAchievementStatusHistoryTx mencatat satu transisi status di dalam transaksi
+func insertAchievementStatusHistoryTx(tx *sql.Tx, entry *model.AchievementStatusHistory) error {
+	entry.ID = uuid.New()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO achievement_status_history
+		(id, achievement_reference_id, from_status, to_status, actor_id, actor_role, note, stage, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, entry.ID, entry.AchievementReferenceID, entry.FromStatus, entry.ToStatus,
+		entry.ActorID, entry.ActorRole, entry.Note, entry.Stage, entry.CreatedAt)
+
+	return err
+}
+
+// GetAchievementStatusHistory mengambil riwayat status achievement, urut dari yang paling lama
+func GetAchievementStatusHistory(referenceID uuid.UUID) ([]model.AchievementStatusHistory, error) {
+	rows, err := config.DB.Query(`
+		SELECT h.id, h.achievement_reference_id, h.from_status, h.to_status,
+		       h.actor_id, u.full_name, h.actor_role, h.note, h.stage, h.created_at
+		FROM achievement_status_history h
+		LEFT JOIN users u ON u.id = h.actor_id
+		WHERE h.achievement_reference_id = $1
+		ORDER BY h.created_at ASC, h.id ASC
+	`, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.AchievementStatusHistory{}
+	for rows.Next() {
+		var entry model.AchievementStatusHistory
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AchievementReferenceID,
+			&entry.FromStatus,
+			&entry.ToStatus,
+			&entry.ActorID,
+			&entry.ActorName,
+			&entry.ActorRole,
+			&entry.Note,
+			&entry.Stage,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+)
+
+// Permission untuk mengelola schema custom field tipe prestasi
+const ManageAchievementTypesPermission = "manage_achievement_types"
+
+// GetAchievementTypeSchemas mengambil semua schema custom field, dikelompokkan per tipe
+func GetAchievementTypeSchemas() (map[string]*model.AchievementTypeSchemas, error) {
+	rows, err := config.DB.Query(`
+		SELECT achievement_type, custom_fields_schema, updated_by, updated_at
+		FROM achievement_type_schemas
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schemas := make(map[string]*model.AchievementTypeSchemas)
+	for rows.Next() {
+		var schema model.AchievementTypeSchemas
+		var raw []byte
+		err := rows.Scan(&schema.AchievementType, &raw, &schema.UpdatedBy, &schema.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		schema.CustomFieldsSchema = raw
+		schemas[schema.AchievementType] = &schema
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+// GetAchievementTypeSchema mengambil schema custom field satu tipe; nil jika belum ada
+func GetAchievementTypeSchema(achievementType string) (*model.AchievementTypeSchemas, error) {
+	var schema model.AchievementTypeSchemas
+	var raw []byte
+	err := config.DB.QueryRow(`
+		SELECT achievement_type, custom_fields_schema, updated_by, updated_at
+		FROM achievement_type_schemas
+		WHERE achievement_type = $1
+	`, achievementType).Scan(&schema.AchievementType, &raw, &schema.UpdatedBy, &schema.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	schema.CustomFieldsSchema = raw
+	return &schema, nil
+}
+
+// UpsertAchievementTypeSchema menyimpan (atau mengganti) schema custom field satu tipe
+func UpsertAchievementTypeSchema(schema *model.AchievementTypeSchemas) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO achievement_type_schemas (achievement_type, custom_fields_schema, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (achievement_type) DO UPDATE
+		SET custom_fields_schema = EXCLUDED.custom_fields_schema,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = EXCLUDED.updated_at
+	`, schema.AchievementType, []byte(schema.CustomFieldsSchema), schema.UpdatedBy, schema.UpdatedAt)
+	return err
+}
+
+// DeleteAchievementTypeSchema menghapus schema custom field satu tipe; false jika memang tidak ada
+func DeleteAchievementTypeSchema(achievementType string) (bool, error) {
+	result, err := config.DB.Exec(`DELETE FROM achievement_type_schemas WHERE achievement_type = $1`, achievementType)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,431 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission untuk mengelola workflow persetujuan prestasi
+const ManageApprovalWorkflowsPermission = "manage_approval_workflows"
+
+var (
+	ErrApprovalWorkflowExists   = errors.New("workflow untuk tipe dan tingkat ini sudah ada")
+	ErrApprovalWorkflowNotFound = errors.New("workflow tidak ditemukan")
+	ErrApprovalWorkflowInUse    = errors.New("workflow masih dipakai prestasi yang sedang direview")
+)
+
+// GetApprovalWorkflows mengambil semua workflow beserta tahapnya
+func GetApprovalWorkflows() ([]model.ApprovalWorkflows, error) {
+	rows, err := config.DB.Query(`
+		SELECT id, achievement_type, competition_level, name, updated_by, created_at, updated_at
+		FROM approval_workflows
+		ORDER BY achievement_type ASC, competition_level ASC NULLS FIRST
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workflows := []model.ApprovalWorkflows{}
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var w model.ApprovalWorkflows
+		err := rows.Scan(&w.ID, &w.AchievementType, &w.CompetitionLevel, &w.Name, &w.UpdatedBy, &w.CreatedAt, &w.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		w.Stages = []model.ApprovalStage{}
+		index[w.ID] = len(workflows)
+		workflows = append(workflows, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stageRows, err := config.DB.Query(`
+		SELECT workflow_id, stage_order, name, required_permission, advisor_only
+		FROM approval_workflow_stages
+		ORDER BY workflow_id, stage_order ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer stageRows.Close()
+
+	for stageRows.Next() {
+		var workflowID uuid.UUID
+		var stage model.ApprovalStage
+		if err := stageRows.Scan(&workflowID, &stage.Order, &stage.Name, &stage.RequiredPermission, &stage.AdvisorOnly); err != nil {
+			return nil, err
+		}
+		if i, ok := index[workflowID]; ok {
+			workflows[i].Stages = append(workflows[i].Stages, stage)
+		}
+	}
+	if err = stageRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return workflows, nil
+}
+
+// GetApprovalWorkflowByID mengambil workflow beserta tahapnya
+func GetApprovalWorkflowByID(id uuid.UUID) (*model.ApprovalWorkflows, error) {
+	var w model.ApprovalWorkflows
+	err := config.DB.QueryRow(`
+		SELECT id, achievement_type, competition_level, name, updated_by, created_at, updated_at
+		FROM approval_workflows
+		WHERE id = $1
+	`, id).Scan(&w.ID, &w.AchievementType, &w.CompetitionLevel, &w.Name, &w.UpdatedBy, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrApprovalWorkflowNotFound
+		}
+		return nil, err
+	}
+
+	w.Stages, err = getApprovalWorkflowStages(id)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// FindApprovalWorkflow mencari workflow yang berlaku untuk tipe dan tingkat kompetisi prestasi
+// Workflow dengan tingkat yang sama didahulukan dari workflow untuk semua tingkat.
+// Mengembalikan nil, nil jika tidak ada (alur bawaan dosen wali).
+func FindApprovalWorkflow(achievementType string, competitionLevel *string) (*model.ApprovalWorkflows, error) {
+	var id uuid.UUID
+	err := config.DB.QueryRow(`
+		SELECT id FROM approval_workflows
+		WHERE achievement_type = $1 AND (competition_level IS NULL OR competition_level = $2)
+		ORDER BY competition_level IS NULL ASC
+		LIMIT 1
+	`, achievementType, competitionLevel).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return GetApprovalWorkflowByID(id)
+}
+
+// getApprovalWorkflowStages mengambil tahap workflow berurutan
+func getApprovalWorkflowStages(workflowID uuid.UUID) ([]model.ApprovalStage, error) {
+	return getApprovalWorkflowStagesTx(config.DB, workflowID)
+}
+
+// getApprovalWorkflowStagesTx mengambil tahap workflow lewat *sql.DB atau *sql.Tx
+func getApprovalWorkflowStagesTx(db interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, workflowID uuid.UUID) ([]model.ApprovalStage, error) {
+	rows, err := db.Query(`
+		SELECT stage_order, name, required_permission, advisor_only
+		FROM approval_workflow_stages
+		WHERE workflow_id = $1
+		ORDER BY stage_order ASC
+	`, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := []model.ApprovalStage{}
+	for rows.Next() {
+		var stage model.ApprovalStage
+		if err := rows.Scan(&stage.Order, &stage.Name, &stage.RequiredPermission, &stage.AdvisorOnly); err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stages, nil
+}
+
+// GetCurrentApprovalStage mengambil tahap persetujuan reference saat ini dan apakah tahap itu yang terakhir
+// Reference tanpa workflow memakai DefaultApprovalStages. current_stage di luar tahap workflow
+// menghasilkan model.ErrApprovalStageOutOfRange.
+func GetCurrentApprovalStage(ref *model.AchievementReferences) (*model.ApprovalStage, bool, error) {
+	stages := model.DefaultApprovalStages
+	if ref.WorkflowID != nil {
+		workflowStages, err := getApprovalWorkflowStages(*ref.WorkflowID)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(workflowStages) > 0 {
+			stages = workflowStages
+		}
+	}
+
+	return model.ResolveApprovalStage(stages, ref.CurrentStage)
+}
+
+// lockApprovalWorkflowTx mengunci workflow dan menghitung prestasi submitted yang memakainya
+// Kunci baris membuat submit yang bersamaan (foreign key workflow_id) menunggu transaksi ini.
+func lockApprovalWorkflowTx(tx *sql.Tx, id uuid.UUID) (int, error) {
+	var locked uuid.UUID
+	err := tx.QueryRow(`SELECT id FROM approval_workflows WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrApprovalWorkflowNotFound
+		}
+		return 0, err
+	}
+
+	var inFlight int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM achievement_references
+		WHERE workflow_id = $1 AND status = 'submitted'
+	`, id).Scan(&inFlight)
+	return inFlight, err
+}
+
+// approvalStagesEqual membandingkan tahap workflow tanpa memperhatikan field Order dari input
+func approvalStagesEqual(current, next []model.ApprovalStage) bool {
+	if len(current) != len(next) {
+		return false
+	}
+	for i := range current {
+		if current[i].Name != next[i].Name ||
+			current[i].RequiredPermission != next[i].RequiredPermission ||
+			current[i].AdvisorOnly != next[i].AdvisorOnly {
+			return false
+		}
+	}
+	return true
+}
+
+// insertApprovalWorkflowStagesTx menyimpan tahap workflow; stage_order mengikuti urutan slice
+func insertApprovalWorkflowStagesTx(tx *sql.Tx, w *model.ApprovalWorkflows) error {
+	for i := range w.Stages {
+		stage := &w.Stages[i]
+		stage.Order = i + 1
+		_, err := tx.Exec(`
+			INSERT INTO approval_workflow_stages (workflow_id, stage_order, name, required_permission, advisor_only)
+			VALUES ($1, $2, $3, $4, $5)
+		`, w.ID, stage.Order, stage.Name, stage.RequiredPermission, stage.AdvisorOnly)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateApprovalWorkflow menyimpan workflow baru beserta tahapnya
+func CreateApprovalWorkflow(w *model.ApprovalWorkflows) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	w.ID = uuid.New()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+
+	_, err = tx.Exec(`
+		INSERT INTO approval_workflows (id, achievement_type, competition_level, name, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, w.ID, w.AchievementType, w.CompetitionLevel, w.Name, w.UpdatedBy, w.CreatedAt, w.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrApprovalWorkflowExists
+		}
+		return err
+	}
+
+	if err := insertApprovalWorkflowStagesTx(tx, w); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateApprovalWorkflow mengganti data dan seluruh tahap workflow
+// Tahap tidak boleh diubah selama ada prestasi submitted yang memakai workflow (ErrApprovalWorkflowInUse),
+// karena nomor tahap prestasi tersebut akan menunjuk tahap lain atau tahap yang sudah tidak ada.
+func UpdateApprovalWorkflow(w *model.ApprovalWorkflows) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	inFlight, err := lockApprovalWorkflowTx(tx, w.ID)
+	if err != nil {
+		return err
+	}
+	if inFlight > 0 {
+		current, err := getApprovalWorkflowStagesTx(tx, w.ID)
+		if err != nil {
+			return err
+		}
+		if !approvalStagesEqual(current, w.Stages) {
+			return ErrApprovalWorkflowInUse
+		}
+	}
+
+	w.UpdatedAt = time.Now()
+	_, err = tx.Exec(`
+		UPDATE approval_workflows
+		SET achievement_type = $1, competition_level = $2, name = $3, updated_by = $4, updated_at = $5
+		WHERE id = $6
+	`, w.AchievementType, w.CompetitionLevel, w.Name, w.UpdatedBy, w.UpdatedAt, w.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrApprovalWorkflowExists
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM approval_workflow_stages WHERE workflow_id = $1`, w.ID); err != nil {
+		return err
+	}
+	if err := insertApprovalWorkflowStagesTx(tx, w); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteApprovalWorkflow menghapus workflow; false jika tidak ada
+// Ditolak dengan ErrApprovalWorkflowInUse selama ada prestasi submitted yang memakainya. Prestasi lain
+// (draft, verified, dll.) kehilangan workflow_id lewat foreign key dan submit ulang memilih workflow baru.
+func DeleteApprovalWorkflow(id uuid.UUID) (bool, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	inFlight, err := lockApprovalWorkflowTx(tx, id)
+	if err != nil {
+		if errors.Is(err, ErrApprovalWorkflowNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if inFlight > 0 {
+		return false, ErrApprovalWorkflowInUse
+	}
+
+	if _, err := tx.Exec(`DELETE FROM approval_workflows WHERE id = $1`, id); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// PendingApproval reference yang menunggu persetujuan pada tahap non-dosen wali, atau tahap
+// dosen wali yang dialihkan ke pemanggil oleh eskalasi review
+type PendingApproval struct {
+	Reference model.AchievementReferences `json:"reference"`
+	Stage     model.ApprovalStage         `json:"stage"`
+}
+
+// pendingApprovalsWhere prestasi submitted yang tahap saat ini membutuhkan salah satu permission ($1),
+// ditambah tahap dosen wali yang dieskalasi ke dosen $2
+const pendingApprovalsWhere = `
+	WHERE r.status = 'submitted' AND (
+	      (s.workflow_id IS NOT NULL AND NOT s.advisor_only AND s.required_permission = ANY($1))
+	   OR (r.escalated_to = $2 AND COALESCE(s.advisor_only, TRUE))
+	)`
+
+// GetPendingApprovals mengambil prestasi yang menunggu persetujuan pemanggil
+// Tahap advisor_only hanya ikut jika dialihkan ke lecturerID; tahap dosen wali biasa sudah tampil
+// di daftar mahasiswa bimbingan. lecturerID nil untuk pemanggil yang bukan dosen.
+func GetPendingApprovals(permissions []string, lecturerID *uuid.UUID, limit, offset int) ([]PendingApproval, int, error) {
+	permissionArray := "{" + strings.Join(permissions, ",") + "}"
+
+	rows, err := config.DB.Query(`
+		SELECT r.id, r.student_id, r.mongo_achievement_id, r.status,
+		       r.submitted_at, r.verified_at, r.verified_by, r.rejection_note, r.workflow_id, r.current_stage,
+		       r.revoked_at, r.revoked_by, r.revocation_reason,
+		       r.review_due_at, r.review_sla_id, r.escalated_at, r.escalated_to,
+		       r.created_at, r.updated_at,
+		       s.stage_order, s.name, s.required_permission, s.advisor_only
+		FROM achievement_references r
+		LEFT JOIN approval_workflow_stages s ON s.workflow_id = r.workflow_id AND s.stage_order = r.current_stage
+	`+pendingApprovalsWhere+`
+		ORDER BY r.submitted_at ASC
+		LIMIT $3 OFFSET $4
+	`, permissionArray, lecturerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	pending := []PendingApproval{}
+	for rows.Next() {
+		var p PendingApproval
+		var stageOrder sql.NullInt64
+		var stageName, stagePermission sql.NullString
+		var stageAdvisorOnly sql.NullBool
+		err := rows.Scan(
+			&p.Reference.ID,
+			&p.Reference.StudentID,
+			&p.Reference.MongoAchievementID,
+			&p.Reference.Status,
+			&p.Reference.SubmittedAt,
+			&p.Reference.VerifiedAt,
+			&p.Reference.VerifiedBy,
+			&p.Reference.RejectionNote,
+			&p.Reference.WorkflowID,
+			&p.Reference.CurrentStage,
+			&p.Reference.RevokedAt,
+			&p.Reference.RevokedBy,
+			&p.Reference.RevocationReason,
+			&p.Reference.ReviewDueAt,
+			&p.Reference.ReviewSLAID,
+			&p.Reference.EscalatedAt,
+			&p.Reference.EscalatedTo,
+			&p.Reference.CreatedAt,
+			&p.Reference.UpdatedAt,
+			&stageOrder,
+			&stageName,
+			&stagePermission,
+			&stageAdvisorOnly,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Tanpa tahap workflow yang cocok berarti alur bawaan dosen wali
+		p.Stage = model.DefaultApprovalStages[0]
+		if stageOrder.Valid {
+			p.Stage = model.ApprovalStage{
+				Order:              int(stageOrder.Int64),
+				Name:               stageName.String,
+				RequiredPermission: stagePermission.String,
+				AdvisorOnly:        stageAdvisorOnly.Bool,
+			}
+		}
+		pending = append(pending, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err = config.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM achievement_references r
+		LEFT JOIN approval_workflow_stages s ON s.workflow_id = r.workflow_id AND s.stage_order = r.current_stage
+	`+pendingApprovalsWhere, permissionArray, lecturerID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pending, total, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission untuk memulai impersonation ("login as" user lain)
+const ImpersonateUsersPermission = "impersonate_users"
+
+// Jenis kejadian di impersonation_events
+const (
+	ImpersonationEventStart        = "start"
+	ImpersonationEventEnd          = "end"
+	ImpersonationEventWriteBlocked = "write_blocked"
+)
+
+// CreateImpersonation menyimpan impersonation baru beserta event start dalam satu transaksi
+func CreateImpersonation(imp *model.Impersonations) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	imp.ID = uuid.New()
+	imp.StartedAt = time.Now()
+
+	_, err = tx.Exec(`
+		INSERT INTO impersonations
+		(id, impersonator_id, impersonator_username, target_user_id, target_username,
+		 reason, ip_address, user_agent, started_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, imp.ID, imp.ImpersonatorID, imp.ImpersonatorUsername, imp.TargetUserID, imp.TargetUsername,
+		imp.Reason, imp.IPAddress, imp.UserAgent, imp.StartedAt, imp.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	err = insertImpersonationEventTx(tx, &model.ImpersonationEvents{
+		ImpersonationID: imp.ID,
+		Event:           ImpersonationEventStart,
+		IPAddress:       imp.IPAddress,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetImpersonationByID mengambil impersonation berdasarkan id
+func GetImpersonationByID(id uuid.UUID) (*model.Impersonations, error) {
+	var imp model.Impersonations
+	err := config.DB.QueryRow(`
+		SELECT id, impersonator_id, impersonator_username, target_user_id, target_username,
+		       reason, ip_address, user_agent, started_at, expires_at, ended_at, end_reason
+		FROM impersonations
+		WHERE id = $1
+	`, id).Scan(
+		&imp.ID,
+		&imp.ImpersonatorID,
+		&imp.ImpersonatorUsername,
+		&imp.TargetUserID,
+		&imp.TargetUsername,
+		&imp.Reason,
+		&imp.IPAddress,
+		&imp.UserAgent,
+		&imp.StartedAt,
+		&imp.ExpiresAt,
+		&imp.EndedAt,
+		&imp.EndReason,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("impersonation tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	return &imp, nil
+}
+
+// EndImpersonation mengakhiri impersonation dan mencatat event end
+// Mengembalikan false jika impersonation sudah berakhir sebelumnya
+func EndImpersonation(id uuid.UUID, reason, ipAddress string) (bool, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE impersonations
+		SET ended_at = $1, end_reason = $2
+		WHERE id = $3 AND ended_at IS NULL
+	`, time.Now(), reason, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	err = insertImpersonationEventTx(tx, &model.ImpersonationEvents{
+		ImpersonationID: id,
+		Event:           ImpersonationEventEnd,
+		IPAddress:       ipAddress,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RecordImpersonationEvent mencatat satu event impersonation (mis. aksi tulis yang diblokir)
+func RecordImpersonationEvent(event *model.ImpersonationEvents) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO impersonation_events (id, impersonation_id, event, method, path, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, uuid.New(), event.ImpersonationID, event.Event, event.Method, event.Path, event.IPAddress, time.Now())
+	return err
+}
+
+func insertImpersonationEventTx(tx *sql.Tx, event *model.ImpersonationEvents) error {
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+
+	_, err := tx.Exec(`
+		INSERT INTO impersonation_events (id, impersonation_id, event, method, path, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, event.ID, event.ImpersonationID, event.Event, event.Method, event.Path, event.IPAddress, event.CreatedAt)
+	return err
+}
+
+// GetImpersonations mengambil riwayat impersonation terbaru dengan pagination
+func GetImpersonations(limit, offset int) ([]model.Impersonations, int, error) {
+	var total int
+	if err := config.DB.QueryRow(`SELECT COUNT(*) FROM impersonations`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, impersonator_id, impersonator_username, target_user_id, target_username,
+		       reason, ip_address, user_agent, started_at, expires_at, ended_at, end_reason
+		FROM impersonations
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	impersonations := []model.Impersonations{}
+	for rows.Next() {
+		var imp model.Impersonations
+		err := rows.Scan(
+			&imp.ID,
+			&imp.ImpersonatorID,
+			&imp.ImpersonatorUsername,
+			&imp.TargetUserID,
+			&imp.TargetUsername,
+			&imp.Reason,
+			&imp.IPAddress,
+			&imp.UserAgent,
+			&imp.StartedAt,
+			&imp.ExpiresAt,
+			&imp.EndedAt,
+			&imp.EndReason,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		impersonations = append(impersonations, imp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return impersonations, total, nil
+}
+
+// GetImpersonationEvents mengambil semua event milik satu impersonation
+func GetImpersonationEvents(impersonationID uuid.UUID) ([]model.ImpersonationEvents, error) {
+	rows, err := config.DB.Query(`
+		SELECT id, impersonation_id, event, method, path, ip_address, created_at
+		FROM impersonation_events
+		WHERE impersonation_id = $1
+		ORDER BY created_at
+	`, impersonationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.ImpersonationEvents{}
+	for rows.Next() {
+		var e model.ImpersonationEvents
+		if err := rows.Scan(&e.ID, &e.ImpersonationID, &e.Event, &e.Method, &e.Path, &e.IPAddress, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
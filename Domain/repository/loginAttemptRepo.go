@@ -0,0 +1,180 @@
+package repository
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository interface untuk menyimpan percobaan login gagal
+type LoginAttemptRepository interface {
+	// Get mengembalikan nil jika key belum pernah gagal login
+	Get(key string) (*model.LoginAttempts, error)
+	// IncrementFailure menambah counter gagal dan mengembalikan jumlah terbaru.
+	// Counter dimulai ulang jika kegagalan terakhir terjadi sebelum windowStart.
+	IncrementFailure(key string, at, windowStart time.Time) (int, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+// NewLoginAttemptRepository membuat repository sesuai driver yang dikonfigurasi
+// Driver yang didukung: "postgres" dan "memory"
+func NewLoginAttemptRepository(driver string, db *sql.DB) (LoginAttemptRepository, error) {
+	switch driver {
+	case "postgres":
+		if db == nil {
+			return nil, errors.New("login attempt postgres membutuhkan koneksi database")
+		}
+		return NewPostgresLoginAttempts(db), nil
+	case "memory":
+		return NewInMemoryLoginAttempts(), nil
+	default:
+		return nil, errors.New("login attempt driver tidak dikenal: " + driver)
+	}
+}
+
+// InMemoryLoginAttempts implementasi repository dengan in-memory storage
+// Hanya untuk development/testing: tidak dibagi antar replica
+type InMemoryLoginAttempts struct {
+	storage map[string]*model.LoginAttempts
+	mu      sync.Mutex
+}
+
+// NewInMemoryLoginAttempts membuat instance repository baru
+func NewInMemoryLoginAttempts() *InMemoryLoginAttempts {
+	return &InMemoryLoginAttempts{
+		storage: make(map[string]*model.LoginAttempts),
+	}
+}
+
+// Get mengambil data percobaan login berdasarkan key
+func (r *InMemoryLoginAttempts) Get(key string) (*model.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, exists := r.storage[key]
+	if !exists {
+		return nil, nil
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+// IncrementFailure menambah counter gagal
+func (r *InMemoryLoginAttempts) IncrementFailure(key string, at, windowStart time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, exists := r.storage[key]
+	if !exists {
+		attempt = &model.LoginAttempts{Key: key}
+		r.storage[key] = attempt
+	}
+
+	if attempt.LastFailureAt.Before(windowStart) {
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = at
+	return attempt.Failures, nil
+}
+
+// Lock mengunci key sampai waktu tertentu
+func (r *InMemoryLoginAttempts) Lock(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, exists := r.storage[key]
+	if !exists {
+		attempt = &model.LoginAttempts{Key: key, LastFailureAt: until}
+		r.storage[key] = attempt
+	}
+	attempt.LockedUntil = &until
+	return nil
+}
+
+// Reset menghapus data percobaan login untuk key
+func (r *InMemoryLoginAttempts) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.storage, key)
+	return nil
+}
+
+// PostgresLoginAttempts implementasi repository dengan tabel login_attempts
+type PostgresLoginAttempts struct {
+	db *sql.DB
+}
+
+// NewPostgresLoginAttempts membuat instance repository baru
+func NewPostgresLoginAttempts(db *sql.DB) *PostgresLoginAttempts {
+	return &PostgresLoginAttempts{db: db}
+}
+
+// Get mengambil data percobaan login berdasarkan key
+func (r *PostgresLoginAttempts) Get(key string) (*model.LoginAttempts, error) {
+	var attempt model.LoginAttempts
+	query := `
+		SELECT key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	err := r.db.QueryRow(query, key).Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// IncrementFailure menambah counter gagal secara atomik
+func (r *PostgresLoginAttempts) IncrementFailure(key string, at, windowStart time.Time) (int, error) {
+	var failures int
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures
+	`
+	err := r.db.QueryRow(query, key, at, windowStart).Scan(&failures)
+	if err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+// Lock mengunci key sampai waktu tertentu
+func (r *PostgresLoginAttempts) Lock(key string, until time.Time) error {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at, locked_until)
+		VALUES ($1, 0, $2, $2)
+		ON CONFLICT (key) DO UPDATE SET locked_until = EXCLUDED.locked_until
+	`
+	_, err := r.db.Exec(query, key, until)
+	return err
+}
+
+// Reset menghapus data percobaan login untuk key
+func (r *PostgresLoginAttempts) Reset(key string) error {
+	_, err := r.db.Exec(`DELETE FROM login_attempts WHERE key = $1`, key)
+	return err
+}
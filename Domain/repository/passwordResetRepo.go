@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordResetTokenInvalid token reset tidak ada, sudah dipakai, atau sudah kadaluarsa
+var ErrPasswordResetTokenInvalid = errors.New("token reset password tidak valid atau sudah kadaluarsa")
+
+// CreatePasswordResetToken menyimpan token reset baru
+// Token lain milik user yang belum dipakai langsung dibatalkan sehingga hanya link terakhir yang berlaku
+func CreatePasswordResetToken(token *model.PasswordResetTokens) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE user_id = $2 AND used_at IS NULL
+	`, now, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	token.ID = uuid.New()
+	token.CreatedAt = now
+
+	_, err = tx.Exec(`
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, ip_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, token.ID, token.UserID, token.TokenHash, token.IPAddress, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetPasswordWithToken memakai token reset (sekali pakai), mengganti password
+// dan mencabut semua sesi user dalam satu transaksi. Mengembalikan id user.
+func ResetPasswordWithToken(tokenHash, newPassword string) (uuid.UUID, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var userID uuid.UUID
+	err = tx.QueryRow(`
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id
+	`, now, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, ErrPasswordResetTokenInvalid
+		}
+		return uuid.Nil, err
+	}
+
+	if err := updateUserPasswordTx(tx, userID, string(hashedPassword), now); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
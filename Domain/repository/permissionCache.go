@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionLoader sumber data permission per role untuk PermissionCache
+type PermissionLoader interface {
+	// LoadRolePermissions mengembalikan daftar permission role beserta permissions_version-nya
+	LoadRolePermissions(roleID uuid.UUID) ([]string, int64, error)
+	// GetRolePermissionsVersion mengembalikan permissions_version role (query ringan)
+	GetRolePermissionsVersion(roleID uuid.UUID) (int64, error)
+}
+
+// PostgresPermissionLoader membaca permission dari tabel roles/role_permissions
+type PostgresPermissionLoader struct{}
+
+// LoadRolePermissions membaca versi dan permission role dalam satu transaksi read-only
+func (PostgresPermissionLoader) LoadRolePermissions(roleID uuid.UUID) ([]string, int64, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	// Versi dibaca lebih dulu: jika role_permissions berubah setelahnya, versi di cache
+	// lebih kecil dari versi baru sehingga pengecekan berikutnya tetap memuat ulang
+	var version int64
+	err = tx.QueryRow(`SELECT permissions_version FROM roles WHERE id = $1`, roleID).Scan(&version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, ErrRoleNotFound
+		}
+		return nil, 0, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT p.name
+		FROM permissions p
+		JOIN role_permissions rp ON p.id = rp.permission_id
+		WHERE rp.role_id = $1
+	`, roleID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, 0, err
+		}
+		permissions = append(permissions, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return permissions, version, tx.Commit()
+}
+
+// GetRolePermissionsVersion membaca permissions_version role
+func (PostgresPermissionLoader) GetRolePermissionsVersion(roleID uuid.UUID) (int64, error) {
+	var version int64
+	err := config.DB.QueryRow(`SELECT permissions_version FROM roles WHERE id = $1`, roleID).Scan(&version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrRoleNotFound
+		}
+		return 0, err
+	}
+	return version, nil
+}
+
+type permissionCacheEntry struct {
+	permissions []string
+	version     int64
+	checkedAt   time.Time
+}
+
+// PermissionCache cache role -> permissions yang dipakai JWTAuth
+//
+// Entry dianggap segar selama checkInterval. Setelah itu versi role dicek ke database
+// dan permission hanya dimuat ulang jika versinya berubah. Token yang membawa versi
+// lebih baru dari cache (claim "pv") langsung memaksa reload.
+type PermissionCache struct {
+	loader        PermissionLoader
+	checkInterval time.Duration
+	now           func() time.Time
+
+	mu      sync.RWMutex
+	entries map[uuid.UUID]*permissionCacheEntry
+}
+
+// NewPermissionCache membuat cache permission baru
+func NewPermissionCache(loader PermissionLoader, checkInterval time.Duration) *PermissionCache {
+	return &PermissionCache{
+		loader:        loader,
+		checkInterval: checkInterval,
+		now:           time.Now,
+		entries:       make(map[uuid.UUID]*permissionCacheEntry),
+	}
+}
+
+// WithClock mengganti sumber waktu (untuk testing)
+func (c *PermissionCache) WithClock(now func() time.Time) *PermissionCache {
+	c.now = now
+	return c
+}
+
+// Get mengembalikan permission role beserta versinya; minVersion adalah versi dari claim token (0 jika tidak ada)
+func (c *PermissionCache) Get(roleID uuid.UUID, minVersion int64) ([]string, int64, error) {
+	now := c.now()
+
+	c.mu.RLock()
+	entry, exists := c.entries[roleID]
+	c.mu.RUnlock()
+
+	if exists && entry.version >= minVersion {
+		if now.Sub(entry.checkedAt) < c.checkInterval {
+			return entry.permissions, entry.version, nil
+		}
+
+		version, err := c.loader.GetRolePermissionsVersion(roleID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if version == entry.version {
+			c.mu.Lock()
+			c.entries[roleID] = &permissionCacheEntry{
+				permissions: entry.permissions,
+				version:     entry.version,
+				checkedAt:   now,
+			}
+			c.mu.Unlock()
+			return entry.permissions, entry.version, nil
+		}
+	}
+
+	permissions, version, err := c.loader.LoadRolePermissions(roleID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.mu.Lock()
+	c.entries[roleID] = &permissionCacheEntry{
+		permissions: permissions,
+		version:     version,
+		checkedAt:   now,
+	}
+	c.mu.Unlock()
+
+	return permissions, version, nil
+}
+
+// Invalidate menghapus cache satu role (dipanggil setelah role_permissions diubah lewat API)
+func (c *PermissionCache) Invalidate(roleID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, roleID)
+}
+
+// InvalidateAll mengosongkan seluruh cache
+func (c *PermissionCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[uuid.UUID]*permissionCacheEntry)
+}
+
+// permissionCache cache yang dipakai bersama oleh middleware dan service
+var permissionCache = NewPermissionCache(PostgresPermissionLoader{}, config.GetPermissionCacheCheckInterval())
+
+// RolePermissionCache mengembalikan cache permission yang sedang dipakai
+func RolePermissionCache() *PermissionCache {
+	return permissionCache
+}
+
+// SetPermissionCache mengganti cache permission (dikonfigurasi di main atau di test)
+func SetPermissionCache(cache *PermissionCache) {
+	permissionCache = cache
+}
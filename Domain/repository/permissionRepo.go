@@ -2,6 +2,9 @@ package repository
 
 import (
 	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
 
 	"github.com/google/uuid"
 )
@@ -35,4 +38,157 @@ func GetPermissionsByRoleID(roleID uuid.UUID) ([]string, error) {
     }
 
     return permissions, nil
-}
\ No newline at end of file
+}
+var (
+	ErrPermissionNameExists = errors.New("nama permission sudah digunakan")
+	ErrPermissionNotFound   = errors.New("permission tidak ditemukan")
+)
+
+// GetPermissions mengambil semua permission
+func GetPermissions() ([]model.Permissions, error) {
+	permissions := []model.Permissions{}
+	rows, err := config.DB.Query(`
+		SELECT id, name, resource, action, description
+		FROM permissions
+		ORDER BY resource, action
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p model.Permissions
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetPermissionByID mengambil permission berdasarkan id
+func GetPermissionByID(permissionID uuid.UUID) (*model.Permissions, error) {
+	var p model.Permissions
+	err := config.DB.QueryRow(`
+		SELECT id, name, resource, action, description
+		FROM permissions
+		WHERE id = $1
+	`, permissionID).Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPermissionNotFound
+		}
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GetPermissionByName mengambil permission berdasarkan nama
+func GetPermissionByName(name string) (*model.Permissions, error) {
+	var p model.Permissions
+	err := config.DB.QueryRow(`
+		SELECT id, name, resource, action, description
+		FROM permissions
+		WHERE name = $1
+	`, name).Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPermissionNotFound
+		}
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// CreatePermission membuat permission baru
+func CreatePermission(p *model.Permissions) error {
+	p.ID = uuid.New()
+	_, err := config.DB.Exec(`
+		INSERT INTO permissions (id, name, resource, action, description)
+		VALUES ($1, $2, $3, $4, $5)
+	`, p.ID, p.Name, p.Resource, p.Action, p.Description)
+	if isUniqueViolation(err) {
+		return ErrPermissionNameExists
+	}
+	return err
+}
+
+// UpdatePermission mengubah permission
+func UpdatePermission(p *model.Permissions) error {
+	result, err := config.DB.Exec(`
+		UPDATE permissions SET name = $1, resource = $2, action = $3, description = $4
+		WHERE id = $5
+	`, p.Name, p.Resource, p.Action, p.Description, p.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrPermissionNameExists
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrPermissionNotFound
+	}
+
+	return nil
+}
+
+// DeletePermission menghapus permission beserta relasinya ke role
+// Mengembalikan id role yang kehilangan permission ini (untuk invalidasi cache)
+func DeletePermission(permissionID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`DELETE FROM role_permissions WHERE permission_id = $1 RETURNING role_id`, permissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := []uuid.UUID{}
+	for rows.Next() {
+		var roleID uuid.UUID
+		if err := rows.Scan(&roleID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		roleIDs = append(roleIDs, roleID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM permissions WHERE id = $1`, permissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, ErrPermissionNotFound
+	}
+
+	return roleIDs, tx.Commit()
+}
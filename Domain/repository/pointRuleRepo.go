@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission untuk mengelola aturan poin prestasi
+const ManagePointRulesPermission = "manage_point_rules"
+
+var ErrPointRuleSetNotFound = errors.New("rule set tidak ditemukan")
+
+// CreatePointRuleSet menyimpan rule set sebagai versi baru (nomor versi = versi terakhir + 1)
+// Jika activate true, versi baru langsung diaktifkan dalam transaksi yang sama
+func CreatePointRuleSet(set *model.PointRuleSets, activate bool) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Kunci tabel agar dua request bersamaan tidak mendapat nomor versi yang sama
+	if _, err := tx.Exec(`LOCK TABLE point_rule_sets IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) + 1 FROM point_rule_sets`).Scan(&set.Version); err != nil {
+		return err
+	}
+
+	set.ID = uuid.New()
+	set.CreatedAt = time.Now()
+	set.IsActive = false
+	set.ActivatedAt = nil
+
+	_, err = tx.Exec(`
+		INSERT INTO point_rule_sets (id, version, description, is_active, created_by, created_at)
+		VALUES ($1, $2, $3, FALSE, $4, $5)
+	`, set.ID, set.Version, set.Description, set.CreatedBy, set.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range set.Rules {
+		rule := &set.Rules[i]
+		rule.ID = uuid.New()
+		rule.RuleSetID = set.ID
+		if rule.Conditions == nil {
+			rule.Conditions = map[string]string{}
+		}
+
+		conditions, err := json.Marshal(rule.Conditions)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO point_rules (id, rule_set_id, achievement_type, conditions, points, description, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, rule.ID, rule.RuleSetID, rule.AchievementType, conditions, rule.Points, rule.Description, i)
+		if err != nil {
+			return err
+		}
+	}
+	set.RuleCount = len(set.Rules)
+
+	if activate {
+		if err := activatePointRuleSetTx(tx, set.ID); err != nil {
+			return err
+		}
+		now := time.Now()
+		set.IsActive = true
+		set.ActivatedAt = &now
+	}
+
+	return tx.Commit()
+}
+
+// activatePointRuleSetTx menonaktifkan versi aktif sebelumnya lalu mengaktifkan rule set ini
+func activatePointRuleSetTx(tx *sql.Tx, ruleSetID uuid.UUID) error {
+	if _, err := tx.Exec(`UPDATE point_rule_sets SET is_active = FALSE WHERE is_active`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE point_rule_sets SET is_active = TRUE, activated_at = $1 WHERE id = $2`, time.Now(), ruleSetID)
+	return err
+}
+
+// ActivatePointRuleSet menjadikan versi tertentu sebagai rule set aktif
+func ActivatePointRuleSet(version int) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var ruleSetID uuid.UUID
+	err = tx.QueryRow(`SELECT id FROM point_rule_sets WHERE version = $1`, version).Scan(&ruleSetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPointRuleSetNotFound
+		}
+		return err
+	}
+
+	if err := activatePointRuleSetTx(tx, ruleSetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPointRuleSets mengambil semua versi rule set (tanpa rules), terbaru dulu
+func GetPointRuleSets() ([]model.PointRuleSets, error) {
+	rows, err := config.DB.Query(`
+		SELECT s.id, s.version, s.description, s.is_active, s.created_by, s.created_at, s.activated_at,
+		       (SELECT COUNT(*) FROM point_rules r WHERE r.rule_set_id = s.id)
+		FROM point_rule_sets s
+		ORDER BY s.version DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []model.PointRuleSets{}
+	for rows.Next() {
+		var set model.PointRuleSets
+		err := rows.Scan(
+			&set.ID,
+			&set.Version,
+			&set.Description,
+			&set.IsActive,
+			&set.CreatedBy,
+			&set.CreatedAt,
+			&set.ActivatedAt,
+			&set.RuleCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// getPointRuleSet mengambil satu rule set beserta rules-nya
+func getPointRuleSet(where string, arg interface{}) (*model.PointRuleSets, error) {
+	var set model.PointRuleSets
+	err := config.DB.QueryRow(`
+		SELECT id, version, description, is_active, created_by, created_at, activated_at
+		FROM point_rule_sets
+		WHERE `+where, arg).Scan(
+		&set.ID,
+		&set.Version,
+		&set.Description,
+		&set.IsActive,
+		&set.CreatedBy,
+		&set.CreatedAt,
+		&set.ActivatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, rule_set_id, achievement_type, conditions, points, description
+		FROM point_rules
+		WHERE rule_set_id = $1
+		ORDER BY position ASC
+	`, set.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set.Rules = []model.PointRules{}
+	for rows.Next() {
+		var rule model.PointRules
+		var conditions []byte
+		err := rows.Scan(&rule.ID, &rule.RuleSetID, &rule.AchievementType, &conditions, &rule.Points, &rule.Description)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+			return nil, err
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	set.RuleCount = len(set.Rules)
+
+	return &set, nil
+}
+
+// GetPointRuleSetByVersion mengambil rule set versi tertentu beserta rules-nya
+func GetPointRuleSetByVersion(version int) (*model.PointRuleSets, error) {
+	set, err := getPointRuleSet("version = $1", version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPointRuleSetNotFound
+		}
+		return nil, err
+	}
+	return set, nil
+}
+
+// GetActivePointRuleSet mengambil rule set aktif beserta rules-nya; nil jika belum ada yang aktif
+func GetActivePointRuleSet() (*model.PointRuleSets, error) {
+	set, err := getPointRuleSet("is_active = $1", true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return set, nil
+}
+
+// GetAchievementMongoIDsByStatus mengambil mongo_achievement_id semua reference dengan status tertentu
+func GetAchievementMongoIDsByStatus(status string) ([]string, error) {
+	rows, err := config.DB.Query(`
+		SELECT mongo_achievement_id FROM achievement_references WHERE status = $1
+	`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenReused dikembalikan ketika refresh token yang sudah dirotasi dipakai lagi
+var ErrRefreshTokenReused = errors.New("refresh token sudah digunakan")
+
+// HashToken menghasilkan hash SHA-256 (hex) dari token mentah
+// Token mentah tidak pernah disimpan di database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateRefreshToken menyimpan refresh token baru
+func CreateRefreshToken(token *model.RefreshTokens) error {
+	query := `
+		INSERT INTO refresh_tokens
+		(id, user_id, family_id, token_hash, device, user_agent, ip_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+
+	_, err := config.DB.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.Device,
+		token.UserAgent,
+		token.IPAddress,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// GetRefreshTokenByHash mengambil refresh token berdasarkan hash
+func GetRefreshTokenByHash(tokenHash string) (*model.RefreshTokens, error) {
+	var token model.RefreshTokens
+	query := `
+		SELECT id, user_id, family_id, token_hash, device, user_agent, ip_address,
+		       expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	err := config.DB.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.Device,
+		&token.UserAgent,
+		&token.IPAddress,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("refresh token tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken menandai token lama sebagai sudah dipakai dan menyimpan penggantinya
+// dalam satu transaksi. Jika token lama ternyata sudah dirotasi oleh request lain,
+// ErrRefreshTokenReused dikembalikan.
+func RotateRefreshToken(oldID uuid.UUID, next *model.RefreshTokens) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	next.ID = uuid.New()
+	next.CreatedAt = time.Now()
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = $1, replaced_by = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`, next.CreatedAt, next.ID, oldID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRefreshTokenReused
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO refresh_tokens
+		(id, user_id, family_id, token_hash, device, user_agent, ip_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		next.ID,
+		next.UserID,
+		next.FamilyID,
+		next.TokenHash,
+		next.Device,
+		next.UserAgent,
+		next.IPAddress,
+		next.ExpiresAt,
+		next.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeRefreshTokenFamily mencabut semua refresh token dalam satu family
+func RevokeRefreshTokenFamily(familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL
+	`
+	_, err := config.DB.Exec(query, time.Now(), familyID)
+	return err
+}
+
+// RevokeRefreshTokensByUserID mencabut semua refresh token milik user
+func RevokeRefreshTokensByUserID(userID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`
+	_, err := config.DB.Exec(query, time.Now(), userID)
+	return err
+}
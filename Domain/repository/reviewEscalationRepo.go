@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewEscalationLockKey key pg advisory lock scheduler eskalasi review
+const ReviewEscalationLockKey int64 = 720250001
+
+// OverdueReview prestasi submitted yang melewati batas waktu review dan belum dieskalasi
+type OverdueReview struct {
+	ReferenceID         uuid.UUID
+	MongoAchievementID  string
+	StudentNumber       string // NIM
+	AdvisorID           *uuid.UUID
+	SubmittedAt         time.Time
+	CurrentStage        int
+	ReviewDueAt         time.Time // batas waktu tahap saat ini
+	AdvisorOnlyStage    bool      // tahap saat ini tahap dosen wali
+	EscalationEmail     *string
+	BackupReviewerID    *uuid.UUID
+	BackupReviewerEmail *string
+}
+
+// ReviewEscalationRepository interface data access scheduler eskalasi review
+type ReviewEscalationRepository interface {
+	// GetOverdueReviews prestasi dengan review_due_at sebelum now, paling lama menunggu lebih dulu
+	GetOverdueReviews(now time.Time, limit int) ([]OverdueReview, error)
+	// MarkReviewEscalated mencatat eskalasi tahap stage (compare-and-set: masih submitted di tahap
+	// yang sama dan belum dieskalasi). Mengembalikan false jika status atau tahap sudah berubah, atau
+	// sudah dieskalasi replica lain.
+	MarkReviewEscalated(referenceID uuid.UUID, stage int, escalatedTo *uuid.UUID, at time.Time) (bool, error)
+}
+
+// AdvisoryLock lock antar replica agar job berkala hanya dijalankan satu instance
+type AdvisoryLock interface {
+	// TryLock tidak menunggu: acquired false jika lock dipegang instance lain
+	TryLock() (unlock func(), acquired bool, err error)
+}
+
+// PostgresReviewEscalations implementasi ReviewEscalationRepository dengan PostgreSQL
+type PostgresReviewEscalations struct {
+	db *sql.DB
+}
+
+// NewPostgresReviewEscalations membuat instance repository baru
+func NewPostgresReviewEscalations(db *sql.DB) *PostgresReviewEscalations {
+	return &PostgresReviewEscalations{db: db}
+}
+
+// GetOverdueReviews mengambil prestasi yang terlambat direview beserta tujuan eskalasinya
+// Reference tanpa workflow (atau tahap di luar workflow) dianggap di tahap dosen wali.
+func (r *PostgresReviewEscalations) GetOverdueReviews(now time.Time, limit int) ([]OverdueReview, error) {
+	rows, err := r.db.Query(`
+		SELECT r.id, r.mongo_achievement_id, st.student_id, st.advisor_id, r.submitted_at, r.current_stage, r.review_due_at,
+		       COALESCE(ws.advisor_only, TRUE),
+		       sla.escalation_email, sla.backup_reviewer_id, bu.email
+		FROM achievement_references r
+		JOIN students st ON st.id = r.student_id
+		LEFT JOIN approval_workflow_stages ws ON ws.workflow_id = r.workflow_id AND ws.stage_order = r.current_stage
+		LEFT JOIN review_slas sla ON sla.id = r.review_sla_id
+		LEFT JOIN lecturers bl ON bl.id = sla.backup_reviewer_id
+		LEFT JOIN users bu ON bu.id = bl.user_id
+		WHERE r.status = 'submitted' AND r.escalated_at IS NULL AND r.review_due_at < $1
+		ORDER BY r.review_due_at ASC
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overdue := []OverdueReview{}
+	for rows.Next() {
+		var o OverdueReview
+		err := rows.Scan(
+			&o.ReferenceID,
+			&o.MongoAchievementID,
+			&o.StudentNumber,
+			&o.AdvisorID,
+			&o.SubmittedAt,
+			&o.CurrentStage,
+			&o.ReviewDueAt,
+			&o.AdvisorOnlyStage,
+			&o.EscalationEmail,
+			&o.BackupReviewerID,
+			&o.BackupReviewerEmail,
+		)
+		if err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overdue, nil
+}
+
+// BackfillReviewDueDates mengisi batas waktu prestasi submitted yang belum punya review_due_at
+// (di-submit sebelum migration 019) dengan submitted_at + defaultDays. Aman dijalankan berulang.
+func (r *PostgresReviewEscalations) BackfillReviewDueDates(defaultDays int) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE achievement_references
+		SET review_due_at = submitted_at + make_interval(days => $1)
+		WHERE status = 'submitted' AND review_due_at IS NULL AND submitted_at IS NOT NULL
+	`, defaultDays)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// MarkReviewEscalated mencatat waktu eskalasi dan dosen pengganti (boleh nil)
+// Perpindahan tahap mengosongkan escalated_at sehingga setiap tahap dieskalasi paling banyak sekali.
+func (r *PostgresReviewEscalations) MarkReviewEscalated(referenceID uuid.UUID, stage int, escalatedTo *uuid.UUID, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE achievement_references
+		SET escalated_at = $1, escalated_to = $2
+		WHERE id = $3 AND status = 'submitted' AND current_stage = $4 AND escalated_at IS NULL
+	`, at, escalatedTo, referenceID, stage)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// PostgresAdvisoryLock session-level pg_try_advisory_lock pada koneksi khusus
+// Lock otomatis lepas jika koneksi putus (misalnya replica mati di tengah job).
+type PostgresAdvisoryLock struct {
+	db  *sql.DB
+	key int64
+}
+
+// NewPostgresAdvisoryLock membuat advisory lock dengan key tertentu
+func NewPostgresAdvisoryLock(db *sql.DB, key int64) *PostgresAdvisoryLock {
+	return &PostgresAdvisoryLock{db: db, key: key}
+}
+
+// TryLock mencoba mengambil lock tanpa menunggu
+func (l *PostgresAdvisoryLock) TryLock() (func(), bool, error) {
+	ctx := context.Background()
+
+	// Advisory lock terikat ke session, jadi lock dan unlock harus di koneksi yang sama
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
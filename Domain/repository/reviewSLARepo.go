@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission untuk mengelola batas waktu review dan eskalasi prestasi
+const ManageReviewSLAsPermission = "manage_review_slas"
+
+var (
+	ErrReviewSLAExists   = errors.New("SLA untuk tipe dan tingkat ini sudah ada")
+	ErrReviewSLANotFound = errors.New("SLA tidak ditemukan")
+)
+
+const reviewSLAColumns = `id, achievement_type, competition_level, due_days, escalation_email, backup_reviewer_id,
+	       updated_by, created_at, updated_at`
+
+// scanReviewSLA membaca satu baris review_slas sesuai urutan reviewSLAColumns
+func scanReviewSLA(row interface{ Scan(...any) error }) (*model.ReviewSLAs, error) {
+	var s model.ReviewSLAs
+	err := row.Scan(&s.ID, &s.AchievementType, &s.CompetitionLevel, &s.DueDays, &s.EscalationEmail, &s.BackupReviewerID,
+		&s.UpdatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetReviewSLAs mengambil semua aturan SLA review
+func GetReviewSLAs() ([]model.ReviewSLAs, error) {
+	rows, err := config.DB.Query(`
+		SELECT ` + reviewSLAColumns + `
+		FROM review_slas
+		ORDER BY achievement_type ASC, competition_level ASC NULLS FIRST
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slas := []model.ReviewSLAs{}
+	for rows.Next() {
+		s, err := scanReviewSLA(rows)
+		if err != nil {
+			return nil, err
+		}
+		slas = append(slas, *s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return slas, nil
+}
+
+// GetReviewSLAByID mengambil satu aturan SLA review
+func GetReviewSLAByID(id uuid.UUID) (*model.ReviewSLAs, error) {
+	s, err := scanReviewSLA(config.DB.QueryRow(`
+		SELECT `+reviewSLAColumns+`
+		FROM review_slas
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReviewSLANotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// FindReviewSLA mencari aturan SLA yang berlaku untuk tipe dan tingkat kompetisi prestasi
+// Aturan dengan tingkat yang sama didahulukan dari aturan untuk semua tingkat.
+// Mengembalikan nil, nil jika tidak ada (batas waktu bawaan REVIEW_SLA_DAYS).
+func FindReviewSLA(achievementType string, competitionLevel *string) (*model.ReviewSLAs, error) {
+	s, err := scanReviewSLA(config.DB.QueryRow(`
+		SELECT `+reviewSLAColumns+`
+		FROM review_slas
+		WHERE achievement_type = $1 AND (competition_level IS NULL OR competition_level = $2)
+		ORDER BY competition_level IS NULL ASC
+		LIMIT 1
+	`, achievementType, competitionLevel))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// CreateReviewSLA menyimpan aturan SLA baru
+func CreateReviewSLA(s *model.ReviewSLAs) error {
+	now := time.Now()
+	s.ID = uuid.New()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	_, err := config.DB.Exec(`
+		INSERT INTO review_slas (id, achievement_type, competition_level, due_days, escalation_email, backup_reviewer_id,
+		                         updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, s.ID, s.AchievementType, s.CompetitionLevel, s.DueDays, s.EscalationEmail, s.BackupReviewerID,
+		s.UpdatedBy, s.CreatedAt, s.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrReviewSLAExists
+	}
+	return err
+}
+
+// UpdateReviewSLA mengganti aturan SLA
+// Prestasi yang sudah di-submit tetap memakai batas waktu yang dihitung saat submit.
+func UpdateReviewSLA(s *model.ReviewSLAs) error {
+	s.UpdatedAt = time.Now()
+	result, err := config.DB.Exec(`
+		UPDATE review_slas
+		SET achievement_type = $1, competition_level = $2, due_days = $3, escalation_email = $4,
+		    backup_reviewer_id = $5, updated_by = $6, updated_at = $7
+		WHERE id = $8
+	`, s.AchievementType, s.CompetitionLevel, s.DueDays, s.EscalationEmail,
+		s.BackupReviewerID, s.UpdatedBy, s.UpdatedAt, s.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrReviewSLAExists
+		}
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrReviewSLANotFound
+	}
+	return nil
+}
+
+// DeleteReviewSLA menghapus aturan SLA; false jika tidak ada
+// Prestasi yang sedang menunggu tetap memakai batas waktunya dan dieskalasi ke penerima bawaan.
+func DeleteReviewSLA(id uuid.UUID) (bool, error) {
+	result, err := config.DB.Exec(`DELETE FROM review_slas WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
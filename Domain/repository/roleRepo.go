@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetRoleByID mengambil data role berdasarkan id
+func GetRoleByID(roleID uuid.UUID) (*model.Roles, error) {
+	var role model.Roles
+	query := `
+		SELECT id, name, description, permissions_version, created_at
+		FROM roles
+		WHERE id = $1
+	`
+
+	err := config.DB.QueryRow(query, roleID).Scan(
+		&role.ID,
+		&role.Name,
+		&role.Description,
+		&role.PermissionsVersion,
+		&role.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// Permission yang memberi akses ke API administrasi role; minimal satu role harus memilikinya
+const ManageRolesPermission = "manage_roles"
+
+var (
+	ErrRoleHasUsers          = errors.New("role masih dipakai oleh user")
+	ErrLastManageRolesRole   = errors.New("role ini adalah role terakhir yang memiliki permission manage_roles")
+	ErrRoleNameExists        = errors.New("nama role sudah digunakan")
+	ErrRoleNotFound          = errors.New("role tidak ditemukan")
+	ErrPermissionNotAttached = errors.New("permission tidak dimiliki role ini")
+)
+
+// GetRoles mengambil semua role
+func GetRoles() ([]model.Roles, error) {
+	roles := []model.Roles{}
+	rows, err := config.DB.Query(`
+		SELECT id, name, description, permissions_version, created_at
+		FROM roles
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role model.Roles
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.PermissionsVersion, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// CreateRole membuat role baru
+func CreateRole(role *model.Roles) error {
+	role.ID = uuid.New()
+	role.CreatedAt = time.Now()
+	role.PermissionsVersion = 1
+
+	_, err := config.DB.Exec(`
+		INSERT INTO roles (id, name, description, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, role.ID, role.Name, role.Description, role.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrRoleNameExists
+	}
+	return err
+}
+
+// UpdateRole mengubah nama dan deskripsi role
+func UpdateRole(role *model.Roles) error {
+	result, err := config.DB.Exec(`
+		UPDATE roles SET name = $1, description = $2
+		WHERE id = $3
+	`, role.Name, role.Description, role.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrRoleNameExists
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRoleNotFound
+	}
+
+	return nil
+}
+
+// CountUsersByRoleID menghitung user yang memakai role
+func CountUsersByRoleID(roleID uuid.UUID) (int, error) {
+	var count int
+	err := config.DB.QueryRow(`SELECT COUNT(*) FROM users WHERE role_id = $1`, roleID).Scan(&count)
+	return count, err
+}
+
+// DeleteRole menghapus role yang tidak dipakai user dan bukan pemegang terakhir manage_roles
+func DeleteRole(roleID uuid.UUID) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Kunci role agar tidak ada user yang di-assign ke role ini selama penghapusan
+	var id uuid.UUID
+	err = tx.QueryRow(`SELECT id FROM roles WHERE id = $1 FOR UPDATE`, roleID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	var userCount int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE role_id = $1`, roleID).Scan(&userCount); err != nil {
+		return err
+	}
+	if userCount > 0 {
+		return ErrRoleHasUsers
+	}
+
+	if err := ensureNotLastManageRolesRole(tx, roleID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = $1`, roleID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM roles WHERE id = $1`, roleID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetRolePermissions mengambil detail permission milik role
+func GetRolePermissions(roleID uuid.UUID) ([]model.Permissions, error) {
+	permissions := []model.Permissions{}
+	rows, err := config.DB.Query(`
+		SELECT p.id, p.name, p.resource, p.action, p.description
+		FROM permissions p
+		JOIN role_permissions rp ON p.id = rp.permission_id
+		WHERE rp.role_id = $1
+		ORDER BY p.name
+	`, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p model.Permissions
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// AttachPermissionToRole menambahkan permission ke role (idempotent)
+func AttachPermissionToRole(roleID, permissionID uuid.UUID) error {
+	_, err := config.DB.Exec(`
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM role_permissions WHERE role_id = $1 AND permission_id = $2
+		)
+	`, roleID, permissionID)
+	return err
+}
+
+// DetachPermissionFromRole melepas permission dari role
+// Melepas manage_roles dari role terakhir yang memilikinya ditolak
+func DetachPermissionFromRole(roleID, permissionID uuid.UUID) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Kunci role agar role yang tidak ada dibedakan dari permission yang tidak dimiliki role
+	var id uuid.UUID
+	err = tx.QueryRow(`SELECT id FROM roles WHERE id = $1 FOR UPDATE`, roleID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	var name string
+	err = tx.QueryRow(`SELECT name FROM permissions WHERE id = $1`, permissionID).Scan(&name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPermissionNotFound
+		}
+		return err
+	}
+
+	if name == ManageRolesPermission {
+		if err := ensureNotLastManageRolesRole(tx, roleID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`
+		DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2
+	`, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrPermissionNotAttached
+	}
+
+	return tx.Commit()
+}
+
+// ensureNotLastManageRolesRole menolak perubahan yang membuat tidak ada role dengan manage_roles
+// Semua role pemegang manage_roles dikunci dulu agar dua admin tidak bisa mencabutnya bersamaan
+func ensureNotLastManageRolesRole(tx *sql.Tx, roleID uuid.UUID) error {
+	rows, err := tx.Query(`
+		SELECT r.id
+		FROM roles r
+		JOIN role_permissions rp ON rp.role_id = r.id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE p.name = $1
+		FOR UPDATE OF r
+	`, ManageRolesPermission)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	holdsPermission := false
+	holders := 0
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		holders++
+		if id == roleID {
+			holdsPermission = true
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if holdsPermission && holders <= 1 {
+		return ErrLastManageRolesRole
+	}
+
+	return nil
+}
+
+// isUniqueViolation mengecek error unique constraint PostgreSQL (23505)
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// GetRoleByName mengambil role berdasarkan nama
+func GetRoleByName(name string) (*model.Roles, error) {
+	var role model.Roles
+	err := config.DB.QueryRow(`
+		SELECT id, name, description, permissions_version, created_at
+		FROM roles
+		WHERE LOWER(name) = LOWER($1)
+	`, name).Scan(&role.ID, &role.Name, &role.Description, &role.PermissionsVersion, &role.CreatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
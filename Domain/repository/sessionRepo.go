@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotFound dikembalikan jika sesi tidak ada
+var ErrSessionNotFound = errors.New("sesi tidak ditemukan")
+
+// sessionTouchInterval membatasi seberapa sering last_seen_at di-update
+const sessionTouchInterval = time.Minute
+
+// CreateSession menyimpan sesi login baru
+func CreateSession(session *model.UserSessions) error {
+	query := `
+		INSERT INTO user_sessions
+		(id, user_id, device, ip_address, user_agent, issued_at, expires_at, last_seen_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	now := time.Now()
+	session.ID = uuid.New()
+	session.IssuedAt = now
+	session.LastSeenAt = now
+	session.CreatedAt = now
+
+	_, err := config.DB.Exec(
+		query,
+		session.ID,
+		session.UserID,
+		session.Device,
+		session.IPAddress,
+		session.UserAgent,
+		session.IssuedAt,
+		session.ExpiresAt,
+		session.LastSeenAt,
+		session.CreatedAt,
+	)
+
+	return err
+}
+
+// GetSessionByID mengambil sesi berdasarkan id
+func GetSessionByID(id uuid.UUID) (*model.UserSessions, error) {
+	var session model.UserSessions
+	query := `
+		SELECT id, user_id, device, ip_address, user_agent,
+		       issued_at, expires_at, last_seen_at, revoked_at, created_at
+		FROM user_sessions
+		WHERE id = $1
+	`
+
+	err := config.DB.QueryRow(query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Device,
+		&session.IPAddress,
+		&session.UserAgent,
+		&session.IssuedAt,
+		&session.ExpiresAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetActiveSessionsByUserID mengambil semua sesi aktif milik user
+func GetActiveSessionsByUserID(userID uuid.UUID) ([]model.UserSessions, error) {
+	sessions := []model.UserSessions{}
+	query := `
+		SELECT id, user_id, device, ip_address, user_agent,
+		       issued_at, expires_at, last_seen_at, revoked_at, created_at
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := config.DB.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session model.UserSessions
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Device,
+			&session.IPAddress,
+			&session.UserAgent,
+			&session.IssuedAt,
+			&session.ExpiresAt,
+			&session.LastSeenAt,
+			&session.RevokedAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// TouchSession memperbarui last_seen_at (paling sering sekali per menit)
+func TouchSession(id uuid.UUID) error {
+	now := time.Now()
+	query := `
+		UPDATE user_sessions
+		SET last_seen_at = $1
+		WHERE id = $2 AND last_seen_at < $3
+	`
+	_, err := config.DB.Exec(query, now, id, now.Add(-sessionTouchInterval))
+	return err
+}
+
+// ExtendSession memperpanjang masa berlaku sesi (dipakai saat refresh token)
+func ExtendSession(id uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE user_sessions
+		SET expires_at = $1, last_seen_at = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+	_, err := config.DB.Exec(query, expiresAt, time.Now(), id)
+	return err
+}
+
+// RevokeSession mencabut satu sesi milik user beserta refresh token-nya
+func RevokeSession(id, userID uuid.UUID) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`, now, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	_, err = tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL
+	`, now, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeOtherSessions mencabut semua sesi user kecuali sesi yang sedang dipakai
+func RevokeOtherSessions(userID, keepSessionID uuid.UUID) (int64, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL
+	`, now, userID, keepSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND family_id <> $3 AND revoked_at IS NULL
+	`, now, userID, keepSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, tx.Commit()
+}
+
+// RevokeSessionsByUserID mencabut semua sesi dan refresh token milik user (force logout)
+func RevokeSessionsByUserID(userID uuid.UUID) (int64, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	revoked, err := revokeUserSessionsTx(tx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, tx.Commit()
+}
+
+// revokeUserSessionsTx mencabut semua sesi dan refresh token user di dalam transaksi yang sudah ada
+func revokeUserSessionsTx(tx *sql.Tx, userID uuid.UUID, now time.Time) (int64, error) {
+	result, err := tx.Exec(`
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`, now, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`, now, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, nil
+}
@@ -105,3 +105,14 @@ func GetStudentsByAdvisorID(advisorID uuid.UUID) ([]model.Students, error) {
 
 	return students, nil
 }
+
+// CountStudentsByAdvisorID menghitung jumlah mahasiswa bimbingan seorang dosen
+func CountStudentsByAdvisorID(advisorID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM students WHERE advisor_id = $1`
+	err := config.DB.QueryRow(query, advisorID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
+	"log"
 	"sync"
 	"time"
 )
@@ -13,8 +16,50 @@ type TokenBlacklistRepository interface {
 	Cleanup() error
 }
 
+// NewTokenBlacklistRepository membuat repository blacklist sesuai driver yang dikonfigurasi
+// Driver yang didukung: "postgres" dan "memory"
+func NewTokenBlacklistRepository(driver string, db *sql.DB) (TokenBlacklistRepository, error) {
+	switch driver {
+	case "postgres":
+		if db == nil {
+			return nil, errors.New("token blacklist postgres membutuhkan koneksi database")
+		}
+		return NewPostgresTokenBlacklist(db), nil
+	case "memory":
+		return NewInMemoryTokenBlacklist(), nil
+	default:
+		return nil, errors.New("token blacklist driver tidak dikenal: " + driver)
+	}
+}
+
+// StartTokenBlacklistCleanup menjalankan Cleanup secara berkala di background
+// Panggil fungsi yang dikembalikan untuk menghentikan proses cleanup
+func StartTokenBlacklistCleanup(repo TokenBlacklistRepository, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := repo.Cleanup(); err != nil {
+					log.Println("Gagal cleanup token blacklist:", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
 // InMemoryTokenBlacklist implementasi repository dengan in-memory storage
-// Untuk production, buat implementasi dengan Redis atau database
+// Hanya untuk development/testing: data hilang saat restart dan tidak dibagi antar replica
 type InMemoryTokenBlacklist struct {
 	storage map[string]time.Time
 	mu      sync.RWMutex
@@ -31,7 +76,7 @@ func NewInMemoryTokenBlacklist() *InMemoryTokenBlacklist {
 func (r *InMemoryTokenBlacklist) Add(token string, expiresAt time.Time) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.storage[token] = expiresAt
+	r.storage[HashToken(token)] = expiresAt
 	return nil
 }
 
@@ -40,7 +85,7 @@ func (r *InMemoryTokenBlacklist) Exists(token string) (bool, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	expiresAt, exists := r.storage[token]
+	expiresAt, exists := r.storage[HashToken(token)]
 	if !exists {
 		return false, nil
 	}
@@ -57,7 +102,7 @@ func (r *InMemoryTokenBlacklist) Exists(token string) (bool, error) {
 func (r *InMemoryTokenBlacklist) Remove(token string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	delete(r.storage, token)
+	delete(r.storage, HashToken(token))
 	return nil
 }
 
@@ -67,29 +112,60 @@ func (r *InMemoryTokenBlacklist) Cleanup() error {
 	defer r.mu.Unlock()
 
 	now := time.Now()
-	for token, expiresAt := range r.storage {
+	for tokenHash, expiresAt := range r.storage {
 		if now.After(expiresAt) {
-			delete(r.storage, token)
+			delete(r.storage, tokenHash)
 		}
 	}
 	return nil
 }
 
-// Global instance untuk backward compatibility
-// TODO: Refactor untuk menggunakan dependency injection
-var defaultBlacklist = NewInMemoryTokenBlacklist()
+// PostgresTokenBlacklist implementasi repository dengan tabel token_blacklist
+// Data bertahan setelah restart dan dibagi oleh semua replica
+type PostgresTokenBlacklist struct {
+	db *sql.DB
+}
+
+// NewPostgresTokenBlacklist membuat instance repository baru
+func NewPostgresTokenBlacklist(db *sql.DB) *PostgresTokenBlacklist {
+	return &PostgresTokenBlacklist{db: db}
+}
 
-// AddTokenToBlacklist - wrapper function untuk backward compatibility
-func AddTokenToBlacklist(token string, expiresAt time.Time) error {
-	return defaultBlacklist.Add(token, expiresAt)
+// Add menambahkan hash token ke blacklist
+func (r *PostgresTokenBlacklist) Add(token string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO token_blacklist (token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_hash) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`
+	_, err := r.db.Exec(query, HashToken(token), expiresAt, time.Now())
+	return err
 }
 
-// IsTokenBlacklisted - wrapper function untuk backward compatibility
-func IsTokenBlacklisted(token string) (bool, error) {
-	return defaultBlacklist.Exists(token)
+// Exists mengecek apakah token ada di blacklist dan masih valid
+func (r *PostgresTokenBlacklist) Exists(token string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM token_blacklist
+			WHERE token_hash = $1 AND expires_at > $2
+		)
+	`
+	err := r.db.QueryRow(query, HashToken(token), time.Now()).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
 }
 
-// CleanupExpiredTokens - wrapper function untuk backward compatibility
-func CleanupExpiredTokens() error {
-	return defaultBlacklist.Cleanup()
+// Remove menghapus token dari blacklist
+func (r *PostgresTokenBlacklist) Remove(token string) error {
+	_, err := r.db.Exec(`DELETE FROM token_blacklist WHERE token_hash = $1`, HashToken(token))
+	return err
+}
+
+// Cleanup menghapus semua token yang sudah expired
+func (r *PostgresTokenBlacklist) Cleanup() error {
+	_, err := r.db.Exec(`DELETE FROM token_blacklist WHERE expires_at <= $1`, time.Now())
+	return err
 }
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetTwoFactorByUserID mengambil konfigurasi 2FA milik user
+func GetTwoFactorByUserID(userID uuid.UUID) (*model.UserTwoFactor, error) {
+	var tfa model.UserTwoFactor
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`
+
+	err := config.DB.QueryRow(query, userID).Scan(
+		&tfa.UserID,
+		&tfa.Secret,
+		&tfa.EnabledAt,
+		&tfa.LastUsedStep,
+		&tfa.CreatedAt,
+		&tfa.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("2FA tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	return &tfa, nil
+}
+
+// SaveTwoFactorSecret menyimpan secret enrollment yang belum dikonfirmasi
+// 2FA yang sudah aktif tidak akan tertimpa
+func SaveTwoFactorSecret(userID uuid.UUID, secret string) error {
+	query := `
+		INSERT INTO user_two_factor (user_id, secret, enabled_at, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, NULL, 0, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			last_used_step = 0,
+			updated_at = EXCLUDED.updated_at
+		WHERE user_two_factor.enabled_at IS NULL
+	`
+
+	result, err := config.DB.Exec(query, userID, secret, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("2FA sudah aktif")
+	}
+
+	return nil
+}
+
+// EnableTwoFactor mengaktifkan 2FA dan menyimpan recovery code baru dalam satu transaksi
+func EnableTwoFactor(userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE user_two_factor
+		SET enabled_at = $1, last_used_step = $2, updated_at = $1
+		WHERE user_id = $3 AND enabled_at IS NULL
+	`, now, step, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("enrollment 2FA tidak ditemukan")
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes mengganti seluruh recovery code milik user
+func ReplaceRecoveryCodes(userID uuid.UUID, recoveryCodeHashes []string) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID uuid.UUID, recoveryCodeHashes []string, now time.Time) error {
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err := tx.Exec(`
+			INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, uuid.New(), userID, hash, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkTwoFactorStepUsed mencatat time step TOTP yang sudah dipakai (compare-and-set)
+// Mengembalikan false jika step tersebut (atau yang lebih baru) sudah pernah dipakai
+func MarkTwoFactorStepUsed(userID uuid.UUID, step int64) (bool, error) {
+	result, err := config.DB.Exec(`
+		UPDATE user_two_factor
+		SET last_used_step = $1, updated_at = $2
+		WHERE user_id = $3 AND enabled_at IS NOT NULL AND last_used_step < $1
+	`, step, time.Now(), userID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// UseRecoveryCode menandai recovery code sebagai terpakai
+// Mengembalikan false jika kode tidak ada atau sudah dipakai
+func UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result, err := config.DB.Exec(`
+		UPDATE user_recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// CountUnusedRecoveryCodes menghitung recovery code yang masih bisa dipakai
+func CountUnusedRecoveryCodes(userID uuid.UUID) (int, error) {
+	var count int
+	err := config.DB.QueryRow(`
+		SELECT COUNT(*) FROM user_recovery_codes
+		WHERE user_id = $1 AND used_at IS NULL
+	`, userID).Scan(&count)
+	return count, err
+}
+
+// DisableTwoFactor menghapus konfigurasi 2FA dan recovery code milik user
+func DisableTwoFactor(userID uuid.UUID) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
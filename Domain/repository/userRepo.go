@@ -151,14 +151,43 @@ func UpdateUser(user *model.Users) error {
 }
 
 // UpdateUserPassword update password user
+// Semua sesi dan refresh token user ikut dicabut sehingga token lama tidak bisa dipakai lagi
 func UpdateUserPassword(userID uuid.UUID, newPassword string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	query := `UPDATE users SET password_hash = $1 WHERE id = $2`
-	_, err = config.DB.Exec(query, string(hashedPassword), userID)
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateUserPasswordTx(tx, userID, string(hashedPassword), time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateUserPasswordTx mengganti password hash dan mencabut semua sesi user di dalam transaksi
+func updateUserPasswordTx(tx *sql.Tx, userID uuid.UUID, passwordHash string, now time.Time) error {
+	result, err := tx.Exec(`UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3`, passwordHash, now, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user tidak ditemukan")
+	}
+
+	_, err = revokeUserSessionsTx(tx, userID, now)
 	return err
 }
 
@@ -2,16 +2,23 @@ package route
 
 import (
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // AchievementRoute - 5.4 Achievements (Tanpa Handler Eksplisit)
-func AchievementRoute(API *fiber.App) {
+func AchievementRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	// GET /api/v1/files/achievements/:id/attachments/:attachmentId - Download lewat link bertanda tangan
+	// Publik (tanpa JWT): akses dicek dari signature HMAC dan expiry di query string
+	API.Get("/api/v1/files/achievements/:id/attachments/:attachmentId",
+		middleware.CallService("AchievementService", "DownloadSignedAttachment"))
+
 	achievements := API.Group("/api/v1/achievements")
 
 	// Semua endpoint butuh JWT authentication
-	achievements.Use(middleware.JWTAuth())
+	// Endpoint per achievement (/:id) juga dicek policy kepemilikan lewat middleware.Authorize
+	achievements.Use(middleware.JWTAuth(blacklist))
 
 	// GET /api/v1/achievements/stats/my - Statistics prestasi sendiri (Mahasiswa)
 	// Permission: write_achievements
@@ -37,6 +44,11 @@ func AchievementRoute(API *fiber.App) {
 	achievements.Get("/advisee", middleware.RequirePermission("verify_achievements"),
 		middleware.CallService("AchievementService", "GetAdviseeAchievements"))
 
+	// GET /api/v1/achievements/pending-approval - Prestasi menunggu persetujuan tahap lanjutan
+	// Permission: permission tahap workflow (difilter di service)
+	achievements.Get("/pending-approval",
+		middleware.CallService("AchievementService", "GetPendingApprovals"))
+
 	// GET /api/v1/achievements - List all achievements (Admin)
 	// Permission: read_achievements
 	// FR-010: View All Achievements
@@ -47,6 +59,7 @@ func AchievementRoute(API *fiber.App) {
 	// Permission: read_achievements atau verify_achievements
 	achievements.Get("/:id",
 		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "read"),
 		middleware.CallService("AchievementService", "GetAchievementDetail"))
 
 	// POST /api/v1/achievements - Create achievement (Mahasiswa)
@@ -58,39 +71,99 @@ func AchievementRoute(API *fiber.App) {
 	// Permission: write_achievements
 	achievements.Put("/:id",
 		middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "update"),
 		middleware.CallService("AchievementService", "UpdateAchievement"))
 
 	// DELETE /api/v1/achievements/:id - Delete achievement (Mahasiswa)
 	// Permission: write_achievements
 	achievements.Delete("/:id", middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "delete"),
 		middleware.CallService("AchievementService", "DeleteAchievement"))
 
 	// POST /api/v1/achievements/:id/submit - Submit for verification
 	// Permission: write_achievements
 	achievements.Post("/:id/submit", middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "submit"),
 		middleware.CallService("AchievementService", "SubmitForVerification"))
 
-	// POST /api/v1/achievements/:id/verify - Verify achievement (Dosen Wali)
-	// Permission: verify_achievements
+	// POST /api/v1/achievements/:id/verify - Setujui tahap persetujuan saat ini
+	// Permission: required_permission tahap saat ini (default verify_achievements, dosen wali), dicek policy
 	// FR-007: Verify Prestasi
-	achievements.Post("/:id/verify", middleware.RequirePermission("verify_achievements"),
+	achievements.Post("/:id/verify",
+		middleware.Authorize("achievement", "verify"),
 		middleware.CallService("AchievementService", "VerifyAchievement"))
 
-	// POST /api/v1/achievements/:id/reject - Reject achievement (Dosen Wali)
-	// Permission: verify_achievements
+	// POST /api/v1/achievements/:id/reject - Tolak pada tahap persetujuan saat ini
+	// Permission: required_permission tahap saat ini, dicek policy
 	// FR-008: Reject Prestasi
-	achievements.Post("/:id/reject", middleware.RequirePermission("verify_achievements"),
+	achievements.Post("/:id/reject",
+		middleware.Authorize("achievement", "reject"),
 		middleware.CallService("AchievementService", "RejectAchievement"))
 
+	// POST /api/v1/achievements/:id/request-revision - Minta revisi (approver tahap saat ini)
+	// Permission: required_permission tahap saat ini, dicek policy
+	achievements.Post("/:id/request-revision",
+		middleware.Authorize("achievement", "request_revision"),
+		middleware.CallService("AchievementService", "RequestRevision"))
+
+	// POST /api/v1/achievements/:id/withdraw - Tarik kembali submit sebelum diproses reviewer (Mahasiswa)
+	// Permission: write_achievements
+	achievements.Post("/:id/withdraw", middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "withdraw"),
+		middleware.CallService("AchievementService", "WithdrawAchievement"))
+
+	// POST /api/v1/achievements/:id/revoke - Cabut verifikasi prestasi (Admin)
+	// Permission: manage_achievements, dicek policy
+	achievements.Post("/:id/revoke",
+		middleware.Authorize("achievement", "revoke"),
+		middleware.CallService("AchievementService", "RevokeAchievementVerification"))
+
+	// GET /api/v1/achievements/:id/comments - Thread komentar
+	// Permission: read_achievements atau verify_achievements
+	achievements.Get("/:id/comments",
+		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "read"),
+		middleware.CallService("AchievementService", "GetAchievementComments"))
+
+	// POST /api/v1/achievements/:id/comments - Tambah komentar (Mahasiswa pemilik / Dosen Wali)
+	// Permission: write_achievements atau verify_achievements
+	achievements.Post("/:id/comments",
+		middleware.RequireAnyPermission("write_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "comment"),
+		middleware.CallService("AchievementService", "CreateAchievementComment"))
+
 	// GET /api/v1/achievements/:id/history - Status history
 	// Permission: read_achievements atau verify_achievements
 	achievements.Get("/:id/history",
 		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "history"),
 		middleware.CallService("AchievementService", "GetAchievementHistory"))
 
+	// GET /api/v1/achievements/:id/points/preview - Dry-run perhitungan poin
+	// Permission: read_achievements atau verify_achievements
+	achievements.Get("/:id/points/preview",
+		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "read"),
+		middleware.CallService("AchievementService", "PreviewAchievementPoints"))
+
 	// POST /api/v1/achievements/:id/attachments - Upload files
 	// Permission: write_achievements
 	achievements.Post("/:id/attachments",
 		middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "upload"),
 		middleware.CallService("AchievementService", "UploadAttachments"))
+
+	// GET /api/v1/achievements/:id/attachments/:attachmentId - Download file
+	// Permission: read_achievements atau verify_achievements
+	achievements.Get("/:id/attachments/:attachmentId",
+		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("achievement", "read"),
+		middleware.CallService("AchievementService", "DownloadAttachment"))
+
+	// DELETE /api/v1/achievements/:id/attachments/:attachmentId - Delete file
+	// Permission: write_achievements
+	achievements.Delete("/:id/attachments/:attachmentId",
+		middleware.RequirePermission("write_achievements"),
+		middleware.Authorize("achievement", "delete_attachment"),
+		middleware.CallService("AchievementService", "DeleteAttachment"))
 }
@@ -0,0 +1,33 @@
+package route
+
+import (
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AchievementTypeRoute - Registry tipe prestasi dan schema customFields (Tanpa Handler Eksplisit)
+func AchievementTypeRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	achievementTypes := API.Group("/api/v1/achievement-types")
+
+	// Semua endpoint butuh JWT authentication
+	achievementTypes.Use(middleware.JWTAuth(blacklist))
+
+	// GET /api/v1/achievement-types - Field wajib/opsional dan schema customFields per tipe
+	// Semua user login (form prestasi mahasiswa dibangun dari data ini)
+	achievementTypes.Get("/",
+		middleware.CallService("AchievementTypeService", "GetAchievementTypes"))
+
+	// PUT /api/v1/achievement-types/:type/custom-fields-schema - Simpan JSON Schema customFields
+	// Permission: manage_achievement_types
+	achievementTypes.Put("/:type/custom-fields-schema",
+		middleware.RequirePermission(repository.ManageAchievementTypesPermission),
+		middleware.CallService("AchievementTypeService", "PutAchievementTypeSchema"))
+
+	// DELETE /api/v1/achievement-types/:type/custom-fields-schema - Hapus JSON Schema customFields
+	// Permission: manage_achievement_types
+	achievementTypes.Delete("/:type/custom-fields-schema",
+		middleware.RequirePermission(repository.ManageAchievementTypesPermission),
+		middleware.CallService("AchievementTypeService", "DeleteAchievementTypeSchema"))
+}
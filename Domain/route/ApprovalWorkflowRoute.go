@@ -0,0 +1,37 @@
+package route
+
+import (
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ApprovalWorkflowRoute - Administrasi workflow persetujuan prestasi (Tanpa Handler Eksplisit)
+func ApprovalWorkflowRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	workflows := API.Group("/api/v1/approval-workflows")
+
+	// Semua endpoint butuh JWT authentication dan permission manage_approval_workflows
+	workflows.Use(middleware.JWTAuth(blacklist))
+	workflows.Use(middleware.RequirePermission(repository.ManageApprovalWorkflowsPermission))
+
+	// GET /api/v1/approval-workflows - List workflow beserta tahapnya
+	workflows.Get("/",
+		middleware.CallService("ApprovalWorkflowService", "GetApprovalWorkflows"))
+
+	// POST /api/v1/approval-workflows - Buat workflow baru
+	workflows.Post("/",
+		middleware.CallService("ApprovalWorkflowService", "CreateApprovalWorkflow"))
+
+	// GET /api/v1/approval-workflows/:id - Detail workflow
+	workflows.Get("/:id",
+		middleware.CallService("ApprovalWorkflowService", "GetApprovalWorkflow"))
+
+	// PUT /api/v1/approval-workflows/:id - Ganti workflow dan seluruh tahapnya
+	workflows.Put("/:id",
+		middleware.CallService("ApprovalWorkflowService", "UpdateApprovalWorkflow"))
+
+	// DELETE /api/v1/approval-workflows/:id - Hapus workflow (kembali ke alur dosen wali)
+	workflows.Delete("/:id",
+		middleware.CallService("ApprovalWorkflowService", "DeleteApprovalWorkflow"))
+}
@@ -2,29 +2,114 @@ package route
 
 import (
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // AuthRoute - 5.1 Authentication (Tanpa Handler Eksplisit)
-func AuthRoute(API *fiber.App) {
+func AuthRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
 	auth := API.Group("/api/v1/auth")
 
+	// GET /.well-known/jwks.json - Public key untuk verifikasi token oleh service lain
+	API.Get("/.well-known/jwks.json", middleware.CallService("AuthService", "JWKS"))
+
 	// POST /api/v1/auth/login - Public route
 	auth.Post("/login", middleware.CallService("AuthService", "Login"))
 
-	// POST /api/v1/auth/refresh - Public route (TODO: implement refresh token)
+	// POST /api/v1/auth/login/2fa - Public route (langkah kedua login untuk user dengan 2FA)
+	auth.Post("/login/2fa", middleware.CallService("AuthService", "LoginTwoFactor"))
+
+	// POST /api/v1/auth/refresh - Public route (tukar refresh token dengan access token baru)
 	auth.Post("/refresh", middleware.CallService("AuthService", "Refresh"))
 
-	// POST /api/v1/auth/logout - Protected route
+	// POST /api/v1/auth/password/forgot - Public route (kirim link reset password)
+	auth.Post("/password/forgot", middleware.CallService("AuthService", "ForgotPassword"))
+
+	// POST /api/v1/auth/password/reset - Public route (reset password dengan token dari email)
+	auth.Post("/password/reset", middleware.CallService("AuthService", "ResetPassword"))
+
+	// POST /api/v1/auth/password/change - Ganti password sendiri
+	auth.Post("/password/change",
+		middleware.JWTAuth(blacklist),
+		middleware.CallService("AuthService", "ChangePassword"),
+	)
+
+	// POST /api/v1/auth/logout - Protected route (juga mengakhiri impersonation)
 	auth.Post("/logout",
-		middleware.JWTAuth(),
+		middleware.AllowImpersonatedWrite(),
+		middleware.JWTAuth(blacklist),
 		middleware.CallService("AuthService", "Logout"),
 	)
 
 	// GET /api/v1/auth/profile - Protected route
 	auth.Get("/profile",
-		middleware.JWTAuth(),
+		middleware.JWTAuth(blacklist),
 		middleware.CallService("AuthService", "GetProfile"),
 	)
-}
\ No newline at end of file
+
+	// GET /api/v1/auth/sessions - List sesi aktif milik sendiri
+	auth.Get("/sessions",
+		middleware.JWTAuth(blacklist),
+		middleware.CallService("AuthService", "GetSessions"),
+	)
+
+	// DELETE /api/v1/auth/sessions - Cabut semua sesi lain (kecuali sesi ini)
+	auth.Delete("/sessions",
+		middleware.JWTAuth(blacklist),
+		middleware.CallService("AuthService", "RevokeOtherSessions"),
+	)
+
+	// DELETE /api/v1/auth/sessions/:id - Cabut satu sesi milik sendiri
+	auth.Delete("/sessions/:id",
+		middleware.JWTAuth(blacklist),
+		middleware.CallService("AuthService", "RevokeSession"),
+	)
+
+	// /api/v1/auth/2fa - Enrollment dan pengelolaan TOTP 2FA milik sendiri
+	twoFactor := auth.Group("/2fa", middleware.JWTAuth(blacklist))
+
+	// GET /api/v1/auth/2fa - Status 2FA
+	twoFactor.Get("/", middleware.CallService("AuthService", "GetTwoFactorStatus"))
+
+	// POST /api/v1/auth/2fa/enroll - Buat secret dan otpauth URI
+	twoFactor.Post("/enroll", middleware.CallService("AuthService", "EnrollTwoFactor"))
+
+	// POST /api/v1/auth/2fa/enable - Konfirmasi kode pertama, dapatkan recovery code
+	twoFactor.Post("/enable", middleware.CallService("AuthService", "EnableTwoFactor"))
+
+	// POST /api/v1/auth/2fa/disable - Nonaktifkan 2FA
+	twoFactor.Post("/disable", middleware.CallService("AuthService", "DisableTwoFactor"))
+
+	// POST /api/v1/auth/2fa/recovery-codes - Buat ulang recovery code
+	twoFactor.Post("/recovery-codes", middleware.CallService("AuthService", "RegenerateRecoveryCodes"))
+
+	// POST /api/v1/auth/impersonate - Login sebagai user lain (token read-only, berumur pendek)
+	auth.Post("/impersonate",
+		middleware.JWTAuth(blacklist),
+		middleware.RequirePermission(repository.ImpersonateUsersPermission),
+		middleware.CallService("AuthService", "StartImpersonation"),
+	)
+
+	// POST /api/v1/auth/impersonate/stop - Akhiri impersonation (dipanggil dengan token impersonation)
+	auth.Post("/impersonate/stop",
+		middleware.AllowImpersonatedWrite(),
+		middleware.JWTAuth(blacklist),
+		middleware.CallService("AuthService", "StopImpersonation"),
+	)
+
+	// /api/v1/auth/impersonations - Audit trail impersonation
+	impersonations := auth.Group("/impersonations",
+		middleware.JWTAuth(blacklist),
+		middleware.RequirePermission(repository.ImpersonateUsersPermission),
+	)
+
+	// GET /api/v1/auth/impersonations - Riwayat impersonation
+	impersonations.Get("/", middleware.CallService("AuthService", "GetImpersonations"))
+
+	// GET /api/v1/auth/impersonations/:id - Detail beserta event
+	impersonations.Get("/:id", middleware.CallService("AuthService", "GetImpersonationDetail"))
+
+	// DELETE /api/v1/auth/impersonations/:id - Akhiri impersonation yang masih aktif
+	impersonations.Delete("/:id", middleware.CallService("AuthService", "EndImpersonation"))
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PointRuleRoute - Administrasi rule poin prestasi (Tanpa Handler Eksplisit)
+func PointRuleRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	pointRules := API.Group("/api/v1/point-rules")
+
+	// Semua endpoint butuh JWT authentication dan permission manage_point_rules
+	pointRules.Use(middleware.JWTAuth(blacklist))
+	pointRules.Use(middleware.RequirePermission(repository.ManagePointRulesPermission))
+
+	// GET /api/v1/point-rules - List versi rule set
+	pointRules.Get("/",
+		middleware.CallService("PointRuleService", "GetPointRuleSets"))
+
+	// POST /api/v1/point-rules - Buat versi rule set baru
+	pointRules.Post("/",
+		middleware.CallService("PointRuleService", "CreatePointRuleSet"))
+
+	// GET /api/v1/point-rules/active - Rule set yang sedang aktif
+	pointRules.Get("/active",
+		middleware.CallService("PointRuleService", "GetActivePointRuleSet"))
+
+	// POST /api/v1/point-rules/recompute - Hitung ulang poin prestasi verified
+	pointRules.Post("/recompute",
+		middleware.CallService("PointRuleService", "RecomputePoints"))
+
+	// GET /api/v1/point-rules/:version - Detail rule set per versi
+	pointRules.Get("/:version",
+		middleware.CallService("PointRuleService", "GetPointRuleSet"))
+
+	// POST /api/v1/point-rules/:version/activate - Aktifkan versi rule set
+	pointRules.Post("/:version/activate",
+		middleware.CallService("PointRuleService", "ActivatePointRuleSet"))
+}
@@ -2,14 +2,15 @@ package route
 
 import (
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // ReportRoute - 5.8 Reports & Analytics (Tanpa Handler Eksplisit)
-func ReportRoute(API *fiber.App) {
+func ReportRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
 	reports := API.Group("/api/v1/reports")
-	reports.Use(middleware.JWTAuth())
+	reports.Use(middleware.JWTAuth(blacklist))
 
 	// GET /api/v1/reports/statistics - General statistics
 	// Permission based on role: students see own, advisors see advisees, admins see all
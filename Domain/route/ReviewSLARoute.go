@@ -0,0 +1,37 @@
+package route
+
+import (
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReviewSLARoute - Administrasi batas waktu review dan eskalasi prestasi (Tanpa Handler Eksplisit)
+func ReviewSLARoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	slas := API.Group("/api/v1/review-slas")
+
+	// Semua endpoint butuh JWT authentication dan permission manage_review_slas
+	slas.Use(middleware.JWTAuth(blacklist))
+	slas.Use(middleware.RequirePermission(repository.ManageReviewSLAsPermission))
+
+	// GET /api/v1/review-slas - List aturan SLA beserta nilai bawaan
+	slas.Get("/",
+		middleware.CallService("ReviewSLAService", "GetReviewSLAs"))
+
+	// POST /api/v1/review-slas - Buat aturan SLA baru
+	slas.Post("/",
+		middleware.CallService("ReviewSLAService", "CreateReviewSLA"))
+
+	// GET /api/v1/review-slas/:id - Detail aturan SLA
+	slas.Get("/:id",
+		middleware.CallService("ReviewSLAService", "GetReviewSLA"))
+
+	// PUT /api/v1/review-slas/:id - Ganti aturan SLA
+	slas.Put("/:id",
+		middleware.CallService("ReviewSLAService", "UpdateReviewSLA"))
+
+	// DELETE /api/v1/review-slas/:id - Hapus aturan SLA (kembali ke batas waktu bawaan)
+	slas.Delete("/:id",
+		middleware.CallService("ReviewSLAService", "DeleteReviewSLA"))
+}
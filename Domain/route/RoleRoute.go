@@ -0,0 +1,66 @@
+package route
+
+import (
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleRoute - Administrasi role dan permission (Tanpa Handler Eksplisit)
+func RoleRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
+	roles := API.Group("/api/v1/roles")
+
+	// Semua endpoint butuh JWT authentication dan permission manage_roles
+	roles.Use(middleware.JWTAuth(blacklist))
+	roles.Use(middleware.RequirePermission(repository.ManageRolesPermission))
+
+	// GET /api/v1/roles - List role beserta permission
+	roles.Get("/",
+		middleware.CallService("RoleService", "GetRoles"))
+
+	// POST /api/v1/roles - Create role
+	roles.Post("/",
+		middleware.CallService("RoleService", "CreateRole"))
+
+	// GET /api/v1/roles/:id - Get role detail
+	roles.Get("/:id",
+		middleware.CallService("RoleService", "GetRoleDetail"))
+
+	// PUT /api/v1/roles/:id - Update role
+	roles.Put("/:id",
+		middleware.CallService("RoleService", "UpdateRole"))
+
+	// DELETE /api/v1/roles/:id - Delete role
+	roles.Delete("/:id",
+		middleware.CallService("RoleService", "DeleteRole"))
+
+	// POST /api/v1/roles/:id/permissions - Tambahkan permission ke role
+	roles.Post("/:id/permissions",
+		middleware.CallService("RoleService", "AttachRolePermission"))
+
+	// DELETE /api/v1/roles/:id/permissions/:permissionId - Lepas permission dari role
+	roles.Delete("/:id/permissions/:permissionId",
+		middleware.CallService("RoleService", "DetachRolePermission"))
+
+	permissions := API.Group("/api/v1/permissions")
+
+	permissions.Use(middleware.JWTAuth(blacklist))
+	permissions.Use(middleware.RequirePermission(repository.ManageRolesPermission))
+
+	// GET /api/v1/permissions - List permission
+	permissions.Get("/",
+		middleware.CallService("RoleService", "GetPermissions"))
+
+	// POST /api/v1/permissions - Create permission
+	permissions.Post("/",
+		middleware.CallService("RoleService", "CreatePermission"))
+
+	// PUT /api/v1/permissions/:id - Update permission
+	permissions.Put("/:id",
+		middleware.CallService("RoleService", "UpdatePermission"))
+
+	// DELETE /api/v1/permissions/:id - Delete permission
+	permissions.Delete("/:id",
+		middleware.CallService("RoleService", "DeletePermission"))
+}
@@ -2,14 +2,15 @@ package route
 
 import (
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func StudentRoute(API *fiber.App) {
+func StudentRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
 	// Students endpoints
 	students := API.Group("/api/v1/students")
-	students.Use(middleware.JWTAuth())
+	students.Use(middleware.JWTAuth(blacklist))
 
 	// GET /api/v1/students - List all students
 	students.Get("/",
@@ -19,21 +20,24 @@ func StudentRoute(API *fiber.App) {
 	// GET /api/v1/students/:id - Get student detail
 	students.Get("/:id",
 		middleware.RequirePermission("read_students"),
+		middleware.Authorize("student", "read"),
 		middleware.CallService("StudentService", "GetStudentDetail"))
 
 	// GET /api/v1/students/:id/achievements - Get student achievements
 	students.Get("/:id/achievements",
 		middleware.RequireAnyPermission("read_achievements", "verify_achievements"),
+		middleware.Authorize("student", "read_achievements"),
 		middleware.CallService("StudentService", "GetStudentAchievements"))
 
 	// PUT /api/v1/students/:id/advisor - Set student advisor
 	students.Put("/:id/advisor",
 		middleware.RequirePermission("manage_students"),
+		middleware.Authorize("student", "set_advisor"),
 		middleware.CallService("StudentService", "SetStudentAdvisor"))
 
 	// Lecturers endpoints
 	lecturers := API.Group("/api/v1/lecturers")
-	lecturers.Use(middleware.JWTAuth())
+	lecturers.Use(middleware.JWTAuth(blacklist))
 
 	// GET /api/v1/lecturers - List all lecturers
 	lecturers.Get("/",
@@ -43,5 +47,6 @@ func StudentRoute(API *fiber.App) {
 	// GET /api/v1/lecturers/:id/advisees - Get lecturer advisees
 	lecturers.Get("/:id/advisees",
 		middleware.RequirePermission("read_lecturers"),
+		middleware.Authorize("lecturer", "read_advisees"),
 		middleware.CallService("LecturerService", "GetLecturerAdvisees"))
 }
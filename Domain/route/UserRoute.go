@@ -2,16 +2,17 @@ package route
 
 import (
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // UserRoute - FR-009: Manage Users (Tanpa Handler Eksplisit)
-func UserRoute(API *fiber.App) {
+func UserRoute(API *fiber.App, blacklist repository.TokenBlacklistRepository) {
 	users := API.Group("/api/v1/users")
 
 	// Semua endpoint butuh JWT authentication dan permission manage_users
-	users.Use(middleware.JWTAuth())
+	users.Use(middleware.JWTAuth(blacklist))
 	users.Use(middleware.RequirePermission("manage_users"))
 
 	// POST /api/v1/users - Create user
@@ -45,4 +46,20 @@ func UserRoute(API *fiber.App) {
 	// POST /api/v1/users/:id/lecturer - Set lecturer profile
 	users.Post("/:id/lecturer",
 		middleware.CallService("UserService", "SetLecturerProfile"))
+
+	// GET /api/v1/users/:id/sessions - List sesi aktif user
+	users.Get("/:id/sessions",
+		middleware.CallService("UserService", "GetUserSessions"))
+
+	// DELETE /api/v1/users/:id/sessions - Force logout user dari semua sesi
+	users.Delete("/:id/sessions",
+		middleware.CallService("UserService", "ForceLogout"))
+
+	// POST /api/v1/users/:id/unlock - Buka kunci login user setelah lockout
+	users.Post("/:id/unlock",
+		middleware.CallService("UserService", "UnlockUser"))
+
+	// DELETE /api/v1/users/:id/2fa - Reset 2FA user (perangkat dan recovery code hilang)
+	users.Delete("/:id/2fa",
+		middleware.CallService("UserService", "ResetTwoFactor"))
 }
@@ -1,6 +1,7 @@
 package route
 
 import (
+	"GOLANG/Domain/config"
 	"database/sql"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,7 +9,10 @@ import (
 )
 
 func NewApp(db *sql.DB) *fiber.App {
-    app := fiber.New()
+    // Body limit mengikuti batas ukuran lampiran (+1 MB untuk overhead multipart)
+    app := fiber.New(fiber.Config{
+        BodyLimit: int(config.GetAttachmentMaxSize()) + 1<<20,
+    })
     
     app.Use(logger.New())
 
@@ -0,0 +1,263 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batas panjang isi komentar
+const maxCommentLength = 5000
+
+// GetAchievementCommentsService - Thread komentar prestasi
+// @Summary Get achievement comments
+// @Description Discussion thread of an achievement, oldest first. Replies carry parent_id. Accessible by the owning student, their advisor, admins and the approver of a pending workflow stage.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/comments [get]
+func GetAchievementCommentsService(c *fiber.Ctx) error {
+	if _, err := primitive.ObjectIDFromHex(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan akses sudah dicek oleh policy achievement:read
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	comments, err := repository.GetAchievementComments(reference.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil komentar achievement",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil komentar achievement",
+		"data": fiber.Map{
+			"achievement_id": reference.MongoAchievementID,
+			"reference_id":   reference.ID,
+			"status":         reference.Status,
+			"comments":       comments,
+		},
+	})
+}
+
+// CreateAchievementCommentService - Tambah komentar ke thread prestasi
+// @Summary Add achievement comment
+// @Description Add a comment (or a reply with parent_id) to the achievement thread. attachment_ids may reference attachments of this achievement. Owner and advisor only.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param comment body model.CreateCommentRequest true "Comment"
+// @Success 201 {object} map[string]interface{} "Comment created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/comments [post]
+func CreateAchievementCommentService(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	body, errResp := commentBody(c, req.Body)
+	if body == "" {
+		return errResp
+	}
+
+	var parentID *uuid.UUID
+	if req.ParentID != "" {
+		parsed, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid parent_id",
+			})
+		}
+		parentID = &parsed
+	}
+
+	// Reference sudah dimuat dan akses sudah dicek oleh policy achievement:comment
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Balasan hanya boleh ke komentar di thread achievement yang sama
+	if parentID != nil {
+		parentReferenceID, err := repository.GetAchievementCommentReferenceID(*parentID)
+		if err != nil || parentReferenceID != reference.ID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "parent_id bukan komentar di achievement ini",
+			})
+		}
+	}
+
+	if ok, err := checkCommentAttachments(c, objectID, req.AttachmentIDs); !ok {
+		return err
+	}
+
+	comment := newAchievementComment(c, model.CommentKindComment, body, req.AttachmentIDs)
+	comment.AchievementReferenceID = reference.ID
+	comment.ParentID = parentID
+
+	if err := repository.CreateAchievementComment(comment); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan komentar",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Komentar berhasil ditambahkan",
+		"data":    comment,
+	})
+}
+
+// RequestRevisionService - Minta revisi prestasi (approver tahap saat ini)
+// @Summary Request achievement revision
+// @Description Send a submitted achievement back to the student with a comment, by the approver of its current workflow stage. Status becomes revision_requested; the student can edit and submit again, starting from the first stage.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param revision body model.RequestRevisionRequest true "What needs to be revised"
+// @Success 200 {object} map[string]interface{} "Revision requested"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
+// @Router /api/v1/achievements/{id}/request-revision [post]
+func RequestRevisionService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	var req model.RequestRevisionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	body, errResp := commentBody(c, req.Body)
+	if body == "" {
+		return errResp
+	}
+
+	// Reference sudah dimuat dan approver tahap sudah dicek oleh policy achievement:request_revision
+	reference, student, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	if ok, err := checkAchievementTransition(c, model.AchievementActionRequestRevision, reference); !ok {
+		return err
+	}
+
+	if ok, err := checkCommentAttachments(c, objectID, req.AttachmentIDs); !ok {
+		return err
+	}
+
+	// Status menjadi revision_requested dan komentar tersimpan dalam transaksi yang sama
+	comment := newAchievementComment(c, model.CommentKindRevisionRequest, body, req.AttachmentIDs)
+	transition := &achievementTransition{
+		Action:    model.AchievementActionRequestRevision,
+		Reference: reference,
+		Note:      &body,
+		Comment:   comment,
+	}
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permintaan revisi berhasil dikirim",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"reference_id":   reference.ID,
+			"status":         reference.Status,
+			"comment":        comment,
+			"student_id":     student.StudentID,
+		},
+	})
+}
+
+// commentBody merapikan isi komentar; string kosong berarti response 400 sudah ditulis
+func commentBody(c *fiber.Ctx, raw string) (string, error) {
+	body := strings.TrimSpace(raw)
+	if body == "" {
+		return "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Isi komentar wajib diisi",
+		})
+	}
+	if len([]rune(body)) > maxCommentLength {
+		return "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Isi komentar maksimal 5000 karakter",
+		})
+	}
+	return body, nil
+}
+
+// checkCommentAttachments memastikan attachment_ids merujuk ke lampiran achievement ini
+// Jika tidak valid, response sudah ditulis dan ok bernilai false.
+func checkCommentAttachments(c *fiber.Ctx, objectID primitive.ObjectID, attachmentIDs []string) (bool, error) {
+	if len(attachmentIDs) == 0 {
+		return true, nil
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return false, achievementLoadErrorResponse(c, err)
+	}
+	for _, attachmentID := range attachmentIDs {
+		if findAttachment(achievement, attachmentID) == nil {
+			return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":         "Lampiran tidak ditemukan di achievement ini",
+				"attachment_id": attachmentID,
+			})
+		}
+	}
+	return true, nil
+}
+
+// newAchievementComment komentar dari user yang sedang login
+func newAchievementComment(c *fiber.Ctx, kind, body string, attachmentIDs []string) *model.AchievementComments {
+	actor := statusHistoryEntry(c, "", nil)
+	return &model.AchievementComments{
+		AuthorID:      actor.ActorID,
+		AuthorRole:    actor.ActorRole,
+		Kind:          kind,
+		Body:          body,
+		AttachmentIDs: attachmentIDs,
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import "GOLANG/Domain/repository"
+
+var achievementDocuments repository.AchievementDocumentRepository = repository.MongoAchievementDocuments{}
+
+// SetAchievementDocuments mengganti sumber dokumen achievement yang dipakai transisi status dan poin
+func SetAchievementDocuments(r repository.AchievementDocumentRepository) {
+	achievementDocuments = r
+}
+
+var achievementReferences repository.AchievementReferenceRepository = repository.PostgresAchievementReferences{}
+
+// SetAchievementReferences mengganti penyimpanan transisi status reference yang dipakai state machine
+func SetAchievementReferences(r repository.AchievementReferenceRepository) {
+	achievementReferences = r
+}
@@ -4,6 +4,9 @@ import (
 	model "GOLANG/Domain/model/Postgresql"
 	mongodb "GOLANG/Domain/model/mongoDB"
 	"GOLANG/Domain/repository"
+	"errors"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -46,19 +49,13 @@ func SubmitAchievementService(c *fiber.Ctx) error {
 	}
 
 	// Validasi achievement type
-	validTypes := map[string]bool{
-		"academic":      true,
-		"competition":   true,
-		"organization":  true,
-		"publication":   true,
-		"certification": true,
-		"other":         true,
+	if !validAchievementType(req.AchievementType) {
+		return invalidAchievementTypeResponse(c)
 	}
 
-	if !validTypes[req.AchievementType] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Achievement type tidak valid. Pilihan: academic, competition, organization, publication, certification, other",
-		})
+	// Validasi details dan customFields sesuai registry tipe prestasi
+	if ok, err := validateAchievementContent(c, req.AchievementType, &req.Details, req.CustomFields); !ok {
+		return err
 	}
 
 	// Ambil user_id dari context (dari JWT middleware)
@@ -78,17 +75,16 @@ func SubmitAchievementService(c *fiber.Ctx) error {
 		})
 	}
 
-	// Flow 2: Mahasiswa upload dokumen pendukung (sudah ada di req.Attachments)
-	// Validasi attachments jika ada
-	if req.Attachments == nil {
-		req.Attachments = []mongodb.Attachment{}
-	}
+	// Flow 2: Dokumen pendukung diunggah lewat POST /achievements/:id/attachments
+	// URL lampiran kiriman client tidak dipercaya, jadi diabaikan
+	req.Attachments = []mongodb.Attachment{}
 
 	// Flow 3: Sistem simpan ke MongoDB (achievement) dan PostgreSQL (reference)
 
 	// 3a. Set data yang diperlukan untuk achievement
 	req.StudentID = student.ID
-	req.Points = 0 // Default 0, bisa dihitung nanti berdasarkan rules
+	req.PointsVersion = 0
+	req.Points = 0 // Dihitung dari rule set poin aktif saat diverifikasi
 
 	// Set timestamps
 	now := time.Now()
@@ -113,7 +109,7 @@ func SubmitAchievementService(c *fiber.Ctx) error {
 		SubmittedAt:        nil,     // Belum di-submit untuk verifikasi
 	}
 
-	err = repository.CreateAchievementReference(reference)
+	err = repository.CreateAchievementReference(reference, statusHistoryEntry(c, "", nil))
 	if err != nil {
 		// Rollback: Hapus achievement dari MongoDB jika gagal simpan reference
 		_ = repository.DeleteAchievement(savedAchievement.ID)
@@ -137,9 +133,60 @@ func SubmitAchievementService(c *fiber.Ctx) error {
 	})
 }
 
+// policyAchievementReference reference dan mahasiswa pemilik yang sudah dimuat oleh
+// middleware.Authorize("achievement", ...) di route. Cek kepemilikan/dosen wali ada di policy.
+func policyAchievementReference(c *fiber.Ctx) (*model.AchievementReferences, *model.Students, bool) {
+	reference, ok := c.Locals("achievement_reference").(*model.AchievementReferences)
+	if !ok {
+		return nil, nil, false
+	}
+	student, ok := c.Locals("achievement_student").(*model.Students)
+	return reference, student, ok
+}
+
+// policyNotEvaluatedResponse ditolak jika handler dipasang tanpa policy (fail closed)
+func policyNotEvaluatedResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": "Anda tidak memiliki akses ke achievement ini",
+	})
+}
+
+// currentLecturer data dosen milik user yang sedang login
+func currentLecturer(c *fiber.Ctx) (*model.Lecturers, error) {
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	return repository.GetLecturerByUserID(userUUID)
+}
+
+// statusHistoryEntry entri riwayat status untuk transisi yang dilakukan user yang sedang login
+// fromStatus kosong berarti status awal (achievement baru dibuat)
+func statusHistoryEntry(c *fiber.Ctx, fromStatus string, note *string) *model.AchievementStatusHistory {
+	entry := &model.AchievementStatusHistory{Note: note}
+	if fromStatus != "" {
+		entry.FromStatus = &fromStatus
+	}
+
+	userID, _ := c.Locals("id").(string)
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		entry.ActorID = &userUUID
+	}
+
+	roleID, _ := c.Locals("role_id").(string)
+	if roleUUID, err := uuid.Parse(roleID); err == nil {
+		if role, err := repository.GetRoleByID(roleUUID); err == nil {
+			entry.ActorRole = role.Name
+		}
+	}
+
+	return entry
+}
+
 // SubmitForVerificationService - FR-004: Submit untuk Verifikasi
 // @Summary Submit achievement for verification
-// @Description Submit draft achievement for verification by advisor (Mahasiswa)
+// @Description Submit a draft achievement, or resubmit one in revision_requested, for verification by advisor (Mahasiswa)
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -149,69 +196,48 @@ func SubmitAchievementService(c *fiber.Ctx) error {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 403 {object} map[string]interface{} "Forbidden"
 // @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
 // @Router /api/v1/achievements/{id}/submit [post]
 func SubmitForVerificationService(c *fiber.Ctx) error {
 	// Flow 1: Mahasiswa submit prestasi
 	achievementID := c.Params("id")
 
 	// Validasi achievement ID
-	_, err := primitive.ObjectIDFromHex(achievementID)
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid achievement ID",
 		})
 	}
 
-	// Get user_id dari JWT context
-	userID := c.Locals("id").(string)
-	userUUID, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:submit
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
 	}
 
-	// Cek apakah user adalah mahasiswa
-	student, err := repository.GetStudentByUserID(userUUID)
-	if err != nil {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "User bukan mahasiswa",
-		})
+	// Precondition: status asal submit ada di tabel transisi (draft atau revision_requested)
+	if ok, err := checkAchievementTransition(c, model.AchievementActionSubmit, reference); !ok {
+		return err
 	}
 
-	// Ambil achievement reference dari PostgreSQL
-	reference, err := repository.GetAchievementReferenceByMongoID(achievementID)
+	// Data prestasi divalidasi ulang: schema customFields bisa berubah sejak draft dibuat
+	achievement, err := loadActiveAchievement(objectID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Achievement tidak ditemukan",
-		})
+		return achievementLoadErrorResponse(c, err)
 	}
-
-	// Validasi: Cek apakah user adalah pemilik achievement
-	if reference.StudentID != student.ID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Anda tidak memiliki akses ke achievement ini",
-		})
+	if ok, err := validateAchievementContent(c, achievement.AchievementType, &achievement.Details, achievement.CustomFields); !ok {
+		return err
 	}
 
-	// Precondition: Cek apakah status masih 'draft'
-	if reference.Status != "draft" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":          "Achievement hanya bisa di-submit jika berstatus draft",
-			"current_status": reference.Status,
-		})
+	// Flow 2: Update status menjadi 'submitted'; pre hook memilih workflow persetujuan
+	transition := &achievementTransition{
+		Action:      model.AchievementActionSubmit,
+		Reference:   reference,
+		Achievement: achievement,
 	}
-
-	// Flow 2: Update status menjadi 'submitted'
-	now := time.Now()
-	reference.Status = "submitted"
-	reference.SubmittedAt = &now
-
-	err = repository.UpdateAchievementReference(reference)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal update status achievement",
-		})
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
 	}
 
 	// Flow 3: Return updated status
@@ -222,6 +248,8 @@ func SubmitForVerificationService(c *fiber.Ctx) error {
 			"reference_id":   reference.ID,
 			"status":         reference.Status,
 			"submitted_at":   reference.SubmittedAt,
+			"current_stage":  reference.CurrentStage,
+			"stages":         transition.Stages,
 		},
 	})
 }
@@ -238,6 +266,7 @@ func SubmitForVerificationService(c *fiber.Ctx) error {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 403 {object} map[string]interface{} "Forbidden"
 // @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
 // @Router /api/v1/achievements/{id} [delete]
 func DeleteAchievementService(c *fiber.Ctx) error {
 	// Get achievement_id dari URL parameter
@@ -251,60 +280,26 @@ func DeleteAchievementService(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user_id dari JWT context
-	userID := c.Locals("id").(string)
-	userUUID, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:delete
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
 	}
 
-	// Cek apakah user adalah mahasiswa
-	student, err := repository.GetStudentByUserID(userUUID)
-	if err != nil {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "User bukan mahasiswa",
-		})
+	// Flow 1: Delete reference di PostgreSQL, hanya jika masih draft (compare-and-set)
+	transition := &achievementTransition{
+		Action:    model.AchievementActionDelete,
+		Reference: reference,
 	}
-
-	// Ambil achievement reference dari PostgreSQL
-	reference, err := repository.GetAchievementReferenceByMongoID(achievementID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Achievement tidak ditemukan",
-		})
-	}
-
-	// Validasi: Cek apakah user adalah pemilik achievement
-	if reference.StudentID != student.ID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Anda tidak memiliki akses ke achievement ini",
-		})
-	}
-
-	// Precondition: Cek apakah status masih 'draft'
-	if reference.Status != "draft" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":          "Hanya achievement dengan status draft yang bisa dihapus",
-			"current_status": reference.Status,
-		})
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
 	}
 
-	// Flow 1: Soft delete data di MongoDB
-	err = repository.SoftDeleteAchievement(objectID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal menghapus achievement",
-		})
-	}
-
-	// Flow 2: Delete reference di PostgreSQL
-	err = repository.DeleteAchievementReference(reference.ID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal update reference",
-		})
+	// Flow 2: Soft delete data di MongoDB
+	// Reference sudah terhapus sehingga achievement tidak lagi bisa diakses; kegagalan di sini
+	// hanya meninggalkan dokumen yatim dan tidak membatalkan penghapusan
+	if err := repository.SoftDeleteAchievement(objectID); err != nil {
+		log.Printf("Gagal soft delete achievement %s di MongoDB: %v", achievementID, err)
 	}
 
 	// Flow 3: Return success message
@@ -319,7 +314,7 @@ func DeleteAchievementService(c *fiber.Ctx) error {
 
 // GetAdviseeAchievementsService - FR-006: View Prestasi Mahasiswa Bimbingan
 // @Summary View advisee achievements
-// @Description Get achievements of students under advisor supervision (Dosen Wali)
+// @Description Get achievements of students under advisor supervision (Dosen Wali). Submitted items include days_waiting and an overdue flag against their review SLA.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -429,6 +424,7 @@ func GetAdviseeAchievementsService(c *fiber.Ctx) error {
 	// Create map untuk lookup achievement by mongo ID
 	achievementMap := make(map[string]*mongodb.Achievement)
 	for i := range achievements {
+		withSignedAttachmentURLs(&achievements[i])
 		achievementMap[achievements[i].ID.Hex()] = &achievements[i]
 	}
 
@@ -440,22 +436,28 @@ func GetAdviseeAchievementsService(c *fiber.Ctx) error {
 
 	// Flow 4: Combine data dan return list dengan pagination
 	type AchievementResponse struct {
-		ReferenceID   uuid.UUID            `json:"reference_id"`
-		AchievementID string               `json:"achievement_id"`
-		StudentID     string               `json:"student_id"`
-		StudentName   string               `json:"student_name"`
-		ProgramStudy  string               `json:"program_study"`
-		Status        string               `json:"status"`
-		SubmittedAt   *time.Time           `json:"submitted_at"`
-		VerifiedAt    *time.Time           `json:"verified_at"`
-		Achievement   *mongodb.Achievement `json:"achievement"`
-		CreatedAt     time.Time            `json:"created_at"`
+		ReferenceID   uuid.UUID               `json:"reference_id"`
+		AchievementID string                  `json:"achievement_id"`
+		StudentID     string                  `json:"student_id"`
+		StudentName   string                  `json:"student_name"`
+		ProgramStudy  string                  `json:"program_study"`
+		Status        model.AchievementStatus `json:"status"`
+		SubmittedAt   *time.Time              `json:"submitted_at"`
+		VerifiedAt    *time.Time              `json:"verified_at"`
+		ReviewDueAt   *time.Time              `json:"review_due_at"`
+		DaysWaiting   *int                    `json:"days_waiting"`
+		Overdue       bool                    `json:"overdue"`
+		Escalated     bool                    `json:"escalated"`
+		Achievement   *mongodb.Achievement    `json:"achievement"`
+		CreatedAt     time.Time               `json:"created_at"`
 	}
 
+	now := time.Now()
 	results := make([]AchievementResponse, 0, len(references))
 	for _, ref := range references {
 		achievement := achievementMap[ref.MongoAchievementID]
 		student := studentMap[ref.StudentID]
+		daysWaiting, overdue := ReviewSLAProgress(&ref, now)
 
 		if achievement != nil && student != nil {
 			results = append(results, AchievementResponse{
@@ -467,6 +469,10 @@ func GetAdviseeAchievementsService(c *fiber.Ctx) error {
 				Status:        ref.Status,
 				SubmittedAt:   ref.SubmittedAt,
 				VerifiedAt:    ref.VerifiedAt,
+				ReviewDueAt:   ref.ReviewDueAt,
+				DaysWaiting:   daysWaiting,
+				Overdue:       overdue,
+				Escalated:     ref.EscalatedAt != nil,
 				Achievement:   achievement,
 				CreatedAt:     ref.CreatedAt,
 			})
@@ -492,7 +498,7 @@ func GetAdviseeAchievementsService(c *fiber.Ctx) error {
 
 // VerifyAchievementService - FR-007: Verify Prestasi
 // @Summary Verify achievement
-// @Description Verify submitted achievement (Dosen Wali)
+// @Description Approve the current workflow stage of a submitted achievement. The item moves to the next stage, or becomes verified after the last stage. Without a workflow the advisor's approval is final.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -502,6 +508,7 @@ func GetAdviseeAchievementsService(c *fiber.Ctx) error {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 403 {object} map[string]interface{} "Forbidden"
 // @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
 // @Router /api/v1/achievements/{id}/verify [post]
 func VerifyAchievementService(c *fiber.Ctx) error {
 	// Get achievement_id dari URL parameter
@@ -515,67 +522,43 @@ func VerifyAchievementService(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user_id dari JWT context
-	userID := c.Locals("id").(string)
-	userUUID, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
-	}
-
-	// Flow 1: Validasi user adalah dosen wali
-	lecturer, err := repository.GetLecturerByUserID(userUUID)
-	if err != nil {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "User bukan dosen atau data dosen tidak ditemukan",
-		})
+	// Flow 1: Reference sudah dimuat dan approver tahap sudah dicek oleh policy achievement:verify
+	reference, student, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
 	}
 
-	// Get achievement reference dari PostgreSQL
-	reference, err := repository.GetAchievementReferenceByMongoID(achievementID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Achievement tidak ditemukan",
-		})
+	// Flow 2: Approver tahap saat ini approve prestasi
+	// Flow 3: Tahap terakhir mengubah status menjadi 'verified' (verified_by dan verified_at diisi
+	// pre hook, poin dihitung post hook); tahap sebelumnya hanya memajukan current_stage
+	transition := &achievementTransition{
+		Action:    model.AchievementActionVerify,
+		Reference: reference,
 	}
-
-	// Precondition: Cek apakah status 'submitted'
-	if reference.Status != "submitted" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":          "Achievement hanya bisa diverifikasi jika berstatus submitted",
-			"current_status": reference.Status,
-		})
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
 	}
 
-	// Validasi: Cek apakah mahasiswa adalah anak bimbingan dosen ini
-	student, err := repository.GetStudentByID(reference.StudentID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Data mahasiswa tidak ditemukan",
-		})
-	}
-
-	// Cek apakah advisor_id mahasiswa sama dengan lecturer ID
-	if student.AdvisorID != lecturer.ID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Anda tidak memiliki akses untuk memverifikasi prestasi mahasiswa ini",
-		})
-	}
-
-	// Flow 2: Dosen approve prestasi
-	// Flow 3: Update status menjadi 'verified'
-	now := time.Now()
-	reference.Status = "verified"
-	reference.VerifiedAt = &now
-	reference.VerifiedBy = &lecturer.ID
-	reference.RejectionNote = nil // Clear rejection note jika ada
+	// Bukan tahap terakhir: status tetap submitted, menunggu tahap berikutnya
+	if transition.Action == model.AchievementActionApproveStage {
+		nextStage, _, err := repository.GetCurrentApprovalStage(reference)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal mengambil tahap persetujuan",
+			})
+		}
 
-	// Flow 4: Set verified_by dan verified_at (sudah dilakukan di atas)
-	err = repository.UpdateAchievementReference(reference)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal update status achievement",
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "Tahap " + transition.Stage.Name + " disetujui, menunggu tahap " + nextStage.Name,
+			"data": fiber.Map{
+				"achievement_id": achievementID,
+				"reference_id":   reference.ID,
+				"status":         reference.Status,
+				"approved_stage": transition.Stage,
+				"current_stage":  reference.CurrentStage,
+				"next_stage":     nextStage,
+				"student_id":     student.StudentID,
+			},
 		})
 	}
 
@@ -589,10 +572,42 @@ func VerifyAchievementService(c *fiber.Ctx) error {
 			"verified_at":    reference.VerifiedAt,
 			"verified_by":    reference.VerifiedBy,
 			"student_id":     student.StudentID,
+			"points":         transition.Points,
+			"points_version": transition.PointsVersion,
 		},
 	})
 }
 
+// awardVerifiedAchievementPoints menghitung dan menyimpan poin prestasi yang baru diverifikasi
+func awardVerifiedAchievementPoints(achievementID string) (int, int) {
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return 0, 0
+	}
+
+	set, err := repository.GetActivePointRuleSet()
+	if err != nil {
+		log.Printf("Gagal mengambil rule set poin aktif: %v", err)
+		return 0, 0
+	}
+	if set == nil {
+		return 0, 0
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		log.Printf("Gagal mengambil achievement %s untuk hitung poin: %v", achievementID, err)
+		return 0, 0
+	}
+
+	points, _ := CalculatePoints(set.Rules, achievement)
+	if err := achievementDocuments.SetAchievementPoints(objectID, points, set.Version); err != nil {
+		log.Printf("Gagal menyimpan poin achievement %s: %v", achievementID, err)
+		return 0, 0
+	}
+	return points, set.Version
+}
+
 // RejectAchievementRequest DTO untuk request reject prestasi
 // Struct ini tetap diperlukan karena hanya berisi rejection note saja
 type RejectAchievementRequest struct {
@@ -601,7 +616,7 @@ type RejectAchievementRequest struct {
 
 // RejectAchievementService - FR-008: Reject Prestasi
 // @Summary Reject achievement
-// @Description Reject submitted achievement with note (Dosen Wali)
+// @Description Reject a submitted achievement with note at its current workflow stage. The achievement goes back to the student; a resubmission starts again from the first stage.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -612,6 +627,7 @@ type RejectAchievementRequest struct {
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 403 {object} map[string]interface{} "Forbidden"
 // @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
 // @Router /api/v1/achievements/{id}/reject [post]
 func RejectAchievementService(c *fiber.Ctx) error {
 	// Get achievement_id dari URL parameter
@@ -640,66 +656,21 @@ func RejectAchievementService(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user_id dari JWT context
-	userID := c.Locals("id").(string)
-	userUUID, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
-	}
-
-	// Validasi user adalah dosen wali
-	lecturer, err := repository.GetLecturerByUserID(userUUID)
-	if err != nil {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "User bukan dosen atau data dosen tidak ditemukan",
-		})
-	}
-
-	// Get achievement reference dari PostgreSQL
-	reference, err := repository.GetAchievementReferenceByMongoID(achievementID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Achievement tidak ditemukan",
-		})
-	}
-
-	// Precondition: Cek apakah status 'submitted'
-	if reference.Status != "submitted" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":          "Achievement hanya bisa ditolak jika berstatus submitted",
-			"current_status": reference.Status,
-		})
-	}
-
-	// Validasi: Cek apakah mahasiswa adalah anak bimbingan dosen ini
-	student, err := repository.GetStudentByID(reference.StudentID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Data mahasiswa tidak ditemukan",
-		})
-	}
-
-	// Cek apakah advisor_id mahasiswa sama dengan lecturer ID
-	if student.AdvisorID != lecturer.ID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Anda tidak memiliki akses untuk menolak prestasi mahasiswa ini",
-		})
+	// Reference sudah dimuat dan approver tahap sudah dicek oleh policy achievement:reject
+	reference, student, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
 	}
 
 	// Flow 2: Update status menjadi 'rejected'
-	// Flow 3: Save rejection_note
-	reference.Status = "rejected"
-	reference.RejectionNote = &req.RejectionNote
-	reference.VerifiedAt = nil // Clear verified_at
-	reference.VerifiedBy = nil // Clear verified_by
-
-	err = repository.UpdateAchievementReference(reference)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal update status achievement",
-		})
+	// Flow 3: Save rejection_note (pre hook, submit ulang dimulai dari tahap pertama)
+	transition := &achievementTransition{
+		Action:    model.AchievementActionReject,
+		Reference: reference,
+		Note:      &req.RejectionNote,
+	}
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
 	}
 
 	// Flow 4: Return updated status
@@ -710,6 +681,7 @@ func RejectAchievementService(c *fiber.Ctx) error {
 			"reference_id":   reference.ID,
 			"status":         reference.Status,
 			"rejection_note": reference.RejectionNote,
+			"rejected_stage": transition.Stage,
 			"student_id":     student.StudentID,
 		},
 	})
@@ -724,7 +696,7 @@ func RejectAchievementService(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param status query string false "Filter by status" Enums(draft, submitted, verified, rejected)
+// @Param status query string false "Filter by status" Enums(draft, submitted, revision_requested, verified, rejected)
 // @Param student_id query string false "Filter by student UUID"
 // @Param sort query string false "Sort by field" Enums(created_at, submitted_at, verified_at, updated_at) default(created_at)
 // @Param order query string false "Sort order" Enums(asc, desc) default(desc)
@@ -792,6 +764,7 @@ func GetAllAchievementsService(c *fiber.Ctx) error {
 	// Create map untuk lookup achievement by mongo ID
 	achievementMap := make(map[string]*mongodb.Achievement)
 	for i := range achievements {
+		withSignedAttachmentURLs(&achievements[i])
 		achievementMap[achievements[i].ID.Hex()] = &achievements[i]
 	}
 
@@ -811,17 +784,17 @@ func GetAllAchievementsService(c *fiber.Ctx) error {
 
 	// Flow 3: Combine data dan return dengan pagination
 	type AchievementResponse struct {
-		ReferenceID   uuid.UUID            `json:"reference_id"`
-		AchievementID string               `json:"achievement_id"`
-		StudentID     string               `json:"student_id"`
-		ProgramStudy  string               `json:"program_study"`
-		Status        string               `json:"status"`
-		SubmittedAt   *time.Time           `json:"submitted_at"`
-		VerifiedAt    *time.Time           `json:"verified_at"`
-		VerifiedBy    *uuid.UUID           `json:"verified_by"`
-		RejectionNote *string              `json:"rejection_note"`
-		Achievement   *mongodb.Achievement `json:"achievement"`
-		CreatedAt     time.Time            `json:"created_at"`
+		ReferenceID   uuid.UUID               `json:"reference_id"`
+		AchievementID string                  `json:"achievement_id"`
+		StudentID     string                  `json:"student_id"`
+		ProgramStudy  string                  `json:"program_study"`
+		Status        model.AchievementStatus `json:"status"`
+		SubmittedAt   *time.Time              `json:"submitted_at"`
+		VerifiedAt    *time.Time              `json:"verified_at"`
+		VerifiedBy    *uuid.UUID              `json:"verified_by"`
+		RejectionNote *string                 `json:"rejection_note"`
+		Achievement   *mongodb.Achievement    `json:"achievement"`
+		CreatedAt     time.Time               `json:"created_at"`
 	}
 
 	results := make([]AchievementResponse, 0, len(references))
@@ -931,12 +904,13 @@ func GetMyAchievementStatsService(c *fiber.Ctx) error {
 	// Get statistics
 	statsByType, _ := repository.GetAchievementStatsByType(mongoIDs)
 	statsByPeriod, _ := repository.GetAchievementStatsByPeriod(mongoIDs)
-	competitionLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	rawLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	competitionLevelDist := canonicalLevelDistribution(rawLevelDist)
 
 	// Count by status
 	statsByStatus := make(map[string]int)
 	for _, ref := range references {
-		statsByStatus[ref.Status]++
+		statsByStatus[string(ref.Status)]++
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -1038,7 +1012,8 @@ func GetAdviseeAchievementStatsService(c *fiber.Ctx) error {
 	// Get statistics
 	statsByType, _ := repository.GetAchievementStatsByType(mongoIDs)
 	statsByPeriod, _ := repository.GetAchievementStatsByPeriod(mongoIDs)
-	competitionLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	rawLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	competitionLevelDist := canonicalLevelDistribution(rawLevelDist)
 	statsByStatus, _ := repository.GetAchievementCountByStatus(studentIDs)
 
 	// Get top students
@@ -1130,7 +1105,8 @@ func GetAllAchievementStatsService(c *fiber.Ctx) error {
 	// Get statistics
 	statsByType, _ := repository.GetAchievementStatsByType(mongoIDs)
 	statsByPeriod, _ := repository.GetAchievementStatsByPeriod(mongoIDs)
-	competitionLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	rawLevelDist, _ := repository.GetCompetitionLevelDistribution(mongoIDs)
+	competitionLevelDist := canonicalLevelDistribution(rawLevelDist)
 	statsByStatus, _ := repository.GetAchievementCountByStatus(studentIDs)
 
 	// Get top students
@@ -1167,3 +1143,319 @@ func GetAllAchievementStatsService(c *fiber.Ctx) error {
 		},
 	})
 }
+
+// lecturerSummary ringkasan data dosen (dosen wali / verifikator); nil jika tidak ditemukan
+func lecturerSummary(lecturerID uuid.UUID) fiber.Map {
+	if lecturerID == uuid.Nil {
+		return nil
+	}
+
+	lecturer, err := repository.GetLecturerByID(lecturerID)
+	if err != nil {
+		return nil
+	}
+
+	data := fiber.Map{
+		"id":          lecturer.ID,
+		"lecturer_id": lecturer.LecturerID,
+		"department":  lecturer.Department,
+	}
+	if user, err := repository.GetUserByID(lecturer.UserID); err == nil {
+		data["full_name"] = user.FullName
+		data["email"] = user.Email
+	}
+	return data
+}
+
+// GetAchievementDetailService - Detail prestasi
+// @Summary Get achievement detail
+// @Description Get achievement detail merged from PostgreSQL (status, verification) and MongoDB (details, attachments). Accessible by the owning student, their advisor, admins and the approver of a pending workflow stage.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id} [get]
+func GetAchievementDetailService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan akses sudah dicek oleh policy achievement:read
+	reference, student, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Detail prestasi dari MongoDB (yang sudah di-soft delete dianggap tidak ada)
+	achievement, err := repository.GetAchievementByID(objectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil detail achievement dari MongoDB",
+		})
+	}
+	if achievement == nil || achievement.DeletedAt != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Achievement tidak ditemukan",
+		})
+	}
+
+	// Identitas mahasiswa pemilik
+	studentData := fiber.Map{
+		"id":            student.ID,
+		"student_id":    student.StudentID,
+		"program_study": student.ProgramStudy,
+		"academic_year": student.AcademicYear,
+	}
+	if user, err := repository.GetUserByID(student.UserID); err == nil {
+		studentData["full_name"] = user.FullName
+		studentData["email"] = user.Email
+	}
+
+	// Link download lampiran yang bisa dibuka di browser tanpa bearer token
+	withSignedAttachmentURLs(achievement)
+
+	var verifier fiber.Map
+	if reference.VerifiedBy != nil {
+		verifier = lecturerSummary(*reference.VerifiedBy)
+	}
+
+	// Thread diskusi mahasiswa dan dosen wali
+	comments, err := repository.GetAchievementComments(reference.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil komentar achievement",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil detail achievement",
+		"data": fiber.Map{
+			"reference_id":   reference.ID,
+			"achievement_id": reference.MongoAchievementID,
+			"status":         reference.Status,
+			"submitted_at":   reference.SubmittedAt,
+			"verified_at":    reference.VerifiedAt,
+			"verified_by":    verifier,
+			"rejection_note": reference.RejectionNote,
+			"workflow_id":    reference.WorkflowID,
+			"current_stage":  reference.CurrentStage,
+			"created_at":     reference.CreatedAt,
+			"updated_at":     reference.UpdatedAt,
+			"achievement":    achievement,
+			"student":        studentData,
+			"advisor":        lecturerSummary(student.AdvisorID),
+			"comments":       comments,
+			"access":         c.Locals("policy_rule"),
+		},
+	})
+}
+
+// blankString true jika field opsional kosong atau hanya spasi
+func blankString(value *string) bool {
+	return value == nil || strings.TrimSpace(*value) == ""
+}
+
+// UpdateAchievementService - Edit prestasi (Mahasiswa)
+// @Summary Update achievement
+// @Description Edit a draft, rejected or revision_requested achievement (owner only). Editing a rejected achievement clears the rejection note and returns it to draft. The body must carry the last read updatedAt; a stale value is refused with 409.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param achievement body mongodb.UpdateAchievementRequest true "Achievement data"
+// @Success 200 {object} map[string]interface{} "Updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Modified by another request"
+// @Failure 428 {object} map[string]interface{} "updatedAt precondition missing"
+// @Router /api/v1/achievements/{id} [put]
+func UpdateAchievementService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	var req mongodb.UpdateAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Precondition: client wajib mengirim updatedAt terakhir yang dibaca
+	if req.UpdatedAt == nil {
+		return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+			"error": "updatedAt wajib diisi dengan nilai terakhir dari detail achievement",
+		})
+	}
+
+	// Validasi input wajib
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Title wajib diisi",
+		})
+	}
+
+	if !validAchievementType(req.AchievementType) {
+		return invalidAchievementTypeResponse(c)
+	}
+
+	if errs := ValidateAchievementDetails(req.AchievementType, &req.Details); len(errs) > 0 {
+		return fieldErrorsResponse(c, errs)
+	}
+
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:update
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Precondition: submitted dan verified tidak bisa diubah mahasiswa
+	if !model.EditableAchievementStatuses[reference.Status] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":          "Achievement hanya bisa diedit jika berstatus draft, rejected, atau revision_requested",
+			"current_status": reference.Status,
+		})
+	}
+
+	// Schema customFields dikelola admin di database, jadi dicek setelah akses dipastikan
+	if ok, err := checkCustomFields(c, req.AchievementType, req.CustomFields); !ok {
+		return err
+	}
+
+	achievement, err := repository.GetAchievementByID(objectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil achievement dari MongoDB",
+		})
+	}
+	if achievement == nil || achievement.DeletedAt != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Achievement tidak ditemukan",
+		})
+	}
+
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+
+	achievement.AchievementType = req.AchievementType
+	achievement.Title = req.Title
+	achievement.Description = req.Description
+	achievement.Details = req.Details
+	achievement.CustomFields = req.CustomFields
+	achievement.Tags = req.Tags
+
+	// Simpan hanya jika belum diubah request lain sejak dibaca client
+	saved, err := repository.UpdateAchievementIfUnmodified(objectID, achievement, *req.UpdatedAt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan perubahan achievement",
+		})
+	}
+	if !saved {
+		conflict := fiber.Map{
+			"error": "Achievement sudah diubah oleh request lain, muat ulang data sebelum menyimpan",
+		}
+		if current, err := repository.GetAchievementByID(objectID); err == nil && current != nil {
+			conflict["current_updated_at"] = current.UpdatedAt
+		}
+		return c.Status(fiber.StatusConflict).JSON(conflict)
+	}
+
+	// Prestasi yang ditolak kembali ke draft setelah diperbaiki
+	// Conflict diabaikan: request lain sudah lebih dulu membuka kembali prestasi ini
+	if reference.Status == model.AchievementStatusRejected {
+		note := "Diedit setelah ditolak"
+		err := runAchievementTransition(c, &achievementTransition{
+			Action:    model.AchievementActionReopen,
+			Reference: reference,
+			Note:      &note,
+		})
+		if err != nil && !errors.Is(err, ErrAchievementStatusConflict) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal mengembalikan status achievement ke draft",
+			})
+		}
+	}
+
+	withSignedAttachmentURLs(achievement)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Achievement berhasil diperbarui",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"reference_id":   reference.ID,
+			"status":         reference.Status,
+			"rejection_note": reference.RejectionNote,
+			"updated_at":     achievement.UpdatedAt,
+			"achievement":    achievement,
+		},
+	})
+}
+
+// GetAchievementHistoryService - Riwayat status prestasi
+// @Summary Get achievement status history
+// @Description Timeline of every status transition (from, to, actor, role, note, workflow stage, timestamp), oldest first. Accessible by the owning student, their advisor, admins and the approver of a pending stage.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/history [get]
+func GetAchievementHistoryService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	if _, err := primitive.ObjectIDFromHex(achievementID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan akses sudah dicek oleh policy achievement:history
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	history, err := repository.GetAchievementStatusHistory(reference.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil riwayat status achievement",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil riwayat status achievement",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"reference_id":   reference.ID,
+			"status":         reference.Status,
+			"history":        history,
+		},
+	})
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// State machine status prestasi
+//
+// Tabel transisi ada di model.AchievementTransitions. Setiap perubahan status dijalankan lewat
+// runAchievementTransition: cek transisi dan aktor, pre hook (mengisi field reference), simpan
+// dengan compare-and-set di repository, lalu post hook (efek samping setelah commit).
+
+var ErrAchievementStatusConflict = errors.New("status achievement sudah berubah")
+
+// achievementTransitionError error pre hook yang langsung dijadikan response
+type achievementTransitionError struct {
+	Status  int
+	Message string
+}
+
+func (e *achievementTransitionError) Error() string {
+	return e.Message
+}
+
+// achievementTransition satu permintaan perubahan status
+type achievementTransition struct {
+	Action      model.AchievementAction
+	Reference   *model.AchievementReferences
+	Note        *string                    // dicatat di riwayat status
+	Comment     *model.AchievementComments // disimpan dalam transaksi yang sama (request revision)
+	Achievement *mongodb.Achievement       // dokumen MongoDB, dipakai hook submit dan revoke
+	Student     *model.Students            // pemilik prestasi, dipakai post hook notifikasi
+
+	// Diisi runner
+	Rule      *model.AchievementTransition
+	From      model.AchievementStatus
+	FromStage int
+	Stage     *model.ApprovalStage // tahap persetujuan saat ini (aksi approver)
+
+	// Diisi hook
+	Points        int // poin yang diberikan (verify) atau dicabut (revoke)
+	PointsVersion int
+	Stages        []model.ApprovalStage
+}
+
+type achievementTransitionHook func(c *fiber.Ctx, t *achievementTransition) error
+
+// achievementPreHooks dijalankan sebelum disimpan; error membatalkan transisi
+var achievementPreHooks = map[model.AchievementAction][]achievementTransitionHook{
+	model.AchievementActionSubmit:       {assignApprovalWorkflow, assignReviewSLA},
+	model.AchievementActionApproveStage: {advanceApprovalStage},
+	model.AchievementActionVerify:       {recordVerification},
+	model.AchievementActionReject:       {recordRejection},
+	model.AchievementActionReopen:       {clearRejection},
+	model.AchievementActionWithdraw:     {withdrawSubmission},
+	model.AchievementActionRevoke:       {recordRevocation},
+}
+
+// achievementPostHooks dijalankan setelah commit; error hanya dicatat ke log
+var achievementPostHooks = map[model.AchievementAction][]achievementTransitionHook{
+	model.AchievementActionVerify: {awardPointsHook},
+	model.AchievementActionRevoke: {revokePointsHook, notifyRevocationHook},
+}
+
+// achievementActionLabels kata kerja untuk pesan error transisi
+var achievementActionLabels = map[model.AchievementAction]string{
+	model.AchievementActionSubmit:          "di-submit",
+	model.AchievementActionApproveStage:    "diverifikasi",
+	model.AchievementActionVerify:          "diverifikasi",
+	model.AchievementActionReject:          "ditolak",
+	model.AchievementActionRequestRevision: "dimintai revisi",
+	model.AchievementActionReopen:          "dibuka kembali",
+	model.AchievementActionDelete:          "dihapus",
+	model.AchievementActionWithdraw:        "ditarik kembali",
+	model.AchievementActionRevoke:          "dicabut verifikasinya",
+}
+
+// checkAchievementTransition cek awal apakah aksi boleh dijalankan dari status reference saat ini
+// Dipakai service sebelum pekerjaan mahal (MongoDB); runner tetap mengecek ulang.
+// Jika tidak boleh, response sudah ditulis dan ok bernilai false.
+func checkAchievementTransition(c *fiber.Ctx, action model.AchievementAction, reference *model.AchievementReferences) (bool, error) {
+	if _, err := model.FindAchievementTransition(action, reference.Status); err != nil {
+		return false, achievementTransitionErrorResponse(c, action, reference, err)
+	}
+	return true, nil
+}
+
+// runAchievementTransition menjalankan transisi status lengkap dengan hook dan compare-and-set
+// Aksi verify pada tahap yang belum terakhir dijalankan sebagai approve_stage.
+func runAchievementTransition(c *fiber.Ctx, t *achievementTransition) error {
+	ref := t.Reference
+
+	rule, err := model.FindAchievementTransition(t.Action, ref.Status)
+	if err != nil {
+		return err
+	}
+
+	if rule.Actor == model.AchievementActorApprover {
+		stage, isFinal, err := repository.GetCurrentApprovalStage(ref)
+		if err != nil {
+			return err
+		}
+		t.Stage = stage
+		if t.Action == model.AchievementActionVerify && !isFinal {
+			t.Action = model.AchievementActionApproveStage
+			if rule, err = model.FindAchievementTransition(t.Action, ref.Status); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Aktor transisi harus sama dengan rule policy yang mengizinkan request ini
+	if policyRule, _ := c.Locals("policy_rule").(string); policyRule != string(rule.Actor) {
+		return &achievementTransitionError{
+			Status:  fiber.StatusForbidden,
+			Message: "Anda tidak bisa menjalankan aksi ini pada achievement",
+		}
+	}
+
+	t.Rule = rule
+	t.From = ref.Status
+	t.FromStage = ref.CurrentStage
+
+	for _, hook := range achievementPreHooks[t.Action] {
+		if err := hook(c, t); err != nil {
+			return err
+		}
+	}
+
+	if rule.To == "" {
+		deleted, err := achievementReferences.DeleteAchievementReference(ref.ID, t.From)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrAchievementStatusConflict
+		}
+	} else {
+		ref.Status = rule.To
+		history := statusHistoryEntry(c, string(t.From), t.Note)
+		if t.Stage != nil {
+			history.Stage = &t.Stage.Order
+		}
+
+		saved, err := achievementReferences.TransitionAchievementReference(ref, t.From, t.FromStage, history, t.Comment)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			return ErrAchievementStatusConflict
+		}
+	}
+
+	for _, hook := range achievementPostHooks[t.Action] {
+		if err := hook(c, t); err != nil {
+			log.Printf("Post hook %s achievement %s gagal: %v", t.Action, ref.MongoAchievementID, err)
+		}
+	}
+
+	return nil
+}
+
+// achievementTransitionErrorResponse response untuk error dari runAchievementTransition
+func achievementTransitionErrorResponse(c *fiber.Ctx, action model.AchievementAction, reference *model.AchievementReferences, err error) error {
+	var hookErr *achievementTransitionError
+	switch {
+	case errors.Is(err, model.ErrAchievementTransitionNotAllowed):
+		sources := model.AchievementActionSources(action)
+		allowed := make([]string, len(sources))
+		for i, status := range sources {
+			allowed[i] = string(status)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":          "Achievement hanya bisa " + achievementActionLabels[action] + " jika berstatus " + strings.Join(allowed, " atau "),
+			"current_status": reference.Status,
+		})
+	case errors.Is(err, ErrAchievementStatusConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Status achievement sudah berubah, muat ulang data",
+		})
+	case errors.Is(err, model.ErrApprovalStageOutOfRange):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":         "Tahap persetujuan achievement tidak ada di workflow, hubungi admin",
+			"current_stage": reference.CurrentStage,
+		})
+	case errors.As(err, &hookErr):
+		return c.Status(hookErr.Status).JSON(fiber.Map{
+			"error": hookErr.Message,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal update status achievement",
+	})
+}
+
+// assignApprovalWorkflow memilih workflow dari tipe dan tingkat kompetisi; submit ulang mulai dari tahap pertama
+func assignApprovalWorkflow(c *fiber.Ctx, t *achievementTransition) error {
+	workflow, err := repository.FindApprovalWorkflow(t.Achievement.AchievementType, t.Achievement.Details.CompetitionLevel)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t.Stages = model.DefaultApprovalStages
+	t.Reference.WorkflowID = nil
+	if workflow != nil {
+		t.Reference.WorkflowID = &workflow.ID
+		t.Stages = workflow.Stages
+	}
+	t.Reference.CurrentStage = 1
+	t.Reference.SubmittedAt = &now
+	return nil
+}
+
+// assignReviewSLA menghitung batas waktu review tahap pertama dari aturan SLA tipe dan tingkat kompetisi
+// Dijalankan setelah assignApprovalWorkflow (submitted_at sudah diisi); eskalasi sebelumnya dihapus.
+func assignReviewSLA(c *fiber.Ctx, t *achievementTransition) error {
+	sla, err := repository.FindReviewSLA(t.Achievement.AchievementType, t.Achievement.Details.CompetitionLevel)
+	if err != nil {
+		return err
+	}
+
+	dueDays := config.GetReviewSLADays()
+	t.Reference.ReviewSLAID = nil
+	if sla != nil {
+		t.Reference.ReviewSLAID = &sla.ID
+		dueDays = sla.DueDays
+	}
+	StartReviewSLA(t.Reference, dueDays, *t.Reference.SubmittedAt)
+	return nil
+}
+
+// advanceApprovalStage memajukan prestasi ke tahap persetujuan berikutnya
+// Tahap baru mendapat batas waktu sendiri dari aturan SLA yang dipilih saat submit.
+func advanceApprovalStage(c *fiber.Ctx, t *achievementTransition) error {
+	dueDays, err := reviewSLADueDays(t.Reference.ReviewSLAID)
+	if err != nil {
+		return err
+	}
+
+	t.Reference.CurrentStage++
+	StartReviewSLA(t.Reference, dueDays, time.Now())
+	return nil
+}
+
+// reviewSLADueDays batas waktu aturan SLA dalam hari; REVIEW_SLA_DAYS jika tanpa aturan atau aturannya sudah dihapus
+func reviewSLADueDays(slaID *uuid.UUID) (int, error) {
+	if slaID == nil {
+		return config.GetReviewSLADays(), nil
+	}
+
+	sla, err := repository.GetReviewSLAByID(*slaID)
+	if err != nil {
+		if errors.Is(err, repository.ErrReviewSLANotFound) {
+			return config.GetReviewSLADays(), nil
+		}
+		return 0, err
+	}
+	return sla.DueDays, nil
+}
+
+// recordVerification mencatat verifikator; tahap dosen wali wajib dilakukan oleh dosen
+func recordVerification(c *fiber.Ctx, t *achievementTransition) error {
+	var verifiedBy *uuid.UUID
+	lecturer, err := currentLecturer(c)
+	if err == nil {
+		verifiedBy = &lecturer.ID
+	} else if t.Stage != nil && t.Stage.AdvisorOnly {
+		return &achievementTransitionError{
+			Status:  fiber.StatusForbidden,
+			Message: "User bukan dosen atau data dosen tidak ditemukan",
+		}
+	}
+
+	now := time.Now()
+	t.Reference.VerifiedAt = &now
+	t.Reference.VerifiedBy = verifiedBy
+	t.Reference.RejectionNote = nil
+	return nil
+}
+
+// recordRejection menyimpan catatan penolakan; submit ulang dimulai dari tahap pertama
+func recordRejection(c *fiber.Ctx, t *achievementTransition) error {
+	t.Reference.RejectionNote = t.Note
+	t.Reference.VerifiedAt = nil
+	t.Reference.VerifiedBy = nil
+	t.Reference.CurrentStage = 1
+	return nil
+}
+
+// clearRejection menghapus catatan penolakan saat prestasi dibuka kembali ke draft
+func clearRejection(c *fiber.Ctx, t *achievementTransition) error {
+	t.Reference.RejectionNote = nil
+	t.Reference.SubmittedAt = nil
+	return nil
+}
+
+// awardPointsHook menghitung poin prestasi yang baru diverifikasi
+func awardPointsHook(c *fiber.Ctx, t *achievementTransition) error {
+	t.Points, t.PointsVersion = awardVerifiedAchievementPoints(t.Reference.MongoAchievementID)
+	return nil
+}
+
+// withdrawSubmission mengembalikan prestasi ke draft selama belum ada tahap yang disetujui
+// Compare-and-set pada current_stage menolak withdraw jika approver menyetujui tahap pertama bersamaan.
+func withdrawSubmission(c *fiber.Ctx, t *achievementTransition) error {
+	if t.Reference.CurrentStage > 1 {
+		return &achievementTransitionError{
+			Status:  fiber.StatusBadRequest,
+			Message: "Achievement sudah diproses reviewer dan tidak bisa ditarik kembali",
+		}
+	}
+
+	t.Reference.SubmittedAt = nil
+	t.Reference.WorkflowID = nil
+	t.Reference.CurrentStage = 1
+	t.Reference.ReviewDueAt = nil
+	t.Reference.ReviewSLAID = nil
+	t.Reference.EscalatedAt = nil
+	t.Reference.EscalatedTo = nil
+	return nil
+}
+
+// recordRevocation mencatat admin, waktu, dan alasan pencabutan verifikasi
+func recordRevocation(c *fiber.Ctx, t *achievementTransition) error {
+	var revokedBy *uuid.UUID
+	userID, _ := c.Locals("id").(string)
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		revokedBy = &userUUID
+	}
+
+	now := time.Now()
+	t.Reference.RevokedAt = &now
+	t.Reference.RevokedBy = revokedBy
+	t.Reference.RevocationReason = t.Note
+	return nil
+}
+
+// revokePointsHook mengosongkan poin prestasi yang verifikasinya dicabut
+// Dijalankan setelah status disimpan sehingga transisi yang gagal tidak menyentuh poin. Poin yang
+// gagal dikosongkan di sini dibersihkan oleh recompute, yang mengosongkan poin semua prestasi revoked.
+func revokePointsHook(c *fiber.Ctx, t *achievementTransition) error {
+	t.Points = t.Achievement.Points
+	return achievementDocuments.SetAchievementPoints(t.Achievement.ID, 0, t.Achievement.PointsVersion)
+}
+
+// notifyRevocationHook memberi tahu mahasiswa pemilik lewat email
+func notifyRevocationHook(c *fiber.Ctx, t *achievementTransition) error {
+	user, err := repository.GetUserByID(t.Student.UserID)
+	if err != nil {
+		return err
+	}
+
+	subject := "Verifikasi prestasi dicabut"
+	message := fmt.Sprintf(
+		"Halo %s,\n\nVerifikasi prestasi \"%s\" telah dicabut oleh admin.\n"+
+			"Alasan: %s\nPoin yang dicabut: %d\n\n"+
+			"Hubungi admin atau dosen wali Anda jika ada pertanyaan.\n",
+		user.FullName, t.Achievement.Title, *t.Reference.RevocationReason, t.Points,
+	)
+
+	// Dikirim di background agar response tidak menunggu server email
+	go func(to string) {
+		if err := mailer.Send(to, subject, message); err != nil {
+			log.Printf("Gagal mengirim email pencabutan verifikasi ke %s: %v", to, err)
+		}
+	}(user.Email)
+	return nil
+}
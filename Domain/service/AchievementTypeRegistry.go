@@ -0,0 +1,214 @@
+package service
+
+import (
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldError pesan validasi untuk satu field, dikembalikan di response 400
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// achievementTypeSchema field details yang wajib dan boleh diisi untuk satu tipe prestasi
+type achievementTypeSchema struct {
+	Required []string
+	Optional []string
+}
+
+// achievementTypeRegistry daftar tipe prestasi yang didukung beserta field details-nya
+// Field di generalDetailFields boleh diisi untuk semua tipe.
+var achievementTypeRegistry = map[string]achievementTypeSchema{
+	"academic": {
+		Optional: []string{"rank", "issuedBy"},
+	},
+	"competition": {
+		Required: []string{"competitionName", "competitionLevel"},
+		Optional: []string{"rank", "medalType"},
+	},
+	"publication": {
+		Required: []string{"publicationTitle", "publicationType", "authors"},
+		Optional: []string{"publisher", "issn"},
+	},
+	"organization": {
+		Required: []string{"organizationName", "position"},
+		Optional: []string{"period"},
+	},
+	"certification": {
+		Required: []string{"certificationName", "issuedBy"},
+		Optional: []string{"certificationNumber", "validUntil"},
+	},
+	"other": {},
+}
+
+var generalDetailFields = []string{"eventDate", "location", "organizer", "score"}
+
+// detailFieldPresent true jika field details terisi (string kosong/spasi dianggap tidak terisi)
+var detailFieldPresent = map[string]func(d *mongodb.AchievementDetails) bool{
+	"competitionName":     func(d *mongodb.AchievementDetails) bool { return !blankString(d.CompetitionName) },
+	"competitionLevel":    func(d *mongodb.AchievementDetails) bool { return !blankString(d.CompetitionLevel) },
+	"rank":                func(d *mongodb.AchievementDetails) bool { return d.Rank != nil },
+	"medalType":           func(d *mongodb.AchievementDetails) bool { return !blankString(d.MedalType) },
+	"publicationType":     func(d *mongodb.AchievementDetails) bool { return !blankString(d.PublicationType) },
+	"publicationTitle":    func(d *mongodb.AchievementDetails) bool { return !blankString(d.PublicationTitle) },
+	"authors":             func(d *mongodb.AchievementDetails) bool { return len(d.Authors) > 0 },
+	"publisher":           func(d *mongodb.AchievementDetails) bool { return !blankString(d.Publisher) },
+	"issn":                func(d *mongodb.AchievementDetails) bool { return !blankString(d.ISSN) },
+	"organizationName":    func(d *mongodb.AchievementDetails) bool { return !blankString(d.OrganizationName) },
+	"position":            func(d *mongodb.AchievementDetails) bool { return !blankString(d.Position) },
+	"period":              func(d *mongodb.AchievementDetails) bool { return d.Period != nil },
+	"certificationName":   func(d *mongodb.AchievementDetails) bool { return !blankString(d.CertificationName) },
+	"issuedBy":            func(d *mongodb.AchievementDetails) bool { return !blankString(d.IssuedBy) },
+	"certificationNumber": func(d *mongodb.AchievementDetails) bool { return !blankString(d.CertificationNumber) },
+	"validUntil":          func(d *mongodb.AchievementDetails) bool { return d.ValidUntil != nil },
+	"eventDate":           func(d *mongodb.AchievementDetails) bool { return d.EventDate != nil },
+	"location":            func(d *mongodb.AchievementDetails) bool { return !blankString(d.Location) },
+	"organizer":           func(d *mongodb.AchievementDetails) bool { return !blankString(d.Organizer) },
+	"score":               func(d *mongodb.AchievementDetails) bool { return d.Score != nil },
+}
+
+// validAchievementType true jika tipe terdaftar di registry
+func validAchievementType(achievementType string) bool {
+	_, ok := achievementTypeRegistry[achievementType]
+	return ok
+}
+
+// achievementTypeNames daftar tipe prestasi, terurut
+func achievementTypeNames() []string {
+	names := make([]string, 0, len(achievementTypeRegistry))
+	for name := range achievementTypeRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// invalidAchievementTypeResponse response 400 untuk tipe prestasi yang tidak terdaftar
+func invalidAchievementTypeResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Achievement type tidak valid. Pilihan: " + strings.Join(achievementTypeNames(), ", "),
+	})
+}
+
+// ValidateAchievementDetails validasi details terhadap registry tipe prestasi:
+// field wajib harus terisi, field milik tipe lain tidak boleh diisi.
+// Alias competitionLevel dan medalType langsung diganti dengan nilai baku di details.
+func ValidateAchievementDetails(achievementType string, details *mongodb.AchievementDetails) []FieldError {
+	schema, ok := achievementTypeRegistry[achievementType]
+	if !ok {
+		return []FieldError{{Field: "achievementType", Message: "tipe prestasi tidak dikenal"}}
+	}
+
+	errs := normalizeAchievementTaxonomy(details)
+	for _, field := range schema.Required {
+		if !detailFieldPresent[field](details) {
+			errs = append(errs, FieldError{
+				Field:   "details." + field,
+				Message: "wajib diisi untuk prestasi " + achievementType,
+			})
+		}
+	}
+
+	allowed := make(map[string]bool)
+	for _, fields := range [][]string{schema.Required, schema.Optional, generalDetailFields} {
+		for _, field := range fields {
+			allowed[field] = true
+		}
+	}
+	for _, field := range detailFieldNames() {
+		if !allowed[field] && detailFieldPresent[field](details) {
+			errs = append(errs, FieldError{
+				Field:   "details." + field,
+				Message: "tidak berlaku untuk prestasi " + achievementType,
+			})
+		}
+	}
+
+	if details.Rank != nil && *details.Rank < 1 {
+		errs = append(errs, FieldError{Field: "details.rank", Message: "minimal 1"})
+	}
+	for i, author := range details.Authors {
+		if strings.TrimSpace(author) == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("details.authors[%d]", i), Message: "tidak boleh kosong"})
+		}
+	}
+	if details.Period != nil && details.Period.End.Before(details.Period.Start) {
+		errs = append(errs, FieldError{Field: "details.period.end", Message: "tidak boleh sebelum details.period.start"})
+	}
+	if details.ValidUntil != nil && details.EventDate != nil && details.ValidUntil.Before(*details.EventDate) {
+		errs = append(errs, FieldError{Field: "details.validUntil", Message: "tidak boleh sebelum details.eventDate"})
+	}
+	if details.Score != nil && *details.Score < 0 {
+		errs = append(errs, FieldError{Field: "details.score", Message: "tidak boleh negatif"})
+	}
+
+	return errs
+}
+
+// detailFieldNames nama semua field details, terurut
+func detailFieldNames() []string {
+	names := make([]string, 0, len(detailFieldPresent))
+	for name := range detailFieldPresent {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateCustomFields validasi customFields terhadap JSON Schema tipe prestasi (jika admin sudah mendefinisikan)
+func validateCustomFields(achievementType string, customFields map[string]any) ([]FieldError, error) {
+	stored, err := repository.GetAchievementTypeSchema(achievementType)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, nil
+	}
+
+	schema, err := CompileCustomFieldSchema(stored.CustomFieldsSchema)
+	if err != nil {
+		return nil, fmt.Errorf("schema customFields %s tidak valid: %w", achievementType, err)
+	}
+	if customFields == nil {
+		customFields = map[string]any{}
+	}
+	return schema.Validate(customFields, "customFields"), nil
+}
+
+// validateAchievementContent validasi details lalu customFields
+// Jika tidak valid, response sudah ditulis dan ok bernilai false.
+func validateAchievementContent(c *fiber.Ctx, achievementType string, details *mongodb.AchievementDetails, customFields map[string]any) (bool, error) {
+	if errs := ValidateAchievementDetails(achievementType, details); len(errs) > 0 {
+		return false, fieldErrorsResponse(c, errs)
+	}
+	return checkCustomFields(c, achievementType, customFields)
+}
+
+// checkCustomFields validasi customFields terhadap schema di database
+// Jika tidak valid, response sudah ditulis dan ok bernilai false.
+func checkCustomFields(c *fiber.Ctx, achievementType string, customFields map[string]any) (bool, error) {
+	errs, err := validateCustomFields(achievementType, customFields)
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memvalidasi customFields",
+		})
+	}
+	if len(errs) > 0 {
+		return false, fieldErrorsResponse(c, errs)
+	}
+	return true, nil
+}
+
+// fieldErrorsResponse response 400 dengan pesan per field
+func fieldErrorsResponse(c *fiber.Ctx, errs []FieldError) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":  "Data prestasi tidak valid: " + errs[0].Field + " " + errs[0].Message,
+		"fields": errs,
+	})
+}
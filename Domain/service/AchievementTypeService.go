@@ -0,0 +1,153 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetAchievementTypesService - Registry tipe prestasi
+// @Summary List achievement types
+// @Description Get every achievement type with its required and optional details fields and the admin-defined customFields JSON Schema (null when customFields are free-form)
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Router /api/v1/achievement-types [get]
+func GetAchievementTypesService(c *fiber.Ctx) error {
+	schemas, err := repository.GetAchievementTypeSchemas()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil schema tipe prestasi",
+		})
+	}
+
+	results := make([]fiber.Map, 0, len(achievementTypeRegistry))
+	for _, name := range achievementTypeNames() {
+		registered := achievementTypeRegistry[name]
+
+		var customFieldsSchema json.RawMessage
+		if stored, ok := schemas[name]; ok {
+			customFieldsSchema = stored.CustomFieldsSchema
+		}
+
+		// Slice baru agar append tidak menulis ke backing array milik registry
+		fields := make([]string, 0, len(registered.Required)+len(registered.Optional))
+		fields = append(append(fields, registered.Required...), registered.Optional...)
+
+		// Nilai yang diizinkan untuk field details yang dibatasi taxonomy
+		enums := fiber.Map{}
+		for _, taxonomy := range taxonomyFields {
+			for _, field := range fields {
+				if field == taxonomy.name {
+					enums[field] = taxonomy.values
+				}
+			}
+		}
+
+		results = append(results, fiber.Map{
+			"achievement_type":     name,
+			"required_details":     nonNilStrings(registered.Required),
+			"optional_details":     append(nonNilStrings(registered.Optional), generalDetailFields...),
+			"enums":                enums,
+			"custom_fields_schema": customFieldsSchema,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data tipe prestasi",
+		"data":    results,
+	})
+}
+
+// PutAchievementTypeSchemaService - Simpan JSON Schema customFields untuk satu tipe
+// @Summary Set custom fields schema
+// @Description Replace the JSON Schema that customFields of an achievement type must satisfy. Supported keywords: type, properties, required, additionalProperties (boolean), enum, minLength, maxLength, pattern, format (date, date-time, email, uri), minimum, maximum, items, minItems, maxItems. Drafts are checked again on submit.
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Achievement type"
+// @Param schema body object true "JSON Schema (root type object)"
+// @Success 200 {object} map[string]interface{} "Saved"
+// @Failure 400 {object} map[string]interface{} "Invalid schema"
+// @Failure 404 {object} map[string]interface{} "Unknown type"
+// @Router /api/v1/achievement-types/{type}/custom-fields-schema [put]
+func PutAchievementTypeSchemaService(c *fiber.Ctx) error {
+	achievementType := c.Params("type")
+	if !validAchievementType(achievementType) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Tipe prestasi tidak ditemukan",
+		})
+	}
+
+	body := append([]byte(nil), c.Body()...)
+	if _, err := CompileCustomFieldSchema(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Schema tidak valid: " + err.Error(),
+		})
+	}
+
+	schema := &model.AchievementTypeSchemas{
+		AchievementType:    achievementType,
+		CustomFieldsSchema: body,
+		UpdatedAt:          time.Now(),
+	}
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+	schema.UpdatedBy = &userUUID
+
+	if err := repository.UpsertAchievementTypeSchema(schema); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan schema tipe prestasi",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Schema customFields berhasil disimpan",
+		"data":    schema,
+	})
+}
+
+// DeleteAchievementTypeSchemaService - Hapus JSON Schema customFields satu tipe
+// @Summary Delete custom fields schema
+// @Description Remove the customFields JSON Schema of an achievement type; customFields become free-form again
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Achievement type"
+// @Success 200 {object} map[string]interface{} "Deleted"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievement-types/{type}/custom-fields-schema [delete]
+func DeleteAchievementTypeSchemaService(c *fiber.Ctx) error {
+	deleted, err := repository.DeleteAchievementTypeSchema(c.Params("type"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus schema tipe prestasi",
+		})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Schema customFields untuk tipe ini tidak ditemukan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Schema customFields berhasil dihapus",
+	})
+}
+
+func nonNilStrings(values []string) []string {
+	return append([]string{}, values...)
+}
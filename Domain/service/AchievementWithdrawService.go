@@ -0,0 +1,154 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokeAchievementRequest DTO untuk request cabut verifikasi prestasi
+type RevokeAchievementRequest struct {
+	Reason string `json:"reason"`
+}
+
+// WithdrawAchievementService - Tarik kembali prestasi yang sudah di-submit (Mahasiswa)
+// @Summary Withdraw submitted achievement
+// @Description Move a submitted achievement back to draft. Only allowed while no reviewer has approved a workflow stage yet.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} map[string]interface{} "Withdrawn successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
+// @Router /api/v1/achievements/{id}/withdraw [post]
+func WithdrawAchievementService(c *fiber.Ctx) error {
+	// Get achievement_id dari URL parameter
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	if _, err := primitive.ObjectIDFromHex(achievementID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:withdraw
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Flow 1: Kembalikan status ke 'draft' selama belum ada tahap yang disetujui
+	transition := &achievementTransition{
+		Action:    model.AchievementActionWithdraw,
+		Reference: reference,
+	}
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
+	}
+
+	// Flow 2: Return updated status
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Achievement berhasil ditarik kembali ke draft",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"reference_id":   reference.ID,
+			"status":         reference.Status,
+		},
+	})
+}
+
+// RevokeAchievementVerificationService - Cabut verifikasi prestasi (Admin)
+// @Summary Revoke achievement verification
+// @Description Move a verified achievement to revoked with a mandatory reason. The awarded points are removed and the student is notified by email.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param body body RevokeAchievementRequest true "Revocation reason"
+// @Success 200 {object} map[string]interface{} "Revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Status changed concurrently"
+// @Router /api/v1/achievements/{id}/revoke [post]
+func RevokeAchievementVerificationService(c *fiber.Ctx) error {
+	// Get achievement_id dari URL parameter
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Flow 1: Admin input alasan pencabutan
+	var req RevokeAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validasi alasan wajib diisi
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Alasan pencabutan wajib diisi",
+		})
+	}
+
+	// Reference sudah dimuat dan permission admin sudah dicek oleh policy achievement:revoke
+	reference, student, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Precondition: hanya prestasi verified yang bisa dicabut
+	if ok, err := checkAchievementTransition(c, model.AchievementActionRevoke, reference); !ok {
+		return err
+	}
+
+	// Dokumen MongoDB dibutuhkan untuk poin yang dicabut dan isi notifikasi
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	// Flow 2: Update status menjadi 'revoked' (pre hook mencatat admin dan alasan, post hook
+	// mencabut poin lalu mengirim notifikasi ke mahasiswa)
+	transition := &achievementTransition{
+		Action:      model.AchievementActionRevoke,
+		Reference:   reference,
+		Note:        &req.Reason,
+		Achievement: achievement,
+		Student:     student,
+	}
+	if err := runAchievementTransition(c, transition); err != nil {
+		return achievementTransitionErrorResponse(c, transition.Action, reference, err)
+	}
+
+	// Flow 3: Return updated status
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Verifikasi achievement berhasil dicabut",
+		"data": fiber.Map{
+			"achievement_id":    achievementID,
+			"reference_id":      reference.ID,
+			"status":            reference.Status,
+			"revoked_at":        reference.RevokedAt,
+			"revoked_by":        reference.RevokedBy,
+			"revocation_reason": reference.RevocationReason,
+			"points_revoked":    transition.Points,
+			"student_id":        student.StudentID,
+		},
+	})
+}
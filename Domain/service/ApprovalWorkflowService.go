@@ -0,0 +1,418 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Batas jumlah tahap dalam satu workflow
+const maxApprovalStages = 10
+
+// GetApprovalWorkflowsService - Daftar workflow persetujuan
+// @Summary List approval workflows
+// @Description Get every approval workflow with its ordered stages. Achievements without a matching workflow are approved by the advisor alone.
+// @Tags Approval Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Router /api/v1/approval-workflows [get]
+func GetApprovalWorkflowsService(c *fiber.Ctx) error {
+	workflows, err := repository.GetApprovalWorkflows()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil workflow persetujuan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil workflow persetujuan",
+		"data": fiber.Map{
+			"workflows":      workflows,
+			"default_stages": model.DefaultApprovalStages,
+		},
+	})
+}
+
+// GetApprovalWorkflowService - Detail workflow persetujuan
+// @Summary Get approval workflow
+// @Description Get one approval workflow with its ordered stages
+// @Tags Approval Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workflow ID"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/approval-workflows/{id} [get]
+func GetApprovalWorkflowService(c *fiber.Ctx) error {
+	workflowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	workflow, err := repository.GetApprovalWorkflowByID(workflowID)
+	if err != nil {
+		if errors.Is(err, repository.ErrApprovalWorkflowNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Workflow tidak ditemukan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil workflow persetujuan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil workflow persetujuan",
+		"data":    workflow,
+	})
+}
+
+// CreateApprovalWorkflowService - Buat workflow persetujuan
+// @Summary Create approval workflow
+// @Description Create a workflow for an achievement type, optionally limited to one competition level (competition only). Stages are approved in the given order; each stage needs required_permission, and advisor_only stages also need the student's advisor.
+// @Tags Approval Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workflow body model.ApprovalWorkflowInput true "Workflow"
+// @Success 201 {object} map[string]interface{} "Created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 409 {object} map[string]interface{} "Workflow already exists"
+// @Router /api/v1/approval-workflows [post]
+func CreateApprovalWorkflowService(c *fiber.Ctx) error {
+	var req model.ApprovalWorkflowInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workflow, ok, err := approvalWorkflowFromInput(c, &req)
+	if !ok {
+		return err
+	}
+
+	if err := repository.CreateApprovalWorkflow(workflow); err != nil {
+		return approvalWorkflowSaveErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Workflow persetujuan berhasil dibuat",
+		"data":    workflow,
+	})
+}
+
+// UpdateApprovalWorkflowService - Ubah workflow persetujuan
+// @Summary Update approval workflow
+// @Description Replace a workflow and all of its stages. Stages cannot change while submitted achievements use the workflow; the name, type and level can.
+// @Tags Approval Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workflow ID"
+// @Param workflow body model.ApprovalWorkflowInput true "Workflow"
+// @Success 200 {object} map[string]interface{} "Updated"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Workflow already exists or in use"
+// @Router /api/v1/approval-workflows/{id} [put]
+func UpdateApprovalWorkflowService(c *fiber.Ctx) error {
+	workflowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	var req model.ApprovalWorkflowInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workflow, ok, err := approvalWorkflowFromInput(c, &req)
+	if !ok {
+		return err
+	}
+	workflow.ID = workflowID
+
+	if err := repository.UpdateApprovalWorkflow(workflow); err != nil {
+		return approvalWorkflowSaveErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Workflow persetujuan berhasil diubah",
+		"data":    workflow,
+	})
+}
+
+// DeleteApprovalWorkflowService - Hapus workflow persetujuan
+// @Summary Delete approval workflow
+// @Description Delete a workflow. Refused while submitted achievements use it; other achievements pick a workflow again on their next submit.
+// @Tags Approval Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workflow ID"
+// @Success 200 {object} map[string]interface{} "Deleted"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Workflow in use"
+// @Router /api/v1/approval-workflows/{id} [delete]
+func DeleteApprovalWorkflowService(c *fiber.Ctx) error {
+	workflowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid workflow ID",
+		})
+	}
+
+	deleted, err := repository.DeleteApprovalWorkflow(workflowID)
+	if errors.Is(err, repository.ErrApprovalWorkflowInUse) {
+		return approvalWorkflowInUseResponse(c)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus workflow persetujuan",
+		})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Workflow tidak ditemukan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Workflow persetujuan berhasil dihapus",
+	})
+}
+
+// GetPendingApprovalsService - Prestasi yang menunggu persetujuan tahap lanjutan
+// @Summary List pending approvals
+// @Description Submitted achievements whose current workflow stage requires one of the caller's permissions (stages after the advisor, e.g. student affairs or vice dean), plus advisor stages reassigned to the calling lecturer by review escalation. Oldest submission first.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Success"
+// @Router /api/v1/achievements/pending-approval [get]
+func GetPendingApprovalsService(c *fiber.Ctx) error {
+	var permissions []string
+	if perms, ok := c.Locals("permissions").([]interface{}); ok {
+		for _, perm := range perms {
+			if permStr, ok := perm.(string); ok {
+				permissions = append(permissions, permStr)
+			}
+		}
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	// Dosen juga melihat tahap dosen wali yang dialihkan kepadanya oleh eskalasi review
+	var lecturerID *uuid.UUID
+	if lecturer, err := currentLecturer(c); err == nil {
+		lecturerID = &lecturer.ID
+	}
+
+	pending, total, err := repository.GetPendingApprovals(permissions, lecturerID, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil prestasi yang menunggu persetujuan",
+		})
+	}
+
+	mongoIDs := make([]string, len(pending))
+	for i, p := range pending {
+		mongoIDs[i] = p.Reference.MongoAchievementID
+	}
+
+	achievementMap := make(map[string]*mongodb.Achievement)
+	if len(mongoIDs) > 0 {
+		achievements, err := repository.GetAchievementsByMongoIDs(mongoIDs)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal mengambil detail achievements dari MongoDB",
+			})
+		}
+		for i := range achievements {
+			withSignedAttachmentURLs(&achievements[i])
+			achievementMap[achievements[i].ID.Hex()] = &achievements[i]
+		}
+	}
+
+	results := make([]fiber.Map, 0, len(pending))
+	for _, p := range pending {
+		results = append(results, fiber.Map{
+			"reference_id":   p.Reference.ID,
+			"achievement_id": p.Reference.MongoAchievementID,
+			"status":         p.Reference.Status,
+			"submitted_at":   p.Reference.SubmittedAt,
+			"review_due_at":  p.Reference.ReviewDueAt,
+			"escalated":      p.Reference.EscalatedAt != nil,
+			"stage":          p.Stage,
+			"achievement":    achievementMap[p.Reference.MongoAchievementID],
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil prestasi yang menunggu persetujuan",
+		"data": fiber.Map{
+			"achievements": results,
+			"pagination": fiber.Map{
+				"total":       total,
+				"page":        page,
+				"limit":       limit,
+				"total_pages": (total + limit - 1) / limit,
+			},
+		},
+	})
+}
+
+// validateApprovalWorkflowInput merapikan input workflow dan mengembalikan error per field
+// competition_level dinormalisasi ke taxonomy dan hanya boleh untuk tipe competition.
+func validateApprovalWorkflowInput(req *model.ApprovalWorkflowInput) []FieldError {
+	var errs []FieldError
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "wajib diisi"})
+	}
+
+	if !validAchievementType(req.AchievementType) {
+		errs = append(errs, FieldError{
+			Field:   "achievement_type",
+			Message: "harus salah satu dari: " + strings.Join(achievementTypeNames(), ", "),
+		})
+	}
+
+	if blankString(req.CompetitionLevel) {
+		req.CompetitionLevel = nil
+	} else if req.AchievementType != "competition" {
+		errs = append(errs, FieldError{Field: "competition_level", Message: "hanya untuk tipe competition"})
+	} else if level, ok := NormalizeCompetitionLevel(*req.CompetitionLevel); ok {
+		req.CompetitionLevel = &level
+	} else {
+		errs = append(errs, FieldError{
+			Field:   "competition_level",
+			Message: "harus salah satu dari: " + strings.Join(CompetitionLevels, ", "),
+		})
+	}
+
+	if len(req.Stages) == 0 || len(req.Stages) > maxApprovalStages {
+		errs = append(errs, FieldError{
+			Field:   "stages",
+			Message: fmt.Sprintf("harus berisi 1 sampai %d tahap", maxApprovalStages),
+		})
+	}
+	for i := range req.Stages {
+		stage := &req.Stages[i]
+		stage.Name = strings.TrimSpace(stage.Name)
+		stage.RequiredPermission = strings.TrimSpace(stage.RequiredPermission)
+		if stage.Name == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("stages[%d].name", i), Message: "wajib diisi"})
+		}
+		if stage.RequiredPermission == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("stages[%d].required_permission", i), Message: "wajib diisi"})
+		}
+	}
+
+	return errs
+}
+
+// approvalWorkflowFromInput memvalidasi input dan membangun workflow yang siap disimpan
+// Jika tidak valid, response sudah ditulis dan ok bernilai false.
+func approvalWorkflowFromInput(c *fiber.Ctx, req *model.ApprovalWorkflowInput) (*model.ApprovalWorkflows, bool, error) {
+	if errs := validateApprovalWorkflowInput(req); len(errs) > 0 {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "Workflow tidak valid: " + errs[0].Field + " " + errs[0].Message,
+			"fields": errs,
+		})
+	}
+
+	workflow := &model.ApprovalWorkflows{
+		AchievementType:  req.AchievementType,
+		CompetitionLevel: req.CompetitionLevel,
+		Name:             req.Name,
+		Stages:           make([]model.ApprovalStage, 0, len(req.Stages)),
+		UpdatedAt:        time.Now(),
+	}
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+	workflow.UpdatedBy = &userUUID
+
+	// Permission tahap harus terdaftar agar tahap tidak macet tanpa approver
+	for i, stage := range req.Stages {
+		if _, err := repository.GetPermissionByName(stage.RequiredPermission); err != nil {
+			return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Permission tahap tidak ditemukan: " + stage.RequiredPermission,
+				"fields": []FieldError{{
+					Field:   fmt.Sprintf("stages[%d].required_permission", i),
+					Message: "permission tidak ditemukan",
+				}},
+			})
+		}
+		workflow.Stages = append(workflow.Stages, model.ApprovalStage{
+			Order:              i + 1,
+			Name:               stage.Name,
+			RequiredPermission: stage.RequiredPermission,
+			AdvisorOnly:        stage.AdvisorOnly,
+		})
+	}
+
+	return workflow, true, nil
+}
+
+// approvalWorkflowSaveErrorResponse response untuk error simpan workflow
+func approvalWorkflowSaveErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrApprovalWorkflowExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repository.ErrApprovalWorkflowNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Workflow tidak ditemukan",
+		})
+	case errors.Is(err, repository.ErrApprovalWorkflowInUse):
+		return approvalWorkflowInUseResponse(c)
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal menyimpan workflow persetujuan",
+	})
+}
+
+// approvalWorkflowInUseResponse response jika workflow masih dipakai prestasi yang sedang direview
+func approvalWorkflowInUseResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "Tahap workflow tidak bisa diubah atau dihapus selama masih ada prestasi yang sedang direview",
+	})
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var fileStorage config.FileStorage = config.NewLocalFileStorage("uploads")
+
+// SetFileStorage mengganti storage lampiran yang dipakai service
+func SetFileStorage(s config.FileStorage) {
+	fileStorage = s
+}
+
+// urlSigner penanda tangan link download lampiran; nil = link bertanda tangan tidak dibuat
+var urlSigner *config.URLSigner
+
+// SetURLSigner mengganti signer link download lampiran
+func SetURLSigner(s *config.URLSigner) {
+	urlSigner = s
+}
+
+// allowedAttachmentTypes whitelist MIME hasil sniffing isi file -> ekstensi penyimpanan
+var allowedAttachmentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// attachmentDownloadPath URL download lampiran lewat API (bukan URL storage mentah)
+func attachmentDownloadPath(achievementID, attachmentID string) string {
+	return "/api/v1/achievements/" + achievementID + "/attachments/" + attachmentID
+}
+
+// signedAttachmentPath path publik download lampiran (tanpa JWT, dicek lewat signature)
+func signedAttachmentPath(achievementID, attachmentID string) string {
+	return "/api/v1/files/achievements/" + achievementID + "/attachments/" + attachmentID
+}
+
+// signAttachmentURL mengisi DownloadUrl lampiran yang tersimpan di storage
+func signAttachmentURL(achievementID string, attachment *mongodb.Attachment, expiresAt time.Time) {
+	if urlSigner == nil || attachment.ID == "" || attachment.StorageKey == "" {
+		return
+	}
+	attachment.DownloadUrl = config.GetPublicBaseURL() + urlSigner.Sign(signedAttachmentPath(achievementID, attachment.ID), expiresAt)
+}
+
+// withSignedAttachmentURLs mengisi link download bertanda tangan untuk semua lampiran achievement
+func withSignedAttachmentURLs(achievement *mongodb.Achievement) {
+	if achievement == nil {
+		return
+	}
+	expiresAt := time.Now().Add(config.GetAttachmentURLExpiry())
+	for i := range achievement.Attachments {
+		signAttachmentURL(achievement.ID.Hex(), &achievement.Attachments[i], expiresAt)
+	}
+}
+
+// sanitizeFileName nama file untuk ditampilkan/Content-Disposition, tanpa path dan karakter kontrol
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "lampiran"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
+// findAttachment mencari lampiran berdasarkan id
+func findAttachment(achievement *mongodb.Achievement, attachmentID string) *mongodb.Attachment {
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].ID == attachmentID {
+			return &achievement.Attachments[i]
+		}
+	}
+	return nil
+}
+
+var errAchievementNotFound = errors.New("achievement tidak ditemukan")
+
+// loadActiveAchievement mengambil dokumen achievement yang belum dihapus
+func loadActiveAchievement(objectID primitive.ObjectID) (*mongodb.Achievement, error) {
+	achievement, err := achievementDocuments.GetAchievementByID(objectID)
+	if err != nil {
+		return nil, err
+	}
+	if achievement == nil || achievement.DeletedAt != nil {
+		return nil, errAchievementNotFound
+	}
+	return achievement, nil
+}
+
+// achievementLoadErrorResponse response untuk error dari loadActiveAchievement
+func achievementLoadErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errAchievementNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Achievement tidak ditemukan",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal mengambil achievement dari MongoDB",
+	})
+}
+
+// UploadAttachmentService - Upload lampiran prestasi (Mahasiswa)
+// @Summary Upload achievement attachment
+// @Description Upload one evidence file (PDF, JPEG or PNG) to a draft, rejected or revision_requested achievement. The type is detected from the file content, and the SHA-256 checksum is stored with the attachment.
+// @Tags Achievements
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param file formData file true "Evidence file (PDF/JPEG/PNG)"
+// @Success 201 {object} map[string]interface{} "Uploaded"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 413 {object} map[string]interface{} "File too large"
+// @Failure 415 {object} map[string]interface{} "Unsupported file type"
+// @Router /api/v1/achievements/{id}/attachments [post]
+func UploadAttachmentService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Validasi achievement ID
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:upload
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	// Lampiran hanya bisa diubah selama prestasi masih bisa diedit
+	if !model.EditableAchievementStatuses[reference.Status] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":          "Lampiran hanya bisa diubah jika achievement berstatus draft, rejected, atau revision_requested",
+			"current_status": reference.Status,
+		})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File wajib dikirim pada field 'file' (multipart/form-data)",
+		})
+	}
+
+	maxSize := config.GetAttachmentMaxSize()
+	if fileHeader.Size > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":          "Ukuran file melebihi batas",
+			"max_size_bytes": maxSize,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File tidak bisa dibaca",
+		})
+	}
+	defer file.Close()
+
+	// Baca maksimal maxSize+1 byte agar ukuran sebenarnya tetap dicek walaupun header berbohong
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File tidak bisa dibaca",
+		})
+	}
+	if int64(len(data)) > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":          "Ukuran file melebihi batas",
+			"max_size_bytes": maxSize,
+		})
+	}
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "File kosong",
+		})
+	}
+
+	// Tipe file ditentukan dari isi file, bukan dari Content-Type atau ekstensi kiriman client
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	extension, allowed := allowedAttachmentTypes[contentType]
+	if !allowed {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error":         "Tipe file tidak didukung. Pilihan: PDF, JPEG, PNG",
+			"detected_type": contentType,
+		})
+	}
+
+	if _, err := loadActiveAchievement(objectID); err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	checksum := sha256.Sum256(data)
+	attachmentID := uuid.New().String()
+	attachment := mongodb.Attachment{
+		ID:         attachmentID,
+		FileName:   sanitizeFileName(fileHeader.Filename),
+		FileUrl:    attachmentDownloadPath(achievementID, attachmentID),
+		FileType:   contentType,
+		Size:       int64(len(data)),
+		SHA256:     hex.EncodeToString(checksum[:]),
+		StorageKey: "achievements/" + achievementID + "/" + attachmentID + extension,
+		UploadedAt: time.Now(),
+	}
+
+	if err := fileStorage.Put(attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan file",
+		})
+	}
+
+	added, err := repository.AddAchievementAttachment(objectID, attachment)
+	if err != nil || !added {
+		// Metadata gagal disimpan: jangan tinggalkan file yatim di storage
+		_ = fileStorage.Delete(attachment.StorageKey)
+		if err == nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Achievement tidak ditemukan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan data lampiran",
+		})
+	}
+
+	signAttachmentURL(achievementID, &attachment, time.Now().Add(config.GetAttachmentURLExpiry()))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Lampiran berhasil diunggah",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"attachment":     attachment,
+		},
+	})
+}
+
+// DownloadAttachmentService - Unduh lampiran prestasi
+// @Summary Download achievement attachment
+// @Description Stream an attachment file. Accessible by the owning student, their advisor, admins and the approver of a pending workflow stage.
+// @Tags Achievements
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {file} file "Attachment file"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/attachments/{attachmentId} [get]
+func DownloadAttachmentService(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Akses sudah dicek oleh policy achievement:read
+	if _, _, ok := policyAchievementReference(c); !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	return streamAttachment(c, achievement, c.Params("attachmentId"), "attachment")
+}
+
+// DownloadSignedAttachmentService - Unduh lampiran lewat link bertanda tangan (tanpa bearer token)
+// @Summary Download attachment via signed URL
+// @Description Public download route for the time-limited downloadUrl included in achievement responses. The HMAC signature and expiry are checked; the file is shown inline unless download=1.
+// @Tags Achievements
+// @Produce application/octet-stream
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param attachmentId path string true "Attachment ID"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "HMAC signature"
+// @Param download query int false "1 = Content-Disposition attachment"
+// @Success 200 {file} file "Attachment file"
+// @Failure 403 {object} map[string]interface{} "Invalid signature"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 410 {object} map[string]interface{} "Link expired"
+// @Router /api/v1/files/achievements/{id}/attachments/{attachmentId} [get]
+func DownloadSignedAttachmentService(c *fiber.Ctx) error {
+	if urlSigner == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Link download tidak tersedia",
+		})
+	}
+
+	achievementID := c.Params("id")
+	attachmentID := c.Params("attachmentId")
+
+	err := urlSigner.Verify(signedAttachmentPath(achievementID, attachmentID), c.Query("expires"), c.Query("signature"), time.Now())
+	if err != nil {
+		if errors.Is(err, config.ErrURLExpired) {
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{
+				"error": "Link download sudah kedaluwarsa, muat ulang data achievement",
+			})
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Link download tidak valid",
+		})
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Achievement tidak ditemukan",
+		})
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	// Link bisa dibagikan tanpa token: jangan di-cache proxy dan jangan bocor lewat Referer
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+
+	disposition := "inline"
+	if c.Query("download") == "1" {
+		disposition = "attachment"
+	}
+	return streamAttachment(c, achievement, attachmentID, disposition)
+}
+
+// streamAttachment mengirim isi file lampiran dengan Content-Type dan Content-Disposition yang benar
+// disposition "inline" (dibuka di tab browser) atau "attachment" (diunduh)
+func streamAttachment(c *fiber.Ctx, achievement *mongodb.Achievement, attachmentID, disposition string) error {
+	attachment := findAttachment(achievement, attachmentID)
+	if attachment == nil || attachment.StorageKey == "" {
+		// Lampiran lama tanpa StorageKey hanya berisi URL kiriman client, tidak disajikan
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Lampiran tidak ditemukan",
+		})
+	}
+
+	reader, err := fileStorage.Open(attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, config.ErrFileNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "File lampiran tidak ditemukan di storage",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka file lampiran",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.FileType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{
+		"filename": attachment.FileName,
+	}))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	// Reader ditutup oleh fasthttp setelah body selesai dikirim
+	return c.SendStream(reader, int(attachment.Size))
+}
+
+// DeleteAttachmentService - Hapus lampiran prestasi (Mahasiswa)
+// @Summary Delete achievement attachment
+// @Description Remove an attachment from a draft, rejected or revision_requested achievement and delete the stored file
+// @Tags Achievements
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} map[string]interface{} "Deleted"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/attachments/{attachmentId} [delete]
+func DeleteAttachmentService(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	attachmentID := c.Params("attachmentId")
+
+	objectID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	// Reference sudah dimuat dan kepemilikan sudah dicek oleh policy achievement:delete_attachment
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	if !model.EditableAchievementStatuses[reference.Status] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":          "Lampiran hanya bisa diubah jika achievement berstatus draft, rejected, atau revision_requested",
+			"current_status": reference.Status,
+		})
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	attachment := findAttachment(achievement, attachmentID)
+	if attachment == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Lampiran tidak ditemukan",
+		})
+	}
+
+	// Metadata dihapus dulu agar achievement tidak pernah menunjuk file yang sudah tidak ada
+	removed, err := repository.RemoveAchievementAttachment(objectID, attachmentID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus data lampiran",
+		})
+	}
+	if !removed {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Lampiran tidak ditemukan",
+		})
+	}
+
+	if attachment.StorageKey != "" {
+		if err := fileStorage.Delete(attachment.StorageKey); err != nil {
+			log.Printf("Gagal menghapus file lampiran %s: %v", attachment.StorageKey, err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Lampiran berhasil dihapus",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"attachment_id":  attachmentID,
+		},
+	})
+}
@@ -4,17 +4,26 @@ import (
 	"GOLANG/Domain/config"
 	model "GOLANG/Domain/model/Postgresql"
 	. "GOLANG/Domain/repository"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // LoginService handles user login
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. If 2FA is enabled, returns a challenge token to be completed at /auth/login/2fa instead.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -22,6 +31,7 @@ import (
 // @Success 200 {object} map[string]interface{} "Login successful"
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
 // @Router /auth/login [post]
 func LoginService(c *fiber.Ctx) error {
 	var body model.Login
@@ -36,68 +46,161 @@ func LoginService(c *fiber.Ctx) error {
 	var user *model.Users
 	var err error
 
-	if body.Email != "" {
-		user, err = GetUserByEmail(body.Email)
-	} else if body.Username != "" {
-		user, err = GetUserByUsername(body.Username)
-	} else {
+	if body.Email == "" && body.Username == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Email atau username harus diisi",
 		})
 	}
 
+	if body.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password harus diisi",
+		})
+	}
+
+	identifier := body.Username
+	if body.Email != "" {
+		if _, err := mail.ParseAddress(body.Email); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Format email tidak valid",
+			})
+		}
+		identifier = body.Email
+	}
+
+	// Throttling per akun dan per IP; key akun memakai identifier sehingga
+	// akun yang tidak ada diperlakukan sama dengan akun yang ada
+	accountKey := AccountThrottleKey(identifier)
+	ipKey := IPThrottleKey(c.IP())
+
+	wait, err := loginThrottler.Check(accountKey, ipKey)
 	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memproses login, silakan coba lagi",
+		})
+	}
+	if wait > 0 {
+		return loginThrottledResponse(c, wait)
+	}
+
+	if body.Email != "" {
+		user, err = GetUserByEmail(body.Email)
+	} else {
+		user, err = GetUserByUsername(body.Username)
+	}
+
+	// Validasi password; user yang tidak ada tetap melewati bcrypt agar waktu respon sama
+	passwordHash := dummyPasswordHash()
+	if err == nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+
+	if bcrypt.CompareHashAndPassword(passwordHash, []byte(body.Password)) != nil || err != nil {
+		if err := loginThrottler.RegisterFailure(accountKey, ipKey); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal memproses login, silakan coba lagi",
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Email atau username salah",
+			"error": invalidCredentialsMessage,
 		})
 	}
 
-	// Cek status aktif user
+	_ = loginThrottler.RegisterSuccess(accountKey)
+
+	// Cek status aktif user (hanya terlihat oleh yang mengetahui password)
 	if !user.IsActive {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Akun dinonaktifkan",
 		})
 	}
 
-	// Validasi password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(body.Password))
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Password salah",
-		})
+	// User dengan 2FA aktif harus menyelesaikan langkah kedua di /auth/login/2fa
+	if isTwoFactorEnabled(user.ID) {
+		return twoFactorChallengeResponse(c, user, body.Device)
 	}
 
-	// Ambil permissions berdasarkan role
-	permissions, err := GetPermissionsByRoleID(user.RoleID)
+	return completeLogin(c, user, body.Device)
+}
+
+// invalidCredentialsMessage pesan seragam untuk user tidak ditemukan maupun password salah
+const invalidCredentialsMessage = "Email/username atau password salah"
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// dummyPasswordHash hash bcrypt untuk dibandingkan ketika user tidak ditemukan
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), bcrypt.DefaultCost)
+	})
+	return dummyHash
+}
+
+// loginThrottledResponse respon 429 seragam saat login sedang dibatasi
+func loginThrottledResponse(c *fiber.Ctx, wait time.Duration) error {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":       "Terlalu banyak percobaan login, silakan coba lagi nanti",
+		"retry_after": retryAfter,
+	})
+}
+
+// completeLogin membuat sesi baru, access token dan refresh token untuk user yang sudah terautentikasi
+func completeLogin(c *fiber.Ctx, user *model.Users, device string) error {
+	// Ambil permissions berdasarkan role (kosong jika 2FA wajib tetapi belum diaktifkan)
+	grant, err := resolveAccess(user)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal mengambil data permissions",
 		})
 	}
 
-	// Generate JWT token
-	jwtSecret := []byte(config.GetJWTSecret())
-	expiryTime := time.Now().Add(config.GetJWTExpiry())
+	// Catat sesi login untuk perangkat ini
+	userAgent := c.Get("User-Agent")
+	if device == "" {
+		device = userAgent
+	}
 
-	claims := jwt.MapClaims{
-		"id":          user.ID.String(),
-		"username":    user.Username,
-		"role_id":     user.RoleID.String(),
-		"permissions": permissions,
-		"exp":         expiryTime.Unix(),
+	session := &model.UserSessions{
+		UserID:    user.ID,
+		Device:    device,
+		IPAddress: c.IP(),
+		UserAgent: userAgent,
+		ExpiresAt: time.Now().Add(config.GetRefreshTokenExpiry()),
+	}
+	if err := CreateSession(session); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat sesi login",
+		})
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	// Generate JWT token
+	tokenString, err := generateAccessToken(user, grant, session.ID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal membuat token session",
 		})
 	}
 
+	// Generate refresh token; family refresh token = id sesi
+	refreshToken, refreshExpiresAt, err := issueRefreshToken(c, user.ID, session.ID, device)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat refresh token",
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Login berhasil",
-		"token":   tokenString,
+		"message":                   "Login berhasil",
+		"token":                     tokenString,
+		"refresh_token":             refreshToken,
+		"refresh_expires_at":        refreshExpiresAt,
+		"session_id":                session.ID,
+		"two_factor_setup_required": grant.TwoFactorSetupRequired,
 		"user": fiber.Map{
 			"id":        user.ID,
 			"username":  user.Username,
@@ -108,9 +211,211 @@ func LoginService(c *fiber.Ctx) error {
 	})
 }
 
+// generateAccessToken membuat JWT access token untuk user
+// Claim "sid" mengikat token ke sesi login sehingga bisa dicabut sebelum expired.
+// Claim "permissions" hanya informatif; JWTAuth memakai permission terbaru dari cache,
+// dan "pv" (versi permission role) memaksa cache reload jika token lebih baru dari cache.
+func generateAccessToken(user *model.Users, grant *accessGrant, sessionID uuid.UUID) (string, error) {
+	expiryTime := time.Now().Add(config.GetJWTExpiry())
+
+	claims := jwt.MapClaims{
+		"id":          user.ID.String(),
+		"username":    user.Username,
+		"role_id":     user.RoleID.String(),
+		"permissions": grant.Permissions,
+		"pv":          grant.PermissionsVersion,
+		"jti":         uuid.New().String(),
+		"exp":         expiryTime.Unix(),
+	}
+
+	if sessionID != uuid.Nil {
+		claims["sid"] = sessionID.String()
+	}
+
+	// Token ini hanya untuk enrollment 2FA; JWTAuth tidak memberi permission apa pun
+	if grant.TwoFactorSetupRequired {
+		claims["tfa_setup"] = true
+	}
+
+	return config.SignJWT(claims)
+}
+
+// generateRefreshToken membuat refresh token acak (256 bit)
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newRefreshTokenRecord menyiapkan record refresh token (hash saja yang disimpan)
+func newRefreshTokenRecord(c *fiber.Ctx, userID, familyID uuid.UUID, device string) (*model.RefreshTokens, string, error) {
+	rawToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	userAgent := c.Get("User-Agent")
+	if device == "" {
+		device = userAgent
+	}
+
+	record := &model.RefreshTokens{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashToken(rawToken),
+		Device:    device,
+		UserAgent: userAgent,
+		IPAddress: c.IP(),
+		ExpiresAt: time.Now().Add(config.GetRefreshTokenExpiry()),
+	}
+
+	return record, rawToken, nil
+}
+
+// issueRefreshToken membuat dan menyimpan refresh token baru dalam family tertentu
+func issueRefreshToken(c *fiber.Ctx, userID, familyID uuid.UUID, device string) (string, time.Time, error) {
+	record, rawToken, err := newRefreshTokenRecord(c, userID, familyID, device)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := CreateRefreshToken(record); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return rawToken, record.ExpiresAt, nil
+}
+
+// RefreshTokenService menukar refresh token dengan access token baru
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated; reusing a rotated token revokes the whole token family.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param body body model.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "Token refreshed"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /auth/refresh [post]
+func RefreshTokenService(c *fiber.Ctx) error {
+	var body model.RefreshTokenRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Refresh token harus diisi",
+		})
+	}
+
+	// Cari refresh token berdasarkan hash
+	stored, err := GetRefreshTokenByHash(HashToken(body.RefreshToken))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Refresh token tidak valid",
+		})
+	}
+
+	// Token yang sudah dirotasi dipakai lagi: kemungkinan dicuri, cabut seluruh family
+	if stored.RevokedAt != nil {
+		_ = RevokeRefreshTokenFamily(stored.FamilyID)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Refresh token sudah tidak berlaku, silakan login ulang",
+		})
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Refresh token sudah kadaluarsa, silakan login ulang",
+		})
+	}
+
+	// Family refresh token = sesi login; tanpa sesi token tidak bisa dicabut, jadi tidak pernah diterbitkan
+	session, err := GetSessionByID(stored.FamilyID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			_ = RevokeRefreshTokenFamily(stored.FamilyID)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Sesi tidak ditemukan, silakan login ulang",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memeriksa sesi",
+		})
+	}
+
+	if session.RevokedAt != nil {
+		_ = RevokeRefreshTokenFamily(stored.FamilyID)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Sesi telah dicabut, silakan login ulang",
+		})
+	}
+
+	user, err := GetUserByID(stored.UserID)
+	if err != nil || !user.IsActive {
+		_ = RevokeRefreshTokenFamily(stored.FamilyID)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Akun tidak ditemukan atau dinonaktifkan",
+		})
+	}
+
+	grant, err := resolveAccess(user)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permissions",
+		})
+	}
+
+	// Rotasi: token lama ditandai terpakai, token baru tetap di family yang sama
+	device := body.Device
+	if device == "" {
+		device = stored.Device
+	}
+	next, rawToken, err := newRefreshTokenRecord(c, user.ID, stored.FamilyID, device)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat refresh token",
+		})
+	}
+
+	if err := RotateRefreshToken(stored.ID, next); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			_ = RevokeRefreshTokenFamily(stored.FamilyID)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Refresh token sudah tidak berlaku, silakan login ulang",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal merotasi refresh token",
+		})
+	}
+
+	_ = ExtendSession(session.ID, next.ExpiresAt)
+
+	tokenString, err := generateAccessToken(user, grant, session.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat token session",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":                   "Token berhasil diperbarui",
+		"token":                     tokenString,
+		"refresh_token":             rawToken,
+		"refresh_expires_at":        next.ExpiresAt,
+		"two_factor_setup_required": grant.TwoFactorSetupRequired,
+	})
+}
+
 // LogoutService handles user logout
 // @Summary Logout user
-// @Description Invalidate JWT token and logout user
+// @Description Invalidate JWT token and logout user. Optionally revoke the refresh token family sent in the body.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -130,10 +435,7 @@ func LogoutService(c *fiber.Ctx) error {
 	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 
 	// Parse token untuk mendapatkan expiry time
-	jwtSecret := []byte(config.GetJWTSecret())
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	token, err := config.ParseJWT(tokenString)
 
 	if err != nil || !token.Valid {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -152,9 +454,16 @@ func LogoutService(c *fiber.Ctx) error {
 	exp := int64(claims["exp"].(float64))
 	expiresAt := time.Unix(exp, 0)
 
-	// Simpan token ke blacklist
+	// Simpan token ke blacklist (di-inject oleh JWTAuth middleware)
+	blacklist, ok := c.Locals("token_blacklist").(TokenBlacklistRepository)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Token blacklist tidak tersedia",
+		})
+	}
+
 	if time.Now().Before(expiresAt) {
-		err := AddTokenToBlacklist(tokenString, expiresAt)
+		err := blacklist.Add(tokenString, expiresAt)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Gagal logout, silakan coba lagi",
@@ -162,7 +471,155 @@ func LogoutService(c *fiber.Ctx) error {
 		}
 	}
 
+	// Cabut sesi yang sedang dipakai beserta refresh token-nya
+	if sid, ok := claims["sid"].(string); ok {
+		if sessionID, err := uuid.Parse(sid); err == nil {
+			if userID, err := uuid.Parse(fmt.Sprint(claims["id"])); err == nil {
+				_ = RevokeSession(sessionID, userID)
+			}
+		}
+	}
+
+	// Logout dari token impersonation sekaligus mengakhiri impersonation-nya
+	if imp, ok := claims["imp"].(string); ok {
+		if impID, err := uuid.Parse(imp); err == nil {
+			_, _ = EndImpersonation(impID, "logout", c.IP())
+		}
+	}
+
+	// Cabut refresh token (jika dikirim) beserta seluruh family-nya
+	var body model.RefreshTokenRequest
+	if err := c.BodyParser(&body); err == nil && body.RefreshToken != "" {
+		if stored, err := GetRefreshTokenByHash(HashToken(body.RefreshToken)); err == nil && stored.UserID.String() == claims["id"] {
+			_ = RevokeRefreshTokenFamily(stored.FamilyID)
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Logout berhasil",
 	})
 }
+
+// GetProfileService mengembalikan profile user yang sedang login
+// @Summary Get current user profile
+// @Description Get the caller's user record, role, effective permissions and linked student/lecturer profile
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/auth/profile [get]
+func GetProfileService(c *fiber.Ctx) error {
+	// Ambil user_id dari context (dari JWT middleware)
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	user, err := GetUserByID(userUUID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User tidak ditemukan",
+		})
+	}
+
+	// Role dan permission efektif dari database (bukan dari token)
+	var roleData fiber.Map
+	role, err := GetRoleByID(user.RoleID)
+	if err == nil {
+		roleData = fiber.Map{
+			"id":          role.ID,
+			"name":        role.Name,
+			"description": role.Description,
+		}
+	}
+
+	permissions, err := GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permissions",
+		})
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	// Profile mahasiswa beserta dosen wali
+	var studentData fiber.Map
+	if student, err := GetStudentByUserID(user.ID); err == nil {
+		studentData = fiber.Map{
+			"id":            student.ID,
+			"student_id":    student.StudentID,
+			"program_study": student.ProgramStudy,
+			"academic_year": student.AcademicYear,
+			"advisor_id":    student.AdvisorID,
+			"advisor":       nil,
+		}
+
+		if student.AdvisorID != uuid.Nil {
+			if advisor, err := GetLecturerByID(student.AdvisorID); err == nil {
+				advisorData := fiber.Map{
+					"id":          advisor.ID,
+					"lecturer_id": advisor.LecturerID,
+					"department":  advisor.Department,
+				}
+				if advisorUser, err := GetUserByID(advisor.UserID); err == nil {
+					advisorData["full_name"] = advisorUser.FullName
+					advisorData["email"] = advisorUser.Email
+				}
+				studentData["advisor"] = advisorData
+			}
+		}
+	}
+
+	// Profile dosen beserta jumlah mahasiswa bimbingan
+	var lecturerData fiber.Map
+	if lecturer, err := GetLecturerByUserID(user.ID); err == nil {
+		adviseeCount, _ := CountStudentsByAdvisorID(lecturer.ID)
+		lecturerData = fiber.Map{
+			"id":            lecturer.ID,
+			"lecturer_id":   lecturer.LecturerID,
+			"department":    lecturer.Department,
+			"advisee_count": adviseeCount,
+		}
+	}
+
+	// Diisi jika profile dilihat lewat token impersonation
+	var impersonatorData fiber.Map
+	if impersonatorID, _ := c.Locals("impersonator_id").(string); impersonatorID != "" {
+		impersonatorData = fiber.Map{
+			"id":               impersonatorID,
+			"username":         c.Locals("impersonator_username"),
+			"impersonation_id": c.Locals("impersonation_id"),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil profile",
+		"data": fiber.Map{
+			"user":            user,
+			"role":            roleData,
+			"permissions":     permissions,
+			"student":         studentData,
+			"lecturer":        lecturerData,
+			"impersonated_by": impersonatorData,
+		},
+	})
+}
+
+// JWKSService mempublikasikan public key JWT agar service lain bisa memverifikasi token
+// @Summary JSON Web Key Set
+// @Description Public keys used to verify access tokens (RS256/EdDSA), identified by the "kid" token header. Empty when tokens are signed with HS256.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]interface{} "JWKS"
+// @Router /.well-known/jwks.json [get]
+func JWKSService(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(config.JWKS())
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"log"
+	"strings"
+)
+
+// Nilai baku details.competitionLevel, urut dari tingkat terendah
+var CompetitionLevels = []string{"campus", "regional", "national", "international"}
+
+// Nilai baku details.medalType
+var MedalTypes = []string{"gold", "silver", "bronze", "honorable_mention"}
+
+// competitionLevelAliases alias (sudah dinormalisasi lowercase + underscore) -> nilai baku
+var competitionLevelAliases = map[string]string{
+	"campus":        "campus",
+	"kampus":        "campus",
+	"internal":      "campus",
+	"university":    "campus",
+	"universitas":   "campus",
+	"local":         "campus",
+	"lokal":         "campus",
+	"regional":      "regional",
+	"provinsi":      "regional",
+	"province":      "regional",
+	"provincial":    "regional",
+	"wilayah":       "regional",
+	"daerah":        "regional",
+	"national":      "national",
+	"nasional":      "national",
+	"international": "international",
+	"internasional": "international",
+	"global":        "international",
+	"world":         "international",
+}
+
+var medalTypeAliases = map[string]string{
+	"gold":               "gold",
+	"emas":               "gold",
+	"silver":             "silver",
+	"perak":              "silver",
+	"bronze":             "bronze",
+	"perunggu":           "bronze",
+	"honorable_mention":  "honorable_mention",
+	"honourable_mention": "honorable_mention",
+	"harapan":            "honorable_mention",
+	"juara_harapan":      "honorable_mention",
+}
+
+// taxonomyKey menyamakan penulisan: "Juara Harapan" / "juara-harapan" -> "juara_harapan"
+func taxonomyKey(value string) string {
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	})
+	return strings.Join(fields, "_")
+}
+
+// NormalizeCompetitionLevel mengubah alias tingkat kompetisi ke nilai baku; false jika tidak dikenal
+func NormalizeCompetitionLevel(value string) (string, bool) {
+	canonical, ok := competitionLevelAliases[taxonomyKey(value)]
+	return canonical, ok
+}
+
+// NormalizeMedalType mengubah alias jenis medali ke nilai baku; false jika tidak dikenal
+func NormalizeMedalType(value string) (string, bool) {
+	canonical, ok := medalTypeAliases[taxonomyKey(value)]
+	return canonical, ok
+}
+
+// taxonomyFields field details yang nilainya dibatasi taxonomy
+var taxonomyFields = []struct {
+	name      string
+	value     func(d *mongodb.AchievementDetails) *string
+	normalize func(string) (string, bool)
+	values    []string
+}{
+	{"competitionLevel", func(d *mongodb.AchievementDetails) *string { return d.CompetitionLevel }, NormalizeCompetitionLevel, CompetitionLevels},
+	{"medalType", func(d *mongodb.AchievementDetails) *string { return d.MedalType }, NormalizeMedalType, MedalTypes},
+}
+
+// normalizeAchievementTaxonomy mengganti alias competitionLevel dan medalType di details dengan nilai baku
+// Nilai yang tidak dikenal dibiarkan dan dilaporkan sebagai error field.
+func normalizeAchievementTaxonomy(details *mongodb.AchievementDetails) []FieldError {
+	var errs []FieldError
+	for _, taxonomy := range taxonomyFields {
+		value := taxonomy.value(details)
+		if blankString(value) {
+			continue
+		}
+		canonical, ok := taxonomy.normalize(*value)
+		if !ok {
+			errs = append(errs, FieldError{
+				Field:   "details." + taxonomy.name,
+				Message: "harus salah satu dari: " + strings.Join(taxonomy.values, ", "),
+			})
+			continue
+		}
+		*value = canonical
+	}
+	return errs
+}
+
+// canonicalLevelDistribution menggabungkan distribusi tingkat kompetisi ke nilai baku
+// Semua tingkat selalu ada (0 jika kosong); nilai yang tidak dikenal masuk ke "unknown".
+func canonicalLevelDistribution(raw map[string]int) map[string]int {
+	distribution := make(map[string]int, len(CompetitionLevels)+1)
+	for _, level := range CompetitionLevels {
+		distribution[level] = 0
+	}
+	for value, count := range raw {
+		level, ok := NormalizeCompetitionLevel(value)
+		if !ok {
+			level = "unknown"
+		}
+		distribution[level] += count
+	}
+	return distribution
+}
+
+// TaxonomyMigrationReport ringkasan normalisasi dokumen MongoDB yang sudah tersimpan
+type TaxonomyMigrationReport struct {
+	Scanned int
+	Updated int
+	Unknown map[string][]string // field -> id achievement dengan nilai tidak dikenal
+}
+
+// NormalizeStoredAchievementTaxonomy menormalisasi competitionLevel dan medalType di semua achievement
+// Nilai yang tidak dikenal tidak diubah dan dicatat di report. Jika dryRun, tidak ada yang ditulis.
+func NormalizeStoredAchievementTaxonomy(dryRun bool) (*TaxonomyMigrationReport, error) {
+	report := &TaxonomyMigrationReport{Unknown: make(map[string][]string)}
+
+	err := repository.EachAchievementTaxonomy(func(achievement *mongodb.Achievement) error {
+		report.Scanned++
+
+		changes := make(map[string]string)
+		for _, taxonomy := range taxonomyFields {
+			value := taxonomy.value(&achievement.Details)
+			if value == nil {
+				continue
+			}
+			canonical, ok := taxonomy.normalize(*value)
+			if !ok {
+				report.Unknown[taxonomy.name] = append(report.Unknown[taxonomy.name], achievement.ID.Hex())
+				continue
+			}
+			if canonical != *value {
+				changes[taxonomy.name] = canonical
+			}
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+		report.Updated++
+		if dryRun {
+			log.Printf("[dry-run] %s: %v", achievement.ID.Hex(), changes)
+			return nil
+		}
+		return repository.SetAchievementDetailFields(achievement.ID, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	. "GOLANG/Domain/repository"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// generateImpersonationToken membuat access token untuk target dengan claim "act" (impersonator)
+// Token tidak terikat sesi dan tidak punya refresh token; masa berlakunya = impersonation
+func generateImpersonationToken(target *model.Users, grant *accessGrant, imp *model.Impersonations, actor *model.Users) (string, error) {
+	claims := jwt.MapClaims{
+		"id":          target.ID.String(),
+		"username":    target.Username,
+		"role_id":     target.RoleID.String(),
+		"permissions": grant.Permissions,
+		"pv":          grant.PermissionsVersion,
+		"jti":         uuid.New().String(),
+		"exp":         imp.ExpiresAt.Unix(),
+		"imp":         imp.ID.String(),
+		"act": map[string]interface{}{
+			"sub":      actor.ID.String(),
+			"username": actor.Username,
+			"role_id":  actor.RoleID.String(),
+		},
+	}
+
+	if grant.TwoFactorSetupRequired {
+		claims["tfa_setup"] = true
+	}
+
+	return config.SignJWT(claims)
+}
+
+// StartImpersonationService - Login sebagai user lain (support)
+// @Summary Start impersonation
+// @Description Issue a short-lived, read-only token for another user. The token carries an "act" claim with the impersonator and every start/end is recorded.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.ImpersonateRequest true "Target user and reason"
+// @Success 200 {object} map[string]interface{} "Impersonation token"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/auth/impersonate [post]
+func StartImpersonationService(c *fiber.Ctx) error {
+	// Impersonation tidak boleh berantai
+	if impersonatorID, _ := c.Locals("impersonator_id").(string); impersonatorID != "" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Tidak bisa memulai impersonation dari token impersonation",
+		})
+	}
+
+	var req model.ImpersonateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	targetID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Alasan impersonation wajib diisi",
+		})
+	}
+
+	actor, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if actor.ID == targetID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Tidak bisa impersonate diri sendiri",
+		})
+	}
+
+	target, err := GetUserByID(targetID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User tidak ditemukan",
+		})
+	}
+
+	if !target.IsActive {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "User dinonaktifkan",
+		})
+	}
+
+	grant, err := resolveAccess(target)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permissions",
+		})
+	}
+
+	// Sesama admin impersonation tidak boleh saling impersonate
+	for _, p := range grant.Permissions {
+		if p == ImpersonateUsersPermission {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "User dengan permission impersonate_users tidak bisa di-impersonate",
+			})
+		}
+	}
+
+	imp := &model.Impersonations{
+		ImpersonatorID:       actor.ID,
+		ImpersonatorUsername: actor.Username,
+		TargetUserID:         target.ID,
+		TargetUsername:       target.Username,
+		Reason:               req.Reason,
+		IPAddress:            c.IP(),
+		UserAgent:            c.Get("User-Agent"),
+		ExpiresAt:            time.Now().Add(config.GetImpersonationExpiry()),
+	}
+	if err := CreateImpersonation(imp); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mencatat impersonation",
+		})
+	}
+
+	tokenString, err := generateImpersonationToken(target, grant, imp, actor)
+	if err != nil {
+		_, _ = EndImpersonation(imp.ID, "error", c.IP())
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat token impersonation",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Impersonation dimulai",
+		"data": fiber.Map{
+			"token":            tokenString,
+			"impersonation_id": imp.ID,
+			"expires_at":       imp.ExpiresAt,
+			"read_only":        true,
+			"user": fiber.Map{
+				"id":        target.ID,
+				"username":  target.Username,
+				"full_name": target.FullName,
+				"role_id":   target.RoleID,
+			},
+		},
+	})
+}
+
+// StopImpersonationService - Akhiri impersonation dengan token impersonation
+// @Summary Stop impersonation
+// @Description End the impersonation bound to the current token and revoke the token
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Impersonation ended"
+// @Failure 400 {object} map[string]interface{} "Not an impersonation token"
+// @Router /api/v1/auth/impersonate/stop [post]
+func StopImpersonationService(c *fiber.Ctx) error {
+	impID, _ := c.Locals("impersonation_id").(string)
+	impUUID, err := uuid.Parse(impID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Token ini bukan token impersonation",
+		})
+	}
+
+	if _, err := EndImpersonation(impUUID, "stopped", c.IP()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengakhiri impersonation",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Impersonation diakhiri",
+	})
+}
+
+// GetImpersonationsService - Riwayat impersonation (audit)
+// @Summary List impersonations
+// @Description Audit trail of impersonations, newest first
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/v1/auth/impersonations [get]
+func GetImpersonationsService(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	impersonations, total, err := GetImpersonations(limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data impersonation",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data impersonation",
+		"data": fiber.Map{
+			"impersonations": impersonations,
+			"pagination": fiber.Map{
+				"total":       total,
+				"page":        page,
+				"limit":       limit,
+				"total_pages": (total + limit - 1) / limit,
+			},
+		},
+	})
+}
+
+// GetImpersonationDetailService - Detail impersonation beserta event-nya
+// @Summary Get impersonation detail
+// @Description Impersonation with its start, end and blocked write events
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Impersonation UUID"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/auth/impersonations/{id} [get]
+func GetImpersonationDetailService(c *fiber.Ctx) error {
+	impID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid impersonation ID",
+		})
+	}
+
+	imp, err := GetImpersonationByID(impID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Impersonation tidak ditemukan",
+		})
+	}
+
+	events, err := GetImpersonationEvents(impID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil event impersonation",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil detail impersonation",
+		"data": fiber.Map{
+			"impersonation": imp,
+			"events":        events,
+		},
+	})
+}
+
+// EndImpersonationService - Admin mengakhiri impersonation yang masih aktif
+// @Summary End impersonation
+// @Description Terminate an active impersonation; its token stops working immediately
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Impersonation UUID"
+// @Success 200 {object} map[string]interface{} "Impersonation ended"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/auth/impersonations/{id} [delete]
+func EndImpersonationService(c *fiber.Ctx) error {
+	impID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid impersonation ID",
+		})
+	}
+
+	if _, err := GetImpersonationByID(impID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Impersonation tidak ditemukan",
+		})
+	}
+
+	ended, err := EndImpersonation(impID, "terminated", c.IP())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengakhiri impersonation",
+		})
+	}
+
+	if !ended {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "Impersonation sudah berakhir sebelumnya",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Impersonation diakhiri",
+	})
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CustomFieldSchema JSON Schema yang sudah di-compile untuk validasi customFields
+// Hanya subset keyword yang didukung; keyword lain ditolak saat compile agar admin
+// tidak mengira aturan yang tidak dijalankan sudah berlaku.
+type CustomFieldSchema struct {
+	Type                 string
+	Properties           map[string]*CustomFieldSchema
+	Required             []string
+	AdditionalProperties *bool
+	Enum                 []any
+	MinLength            *int
+	MaxLength            *int
+	Pattern              *regexp.Regexp
+	Format               string
+	Minimum              *float64
+	Maximum              *float64
+	Items                *CustomFieldSchema
+	MinItems             *int
+	MaxItems             *int
+}
+
+// Keyword anotasi yang diterima tapi tidak mempengaruhi validasi
+var jsonSchemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+var jsonSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true,
+}
+
+var jsonSchemaFormats = map[string]bool{
+	"date": true, "date-time": true, "email": true, "uri": true,
+}
+
+// CompileCustomFieldSchema parse dan validasi JSON Schema; root harus bertipe object
+func CompileCustomFieldSchema(raw []byte) (*CustomFieldSchema, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.New("schema harus berupa objek JSON")
+	}
+
+	schema, err := compileJSONSchema(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != "object" {
+		return nil, errors.New(`schema root harus bertipe "object"`)
+	}
+	return schema, nil
+}
+
+func compileJSONSchema(doc map[string]any, path string) (*CustomFieldSchema, error) {
+	schema := &CustomFieldSchema{}
+	fail := func(format string, args ...any) (*CustomFieldSchema, error) {
+		location := path
+		if location == "" {
+			location = "(root)"
+		}
+		return nil, fmt.Errorf("%s: %s", location, fmt.Sprintf(format, args...))
+	}
+
+	for keyword, value := range doc {
+		switch keyword {
+		case "type":
+			name, ok := value.(string)
+			if !ok || !jsonSchemaTypes[name] {
+				return fail("type harus salah satu dari object, array, string, number, integer, boolean")
+			}
+			schema.Type = name
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				return fail("properties harus berupa objek")
+			}
+			schema.Properties = make(map[string]*CustomFieldSchema, len(props))
+			for name, prop := range props {
+				propDoc, ok := prop.(map[string]any)
+				if !ok {
+					return fail("properties.%s harus berupa schema objek", name)
+				}
+				compiled, err := compileJSONSchema(propDoc, joinFieldPath(path, name))
+				if err != nil {
+					return nil, err
+				}
+				schema.Properties[name] = compiled
+			}
+		case "required":
+			list, ok := value.([]any)
+			if !ok {
+				return fail("required harus berupa array string")
+			}
+			for _, item := range list {
+				name, ok := item.(string)
+				if !ok {
+					return fail("required harus berupa array string")
+				}
+				schema.Required = append(schema.Required, name)
+			}
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				return fail("additionalProperties hanya mendukung nilai boolean")
+			}
+			schema.AdditionalProperties = &allowed
+		case "enum":
+			list, ok := value.([]any)
+			if !ok || len(list) == 0 {
+				return fail("enum harus berupa array yang tidak kosong")
+			}
+			schema.Enum = list
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, ok := value.(float64)
+			if !ok || n < 0 || n != math.Trunc(n) {
+				return fail("%s harus berupa bilangan bulat >= 0", keyword)
+			}
+			limit := int(n)
+			switch keyword {
+			case "minLength":
+				schema.MinLength = &limit
+			case "maxLength":
+				schema.MaxLength = &limit
+			case "minItems":
+				schema.MinItems = &limit
+			case "maxItems":
+				schema.MaxItems = &limit
+			}
+		case "minimum", "maximum":
+			n, ok := value.(float64)
+			if !ok {
+				return fail("%s harus berupa angka", keyword)
+			}
+			if keyword == "minimum" {
+				schema.Minimum = &n
+			} else {
+				schema.Maximum = &n
+			}
+		case "pattern":
+			expr, ok := value.(string)
+			if !ok {
+				return fail("pattern harus berupa string")
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fail("pattern tidak valid: %v", err)
+			}
+			schema.Pattern = re
+		case "format":
+			name, ok := value.(string)
+			if !ok || !jsonSchemaFormats[name] {
+				return fail("format yang didukung: date, date-time, email, uri")
+			}
+			schema.Format = name
+		case "items":
+			itemDoc, ok := value.(map[string]any)
+			if !ok {
+				return fail("items harus berupa schema objek")
+			}
+			compiled, err := compileJSONSchema(itemDoc, path+"[]")
+			if err != nil {
+				return nil, err
+			}
+			schema.Items = compiled
+		default:
+			if !jsonSchemaAnnotations[keyword] {
+				return fail("keyword %q tidak didukung", keyword)
+			}
+		}
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok && schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+			return fail("required %q tidak ada di properties padahal additionalProperties false", name)
+		}
+	}
+
+	return schema, nil
+}
+
+// Validate memvalidasi nilai terhadap schema; path dipakai sebagai prefix nama field di error
+func (s *CustomFieldSchema) Validate(value any, path string) []FieldError {
+	var errs []FieldError
+	s.validate(normalizeJSONValue(value), path, &errs)
+	return errs
+}
+
+func (s *CustomFieldSchema) validate(value any, path string, errs *[]FieldError) {
+	add := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !jsonTypeMatches(s.Type, value) {
+		add("harus bertipe %s", s.Type)
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, option := range s.Enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("harus salah satu dari: %s", formatEnum(s.Enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			add("minimal %d karakter", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			add("maksimal %d karakter", *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(v) {
+			add("tidak sesuai pola %s", s.Pattern.String())
+		}
+		if s.Format != "" && !formatMatches(s.Format, v) {
+			add("harus berformat %s", s.Format)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			add("minimal %s", strconv.FormatFloat(*s.Minimum, 'f', -1, 64))
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			add("maksimal %s", strconv.FormatFloat(*s.Maximum, 'f', -1, 64))
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			add("minimal %d item", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			add("maksimal %d item", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, path+"["+strconv.Itoa(i)+"]", errs)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, FieldError{Field: joinFieldPath(path, name), Message: "wajib diisi"})
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*errs = append(*errs, FieldError{Field: joinFieldPath(path, name), Message: "field tidak dikenal"})
+				}
+				continue
+			}
+			prop.validate(v[name], joinFieldPath(path, name), errs)
+		}
+	}
+}
+
+func jsonTypeMatches(expected string, value any) bool {
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	}
+	return false
+}
+
+func formatMatches(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != "" && parsed.Host != ""
+	}
+	return true
+}
+
+func formatEnum(options []any) string {
+	parts := make([]string, len(options))
+	for i, option := range options {
+		encoded, _ := json.Marshal(option)
+		parts[i] = strings.Trim(string(encoded), `"`)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// normalizeJSONValue menyamakan nilai dari body JSON dan dokumen MongoDB
+// (primitive.D/A, int32/int64) ke bentuk hasil encoding/json
+func normalizeJSONValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeJSONValue(item)
+		}
+		return out
+	case primitive.M:
+		return normalizeJSONValue(map[string]any(v))
+	case primitive.D:
+		out := make(map[string]any, len(v))
+		for _, elem := range v {
+			out[elem.Key] = normalizeJSONValue(elem.Value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSONValue(item)
+		}
+		return out
+	case primitive.A:
+		return normalizeJSONValue([]any(v))
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return value
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Clock abstraksi waktu agar logic berbasis waktu bisa dites dengan clock palsu
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock clock default yang memakai waktu sistem
+type SystemClock struct{}
+
+// Now mengembalikan waktu sekarang
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// LoginThrottlePolicy pengaturan throttling dan lockout login
+type LoginThrottlePolicy struct {
+	// Jumlah gagal per akun sebelum akun dikunci sementara
+	AccountMaxFailures int
+	// Jumlah gagal per IP sebelum IP dikunci sementara
+	IPMaxFailures int
+	// Jeda awal setelah gagal pertama; berlipat dua setiap kegagalan berikutnya
+	BaseDelay time.Duration
+	// Batas atas jeda exponential backoff
+	MaxDelay time.Duration
+	// Lama penguncian setelah batas gagal tercapai
+	LockoutDuration time.Duration
+	// Kegagalan yang lebih lama dari window ini tidak dihitung lagi
+	FailureWindow time.Duration
+}
+
+// DefaultLoginThrottlePolicy membaca policy dari environment
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	lockout := config.GetLoginLockoutDuration()
+	return LoginThrottlePolicy{
+		AccountMaxFailures: config.GetLoginMaxFailures(),
+		IPMaxFailures:      config.GetLoginIPMaxFailures(),
+		BaseDelay:          time.Second,
+		MaxDelay:           time.Minute,
+		LockoutDuration:    lockout,
+		FailureWindow:      lockout,
+	}
+}
+
+// LoginThrottler menghitung kegagalan login per akun dan per IP
+type LoginThrottler struct {
+	repo   repository.LoginAttemptRepository
+	clock  Clock
+	policy LoginThrottlePolicy
+}
+
+// NewLoginThrottler membuat throttler baru
+func NewLoginThrottler(repo repository.LoginAttemptRepository, clock Clock, policy LoginThrottlePolicy) *LoginThrottler {
+	return &LoginThrottler{
+		repo:   repo,
+		clock:  clock,
+		policy: policy,
+	}
+}
+
+// AccountThrottleKey key throttling untuk identifier login (email/username)
+// Identifier dipakai apa adanya (bukan user id) supaya akun yang tidak ada diperlakukan sama
+func AccountThrottleKey(identifier string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// IPThrottleKey key throttling untuk alamat IP
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check mengembalikan durasi tunggu jika login harus ditolak (0 jika boleh mencoba)
+func (t *LoginThrottler) Check(accountKey, ipKey string) (time.Duration, error) {
+	now := t.clock.Now()
+
+	account, err := t.repo.Get(accountKey)
+	if err != nil {
+		return 0, err
+	}
+
+	ip, err := t.repo.Get(ipKey)
+	if err != nil {
+		return 0, err
+	}
+
+	wait := lockedFor(ip, now)
+	if w := lockedFor(account, now); w > wait {
+		wait = w
+	}
+
+	// Exponential backoff hanya untuk akun; IP kampus sering dipakai bersama (NAT)
+	if account != nil && account.Failures > 0 && now.Sub(account.LastFailureAt) < t.policy.FailureWindow {
+		nextAllowed := account.LastFailureAt.Add(t.backoffDelay(account.Failures))
+		if w := nextAllowed.Sub(now); w > wait {
+			wait = w
+		}
+	}
+
+	return wait, nil
+}
+
+// RegisterFailure mencatat login gagal dan mengunci akun/IP jika batas tercapai
+func (t *LoginThrottler) RegisterFailure(accountKey, ipKey string) error {
+	now := t.clock.Now()
+	windowStart := now.Add(-t.policy.FailureWindow)
+
+	failures, err := t.repo.IncrementFailure(accountKey, now, windowStart)
+	if err != nil {
+		return err
+	}
+	if failures >= t.policy.AccountMaxFailures {
+		if err := t.repo.Lock(accountKey, now.Add(t.policy.LockoutDuration)); err != nil {
+			return err
+		}
+	}
+
+	failures, err = t.repo.IncrementFailure(ipKey, now, windowStart)
+	if err != nil {
+		return err
+	}
+	if failures >= t.policy.IPMaxFailures {
+		if err := t.repo.Lock(ipKey, now.Add(t.policy.LockoutDuration)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess menghapus counter gagal untuk akun setelah login berhasil
+func (t *LoginThrottler) RegisterSuccess(accountKey string) error {
+	return t.repo.Reset(accountKey)
+}
+
+// Unlock membuka kunci satu atau lebih key (dipakai admin)
+func (t *LoginThrottler) Unlock(keys ...string) error {
+	for _, key := range keys {
+		if err := t.repo.Reset(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoffDelay menghitung jeda BaseDelay * 2^(failures-1), dibatasi MaxDelay
+func (t *LoginThrottler) backoffDelay(failures int) time.Duration {
+	delay := t.policy.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.policy.MaxDelay {
+			return t.policy.MaxDelay
+		}
+	}
+	return delay
+}
+
+// lockedFor sisa waktu penguncian sebuah key (0 jika tidak terkunci)
+func lockedFor(attempt *model.LoginAttempts, now time.Time) time.Duration {
+	if attempt == nil || attempt.LockedUntil == nil || !now.Before(*attempt.LockedUntil) {
+		return 0
+	}
+	return attempt.LockedUntil.Sub(now)
+}
+
+// loginThrottler dipakai oleh LoginService; default in-memory sampai dikonfigurasi di main
+var loginThrottler = NewLoginThrottler(repository.NewInMemoryLoginAttempts(), SystemClock{}, DefaultLoginThrottlePolicy())
+
+// SetLoginThrottler mengganti throttler yang dipakai LoginService
+func SetLoginThrottler(throttler *LoginThrottler) {
+	loginThrottler = throttler
+}
+
+// UnlockUserService - Buka kunci login seorang user (Admin)
+// @Summary Unlock user login
+// @Description Clear failed login counters and lockout of a user's email and username (Admin)
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User UUID"
+// @Success 200 {object} map[string]interface{} "Unlocked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/users/{id}/unlock [post]
+func UnlockUserService(c *fiber.Ctx) error {
+	userUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	user, err := repository.GetUserByID(userUUID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User tidak ditemukan",
+		})
+	}
+
+	if err := loginThrottler.Unlock(AccountThrottleKey(user.Email), AccountThrottleKey(user.Username)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuka kunci user",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Kunci login user berhasil dibuka",
+		"data": fiber.Map{
+			"user_id": userUUID,
+		},
+	})
+}
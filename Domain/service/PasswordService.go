@@ -0,0 +1,250 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	. "GOLANG/Domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPasswordLength panjang minimal password baru
+const minPasswordLength = 8
+
+// mailer dipakai untuk mengirim link reset password; default log-only sampai dikonfigurasi di main
+var mailer config.Mailer = config.LogMailer{}
+
+// SetMailer mengganti mailer yang dipakai service
+func SetMailer(m config.Mailer) {
+	mailer = m
+}
+
+// validateNewPassword aturan minimal password baru
+func validateNewPassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("Password minimal %d karakter", minPasswordLength)
+	}
+	return nil
+}
+
+// passwordResetLink membuat link halaman reset password di frontend
+func passwordResetLink(token string) string {
+	return config.GetPasswordResetURL() + "?token=" + url.QueryEscape(token)
+}
+
+// ForgotPasswordService mengirim link reset password ke email user
+// @Summary Request password reset
+// @Description Send a single-use, expiring reset link to the email. Always returns the same response whether or not the email is registered.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param body body model.ForgotPasswordRequest true "Email"
+// @Success 200 {object} map[string]interface{} "Reset link sent if the email is registered"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /auth/password/forgot [post]
+func ForgotPasswordService(c *fiber.Ctx) error {
+	var body model.ForgotPasswordRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Email harus diisi",
+		})
+	}
+
+	if _, err := mail.ParseAddress(body.Email); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format email tidak valid",
+		})
+	}
+
+	// Respon selalu sama agar tidak bisa dipakai untuk mengecek email terdaftar
+	response := fiber.Map{
+		"message": "Jika email terdaftar, link reset password telah dikirim",
+	}
+
+	user, err := GetUserByEmail(body.Email)
+	if err != nil || !user.IsActive {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	rawToken, err := generateRefreshToken()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat token reset password",
+		})
+	}
+
+	expiry := config.GetPasswordResetExpiry()
+	resetToken := &model.PasswordResetTokens{
+		UserID:    user.ID,
+		TokenHash: HashToken(rawToken),
+		IPAddress: c.IP(),
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	if err := CreatePasswordResetToken(resetToken); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat token reset password",
+		})
+	}
+
+	subject := "Reset password"
+	message := fmt.Sprintf(
+		"Halo %s,\n\nKami menerima permintaan reset password untuk akun Anda.\n"+
+			"Buka link berikut untuk membuat password baru (berlaku %d menit, sekali pakai):\n\n%s\n\n"+
+			"Abaikan email ini jika Anda tidak meminta reset password.\n",
+		user.FullName, int(expiry.Minutes()), passwordResetLink(rawToken),
+	)
+
+	// Dikirim di background agar waktu respon tidak membedakan email terdaftar
+	go func(to string) {
+		if err := mailer.Send(to, subject, message); err != nil {
+			log.Printf("Gagal mengirim email reset password ke %s: %v", to, err)
+		}
+	}(user.Email)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ResetPasswordService mengganti password memakai token dari email
+// @Summary Reset password
+// @Description Set a new password using the token from the reset email. The token is single-use and all existing sessions of the user are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param body body model.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{} "Password reset"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /auth/password/reset [post]
+func ResetPasswordService(c *fiber.Ctx) error {
+	var body model.ResetPasswordRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Token == "" || body.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Token dan password baru harus diisi",
+		})
+	}
+
+	if err := validateNewPassword(body.NewPassword); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	userID, err := ResetPasswordWithToken(HashToken(body.Token), body.NewPassword)
+	if err != nil {
+		if errors.Is(err, ErrPasswordResetTokenInvalid) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Token reset password tidak valid atau sudah kadaluarsa",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal reset password",
+		})
+	}
+
+	// Pemilik email sudah terbukti, buka juga kunci login jika sempat terkunci
+	if user, err := GetUserByID(userID); err == nil {
+		_ = loginThrottler.Unlock(AccountThrottleKey(user.Email), AccountThrottleKey(user.Username))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password berhasil direset, silakan login dengan password baru",
+	})
+}
+
+// ChangePasswordService mengganti password user yang sedang login
+// @Summary Change password
+// @Description Change the caller's password after checking the current one. All sessions, including the current one, are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]interface{} "Password changed"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Router /api/v1/auth/password/change [post]
+func ChangePasswordService(c *fiber.Ctx) error {
+	var body model.ChangePasswordRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.CurrentPassword == "" || body.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password saat ini dan password baru harus diisi",
+		})
+	}
+
+	if err := validateNewPassword(body.NewPassword); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if body.NewPassword == body.CurrentPassword {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password baru harus berbeda dari password saat ini",
+		})
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	// Token yang dicuri tidak boleh dipakai untuk menebak password saat ini
+	accountKey := "password:" + user.ID.String()
+	ipKey := IPThrottleKey(c.IP())
+
+	wait, err := loginThrottler.Check(accountKey, ipKey)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memproses permintaan, silakan coba lagi",
+		})
+	}
+	if wait > 0 {
+		return loginThrottledResponse(c, wait)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(body.CurrentPassword)); err != nil {
+		_ = loginThrottler.RegisterFailure(accountKey, ipKey)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password saat ini salah",
+		})
+	}
+
+	_ = loginThrottler.RegisterSuccess(accountKey)
+
+	if err := UpdateUserPassword(user.ID, body.NewPassword); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal update password",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password berhasil diubah, silakan login ulang",
+	})
+}
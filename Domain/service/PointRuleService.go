@@ -0,0 +1,409 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetPointRuleSetsService - List versi rule set poin
+// @Summary List point rule sets
+// @Description Get all versions of the points rule set, newest first
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/v1/point-rules [get]
+func GetPointRuleSetsService(c *fiber.Ctx) error {
+	sets, err := repository.GetPointRuleSets()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data rule set poin",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data rule set poin",
+		"data":    sets,
+	})
+}
+
+// GetActivePointRuleSetService - Rule set poin yang sedang aktif
+// @Summary Get active point rule set
+// @Description Get the active points rule set with its rules
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 404 {object} map[string]interface{} "No active rule set"
+// @Router /api/v1/point-rules/active [get]
+func GetActivePointRuleSetService(c *fiber.Ctx) error {
+	set, err := repository.GetActivePointRuleSet()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil rule set poin aktif",
+		})
+	}
+	if set == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Belum ada rule set poin yang aktif",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil rule set poin aktif",
+		"data":    set,
+	})
+}
+
+// GetPointRuleSetService - Detail rule set poin per versi
+// @Summary Get point rule set
+// @Description Get one version of the points rule set with its rules
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Rule set version"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/point-rules/{version} [get]
+func GetPointRuleSetService(c *fiber.Ctx) error {
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule set version",
+		})
+	}
+
+	set, err := repository.GetPointRuleSetByVersion(version)
+	if err != nil {
+		return pointRuleSetLookupErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil detail rule set poin",
+		"data":    set,
+	})
+}
+
+// CreatePointRuleSetService - Buat versi rule set poin baru
+// Rule set tidak pernah diubah setelah dibuat; perubahan aturan selalu menjadi versi baru
+// @Summary Create point rule set
+// @Description Create a new version of the points rule set. Every matching rule adds its points. Set activate to make it the active version immediately.
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.CreatePointRuleSetRequest true "Rule set data"
+// @Success 201 {object} map[string]interface{} "Rule set created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /api/v1/point-rules [post]
+func CreatePointRuleSetService(c *fiber.Ctx) error {
+	var req model.CreatePointRuleSetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if len(req.Rules) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Rule set minimal berisi satu rule",
+		})
+	}
+
+	set := &model.PointRuleSets{
+		Description: strings.TrimSpace(req.Description),
+		Rules:       make([]model.PointRules, 0, len(req.Rules)),
+	}
+	for i := range req.Rules {
+		if err := validatePointRule(&req.Rules[i]); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Rule ke-" + strconv.Itoa(i+1) + ": " + err.Error(),
+			})
+		}
+		set.Rules = append(set.Rules, model.PointRules{
+			AchievementType: req.Rules[i].AchievementType,
+			Conditions:      req.Rules[i].Conditions,
+			Points:          req.Rules[i].Points,
+			Description:     strings.TrimSpace(req.Rules[i].Description),
+		})
+	}
+
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+	set.CreatedBy = &userUUID
+
+	if err := repository.CreatePointRuleSet(set, req.Activate); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat rule set poin",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Rule set poin berhasil dibuat",
+		"data":    set,
+	})
+}
+
+// ActivatePointRuleSetService - Aktifkan versi rule set poin
+// Prestasi yang sudah diverifikasi tidak ikut berubah; gunakan recompute untuk menghitung ulang
+// @Summary Activate point rule set
+// @Description Make a rule set version the active one. Points already awarded are kept until recomputed.
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Rule set version"
+// @Success 200 {object} map[string]interface{} "Activated"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/point-rules/{version}/activate [post]
+func ActivatePointRuleSetService(c *fiber.Ctx) error {
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule set version",
+		})
+	}
+
+	if err := repository.ActivatePointRuleSet(version); err != nil {
+		if errors.Is(err, repository.ErrPointRuleSetNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Rule set poin tidak ditemukan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengaktifkan rule set poin",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Rule set poin versi " + strconv.Itoa(version) + " berhasil diaktifkan",
+	})
+}
+
+// RecomputePointsService - Hitung ulang poin semua prestasi verified
+// @Summary Recompute achievement points
+// @Description Recompute the points of every verified achievement with a rule set version (default: the active version) and clear any points left on revoked achievements
+// @Tags Point Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.RecomputePointsRequest false "Rule set version (0 = active)"
+// @Success 200 {object} map[string]interface{} "Recomputed"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/point-rules/recompute [post]
+func RecomputePointsService(c *fiber.Ctx) error {
+	var req model.RecomputePointsRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if req.Version < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule set version",
+		})
+	}
+
+	set, errResp := loadPointRuleSet(c, req.Version)
+	if set == nil {
+		return errResp
+	}
+
+	mongoIDs, err := repository.GetAchievementMongoIDsByStatus("verified")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil daftar prestasi terverifikasi",
+		})
+	}
+
+	updated, changed, failed := 0, 0, 0
+	for _, mongoID := range mongoIDs {
+		objectID, err := primitive.ObjectIDFromHex(mongoID)
+		if err != nil {
+			failed++
+			continue
+		}
+		achievement, err := loadActiveAchievement(objectID)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		points, _ := CalculatePoints(set.Rules, achievement)
+		if err := achievementDocuments.SetAchievementPoints(objectID, points, set.Version); err != nil {
+			log.Printf("Gagal menyimpan poin achievement %s: %v", mongoID, err)
+			failed++
+			continue
+		}
+		updated++
+		if points != achievement.Points {
+			changed++
+		}
+	}
+
+	// Prestasi revoked diambil setelah prestasi verified selesai ditulis: poin yang gagal dikosongkan
+	// saat pencabutan, atau ditulis ulang di atas untuk prestasi yang dicabut bersamaan, dikosongkan di sini
+	revokedIDs, err := repository.GetAchievementMongoIDsByStatus("revoked")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil daftar prestasi yang dicabut",
+		})
+	}
+
+	cleared := 0
+	for _, mongoID := range revokedIDs {
+		objectID, err := primitive.ObjectIDFromHex(mongoID)
+		if err != nil {
+			failed++
+			continue
+		}
+		achievement, err := loadActiveAchievement(objectID)
+		if err != nil {
+			failed++
+			continue
+		}
+		if achievement.Points == 0 {
+			continue
+		}
+
+		if err := achievementDocuments.SetAchievementPoints(objectID, 0, achievement.PointsVersion); err != nil {
+			log.Printf("Gagal mengosongkan poin achievement %s: %v", mongoID, err)
+			failed++
+			continue
+		}
+		cleared++
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Poin prestasi berhasil dihitung ulang",
+		"data": fiber.Map{
+			"version": set.Version,
+			"total":   len(mongoIDs),
+			"updated": updated,
+			"changed": changed,
+			"cleared": cleared,
+			"failed":  failed,
+		},
+	})
+}
+
+// PreviewAchievementPointsService - Dry-run perhitungan poin prestasi
+// @Summary Preview achievement points
+// @Description Calculate the points an achievement would get without saving anything. Uses the active rule set unless a version is given.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param version query int false "Rule set version (default: active)"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/achievements/{id}/points/preview [get]
+func PreviewAchievementPointsService(c *fiber.Ctx) error {
+	objectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid achievement ID",
+		})
+	}
+
+	version := 0
+	if raw := c.Query("version"); raw != "" {
+		version, err = strconv.Atoi(raw)
+		if err != nil || version < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid rule set version",
+			})
+		}
+	}
+
+	reference, _, ok := policyAchievementReference(c)
+	if !ok {
+		return policyNotEvaluatedResponse(c)
+	}
+
+	set, errResp := loadPointRuleSet(c, version)
+	if set == nil {
+		return errResp
+	}
+
+	achievement, err := loadActiveAchievement(objectID)
+	if err != nil {
+		return achievementLoadErrorResponse(c, err)
+	}
+
+	points, breakdown := CalculatePoints(set.Rules, achievement)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil menghitung pratinjau poin",
+		"data": fiber.Map{
+			"achievement_id": objectID.Hex(),
+			"status":         reference.Status,
+			"version":        set.Version,
+			"points":         points,
+			"current_points": achievement.Points,
+			"breakdown":      breakdown,
+		},
+	})
+}
+
+// pointRuleSetLookupErrorResponse 404 jika versi tidak ada, 500 untuk error database lain
+func pointRuleSetLookupErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrPointRuleSetNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rule set poin tidak ditemukan",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal mengambil rule set poin",
+	})
+}
+
+// loadPointRuleSet mengambil rule set versi tertentu (0 = aktif)
+// Jika gagal, set nil dan error response sudah ditulis ke context
+func loadPointRuleSet(c *fiber.Ctx, version int) (*model.PointRuleSets, error) {
+	if version > 0 {
+		set, err := repository.GetPointRuleSetByVersion(version)
+		if err != nil {
+			return nil, pointRuleSetLookupErrorResponse(c, err)
+		}
+		return set, nil
+	}
+
+	set, err := repository.GetActivePointRuleSet()
+	if err != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil rule set poin aktif",
+		})
+	}
+	if set == nil {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Belum ada rule set poin yang aktif",
+		})
+	}
+	return set, nil
+}
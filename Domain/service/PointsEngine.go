@@ -0,0 +1,133 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Rule poin berlaku untuk semua tipe prestasi jika achievement_type = "*"
+const anyAchievementType = "*"
+
+// pointRuleFields field details yang bisa dipakai sebagai kondisi rule poin
+var pointRuleFields = map[string]func(d *mongodb.AchievementDetails) (string, bool){
+	"competitionLevel": func(d *mongodb.AchievementDetails) (string, bool) { return derefString(d.CompetitionLevel) },
+	"medalType":        func(d *mongodb.AchievementDetails) (string, bool) { return derefString(d.MedalType) },
+	"rank": func(d *mongodb.AchievementDetails) (string, bool) {
+		if d.Rank == nil {
+			return "", false
+		}
+		return strconv.Itoa(*d.Rank), true
+	},
+	"publicationType": func(d *mongodb.AchievementDetails) (string, bool) { return derefString(d.PublicationType) },
+	"position":        func(d *mongodb.AchievementDetails) (string, bool) { return derefString(d.Position) },
+	"issuedBy":        func(d *mongodb.AchievementDetails) (string, bool) { return derefString(d.IssuedBy) },
+}
+
+func derefString(value *string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	return *value, true
+}
+
+// PointBreakdown rule yang cocok dan poin yang disumbangkannya
+type PointBreakdown struct {
+	RuleID      uuid.UUID         `json:"rule_id"`
+	Description string            `json:"description"`
+	Conditions  map[string]string `json:"conditions"`
+	Points      int               `json:"points"`
+}
+
+// CalculatePoints menghitung poin prestasi: jumlah poin semua rule yang tipe dan kondisinya cocok
+// Kondisi dibandingkan tanpa membedakan huruf besar/kecil; rule tanpa kondisi menjadi poin dasar tipe.
+func CalculatePoints(rules []model.PointRules, achievement *mongodb.Achievement) (int, []PointBreakdown) {
+	total := 0
+	breakdown := []PointBreakdown{}
+
+	for _, rule := range rules {
+		if rule.AchievementType != anyAchievementType && rule.AchievementType != achievement.AchievementType {
+			continue
+		}
+		if !pointRuleMatches(rule.Conditions, &achievement.Details) {
+			continue
+		}
+
+		total += rule.Points
+		breakdown = append(breakdown, PointBreakdown{
+			RuleID:      rule.ID,
+			Description: rule.Description,
+			Conditions:  rule.Conditions,
+			Points:      rule.Points,
+		})
+	}
+
+	return total, breakdown
+}
+
+// pointRuleMatches true jika semua kondisi terpenuhi oleh details
+func pointRuleMatches(conditions map[string]string, details *mongodb.AchievementDetails) bool {
+	for field, expected := range conditions {
+		getter, ok := pointRuleFields[field]
+		if !ok {
+			return false
+		}
+		actual, ok := getter(details)
+		if !ok || !strings.EqualFold(strings.TrimSpace(actual), strings.TrimSpace(expected)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validatePointRule validasi satu rule sebelum disimpan
+func validatePointRule(rule *model.PointRuleInput) error {
+	rule.AchievementType = strings.TrimSpace(rule.AchievementType)
+	if rule.AchievementType != anyAchievementType && !validAchievementType(rule.AchievementType) {
+		return errors.New("achievement_type tidak valid: " + rule.AchievementType)
+	}
+	if rule.Points < 0 {
+		return errors.New("points tidak boleh negatif")
+	}
+
+	for field, value := range rule.Conditions {
+		if _, ok := pointRuleFields[field]; !ok {
+			return errors.New("field kondisi tidak dikenal: " + field + ". Pilihan: " + strings.Join(pointRuleFieldNames(), ", "))
+		}
+		if strings.TrimSpace(value) == "" {
+			return errors.New("nilai kondisi " + field + " tidak boleh kosong")
+		}
+		if field == "rank" {
+			if _, err := strconv.Atoi(value); err != nil {
+				return errors.New("kondisi rank harus berupa angka")
+			}
+		}
+		for _, taxonomy := range taxonomyFields {
+			if taxonomy.name != field {
+				continue
+			}
+			canonical, ok := taxonomy.normalize(value)
+			if !ok {
+				return errors.New("kondisi " + field + " harus salah satu dari: " + strings.Join(taxonomy.values, ", "))
+			}
+			rule.Conditions[field] = canonical
+		}
+	}
+
+	return nil
+}
+
+// pointRuleFieldNames daftar field kondisi yang didukung, terurut
+func pointRuleFieldNames() []string {
+	names := make([]string, 0, len(pointRuleFields))
+	for name := range pointRuleFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewEscalationPolicy pengaturan scheduler eskalasi review
+type ReviewEscalationPolicy struct {
+	// Penerima eskalasi jika aturan SLA tidak punya escalation_email (kosong = hanya log)
+	DefaultEscalationEmail string
+	// Jumlah prestasi maksimal per putaran
+	BatchSize int
+}
+
+// DefaultReviewEscalationPolicy policy dari env
+func DefaultReviewEscalationPolicy() ReviewEscalationPolicy {
+	return ReviewEscalationPolicy{
+		DefaultEscalationEmail: config.GetReviewEscalationEmail(),
+		BatchSize:              100,
+	}
+}
+
+// ReviewEscalationScheduler mengeskalasi prestasi submitted yang melewati batas waktu review
+// Aman dijalankan di beberapa replica: satu putaran hanya jalan di instance pemegang advisory lock,
+// dan setiap prestasi ditandai dengan compare-and-set sehingga tidak dieskalasi dua kali.
+type ReviewEscalationScheduler struct {
+	repo   repository.ReviewEscalationRepository
+	lock   repository.AdvisoryLock
+	clock  Clock
+	policy ReviewEscalationPolicy
+}
+
+// NewReviewEscalationScheduler membuat scheduler baru
+func NewReviewEscalationScheduler(repo repository.ReviewEscalationRepository, lock repository.AdvisoryLock, clock Clock, policy ReviewEscalationPolicy) *ReviewEscalationScheduler {
+	return &ReviewEscalationScheduler{
+		repo:   repo,
+		lock:   lock,
+		clock:  clock,
+		policy: policy,
+	}
+}
+
+// RunOnce menjalankan satu putaran eskalasi dan mengembalikan jumlah prestasi yang dieskalasi
+// Jika lock dipegang replica lain, putaran dilewati tanpa error.
+func (s *ReviewEscalationScheduler) RunOnce() (int, error) {
+	unlock, acquired, err := s.lock.TryLock()
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer unlock()
+
+	now := s.clock.Now()
+	overdue, err := s.repo.GetOverdueReviews(now, s.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, review := range overdue {
+		// Tahap dosen wali dialihkan ke dosen pengganti; tahap lain cukup diberi tahu
+		var escalatedTo *uuid.UUID
+		if review.AdvisorOnlyStage && review.BackupReviewerID != nil &&
+			(review.AdvisorID == nil || *review.AdvisorID != *review.BackupReviewerID) {
+			escalatedTo = review.BackupReviewerID
+		}
+
+		marked, err := s.repo.MarkReviewEscalated(review.ReferenceID, review.CurrentStage, escalatedTo, now)
+		if err != nil {
+			log.Printf("Gagal eskalasi review achievement %s: %v", review.MongoAchievementID, err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+
+		escalated++
+		s.notify(review, escalatedTo != nil, now)
+	}
+
+	return escalated, nil
+}
+
+// Start menjalankan RunOnce secara berkala di background
+// Panggil fungsi yang dikembalikan untuk menghentikan scheduler
+func (s *ReviewEscalationScheduler) Start(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if n, err := s.RunOnce(); err != nil {
+					log.Println("Gagal menjalankan eskalasi review:", err)
+				} else if n > 0 {
+					log.Printf("Eskalasi review: %d achievement melewati batas waktu", n)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// notify mengirim email ke penerima eskalasi dan dosen pengganti
+func (s *ReviewEscalationScheduler) notify(review repository.OverdueReview, reassigned bool, now time.Time) {
+	link := config.GetPublicBaseURL() + "/api/v1/achievements/" + review.MongoAchievementID
+	days := reviewDaysBetween(review.SubmittedAt, now)
+
+	to := s.policy.DefaultEscalationEmail
+	if review.EscalationEmail != nil && *review.EscalationEmail != "" {
+		to = *review.EscalationEmail
+	}
+	if to != "" {
+		message := fmt.Sprintf(
+			"Prestasi mahasiswa %s sudah menunggu review %d hari dan melewati batas waktu tahap %d (%s).\n\n%s\n",
+			review.StudentNumber, days, review.CurrentStage, review.ReviewDueAt.Format("2006-01-02"), link,
+		)
+		if reassigned {
+			message += "\nTahap dosen wali sudah dialihkan ke dosen pengganti.\n"
+		}
+		if err := mailer.Send(to, "Eskalasi review prestasi", message); err != nil {
+			log.Printf("Gagal mengirim email eskalasi ke %s: %v", to, err)
+		}
+	} else {
+		log.Printf("Eskalasi review achievement %s tanpa penerima email", review.MongoAchievementID)
+	}
+
+	if reassigned && review.BackupReviewerEmail != nil {
+		message := fmt.Sprintf(
+			"Anda ditunjuk sebagai dosen pengganti untuk mereview prestasi mahasiswa %s yang sudah menunggu %d hari.\n\n%s\n",
+			review.StudentNumber, days, link,
+		)
+		if err := mailer.Send(*review.BackupReviewerEmail, "Review prestasi dialihkan ke Anda", message); err != nil {
+			log.Printf("Gagal mengirim email ke dosen pengganti %s: %v", *review.BackupReviewerEmail, err)
+		}
+	}
+}
+
+// reviewDaysBetween jumlah hari penuh sejak submit
+func reviewDaysBetween(submittedAt, now time.Time) int {
+	if now.Before(submittedAt) {
+		return 0
+	}
+	return int(now.Sub(submittedAt) / (24 * time.Hour))
+}
+
+// StartReviewSLA memulai batas waktu review tahap persetujuan yang dimulai pada from
+// Eskalasi tahap sebelumnya dihapus sehingga tahap ini bisa dieskalasi lagi.
+func StartReviewSLA(ref *model.AchievementReferences, dueDays int, from time.Time) {
+	dueAt := from.AddDate(0, 0, dueDays)
+	ref.ReviewDueAt = &dueAt
+	ref.EscalatedAt = nil
+	ref.EscalatedTo = nil
+}
+
+// ReviewSLAProgress lama menunggu (hari penuh) dan status terlambat prestasi yang sedang direview
+// daysWaiting nil jika prestasi tidak sedang submitted.
+func ReviewSLAProgress(ref *model.AchievementReferences, now time.Time) (daysWaiting *int, overdue bool) {
+	if ref.Status != model.AchievementStatusSubmitted || ref.SubmittedAt == nil {
+		return nil, false
+	}
+	days := reviewDaysBetween(*ref.SubmittedAt, now)
+	return &days, ref.ReviewDueAt != nil && now.After(*ref.ReviewDueAt)
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Batas atas batas waktu review (hari)
+const maxReviewSLADays = 365
+
+// GetReviewSLAsService - Daftar aturan SLA review
+// @Summary List review SLAs
+// @Description Get every review SLA rule. Achievements without a matching rule use the default number of days (REVIEW_SLA_DAYS).
+// @Tags Review SLAs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Router /api/v1/review-slas [get]
+func GetReviewSLAsService(c *fiber.Ctx) error {
+	slas, err := repository.GetReviewSLAs()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil SLA review",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil SLA review",
+		"data": fiber.Map{
+			"slas":                     slas,
+			"default_due_days":         config.GetReviewSLADays(),
+			"default_escalation_email": config.GetReviewEscalationEmail(),
+		},
+	})
+}
+
+// GetReviewSLAService - Detail aturan SLA review
+// @Summary Get review SLA
+// @Description Get one review SLA rule
+// @Tags Review SLAs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "SLA ID"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/review-slas/{id} [get]
+func GetReviewSLAService(c *fiber.Ctx) error {
+	slaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid SLA ID",
+		})
+	}
+
+	sla, err := repository.GetReviewSLAByID(slaID)
+	if err != nil {
+		if errors.Is(err, repository.ErrReviewSLANotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "SLA tidak ditemukan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil SLA review",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil SLA review",
+		"data":    sla,
+	})
+}
+
+// CreateReviewSLAService - Buat aturan SLA review
+// @Summary Create review SLA
+// @Description Create a review deadline for an achievement type, optionally limited to one competition level (competition only). Overdue submissions are escalated to escalation_email, and the advisor stage is reassigned to backup_reviewer_id when set.
+// @Tags Review SLAs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sla body model.ReviewSLAInput true "Review SLA"
+// @Success 201 {object} map[string]interface{} "Created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 409 {object} map[string]interface{} "SLA already exists"
+// @Router /api/v1/review-slas [post]
+func CreateReviewSLAService(c *fiber.Ctx) error {
+	var req model.ReviewSLAInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sla, ok, err := reviewSLAFromInput(c, &req)
+	if !ok {
+		return err
+	}
+
+	if err := repository.CreateReviewSLA(sla); err != nil {
+		return reviewSLASaveErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "SLA review berhasil dibuat",
+		"data":    sla,
+	})
+}
+
+// UpdateReviewSLAService - Ubah aturan SLA review
+// @Summary Update review SLA
+// @Description Replace a review SLA rule. Achievements already submitted keep the deadline computed at submission.
+// @Tags Review SLAs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "SLA ID"
+// @Param sla body model.ReviewSLAInput true "Review SLA"
+// @Success 200 {object} map[string]interface{} "Updated"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "SLA already exists"
+// @Router /api/v1/review-slas/{id} [put]
+func UpdateReviewSLAService(c *fiber.Ctx) error {
+	slaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid SLA ID",
+		})
+	}
+
+	var req model.ReviewSLAInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sla, ok, err := reviewSLAFromInput(c, &req)
+	if !ok {
+		return err
+	}
+	sla.ID = slaID
+
+	if err := repository.UpdateReviewSLA(sla); err != nil {
+		return reviewSLASaveErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "SLA review berhasil diubah",
+		"data":    sla,
+	})
+}
+
+// DeleteReviewSLAService - Hapus aturan SLA review
+// @Summary Delete review SLA
+// @Description Delete a review SLA rule. Achievements already submitted keep their deadline and escalate to the default recipient.
+// @Tags Review SLAs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "SLA ID"
+// @Success 200 {object} map[string]interface{} "Deleted"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/review-slas/{id} [delete]
+func DeleteReviewSLAService(c *fiber.Ctx) error {
+	slaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid SLA ID",
+		})
+	}
+
+	deleted, err := repository.DeleteReviewSLA(slaID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus SLA review",
+		})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "SLA tidak ditemukan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "SLA review berhasil dihapus",
+	})
+}
+
+// validateReviewSLAInput merapikan input SLA dan mengembalikan error per field
+// competition_level dinormalisasi ke taxonomy dan hanya boleh untuk tipe competition.
+func validateReviewSLAInput(req *model.ReviewSLAInput) []FieldError {
+	var errs []FieldError
+
+	if !validAchievementType(req.AchievementType) {
+		errs = append(errs, FieldError{
+			Field:   "achievement_type",
+			Message: "harus salah satu dari: " + strings.Join(achievementTypeNames(), ", "),
+		})
+	}
+
+	if blankString(req.CompetitionLevel) {
+		req.CompetitionLevel = nil
+	} else if req.AchievementType != "competition" {
+		errs = append(errs, FieldError{Field: "competition_level", Message: "hanya untuk tipe competition"})
+	} else if level, ok := NormalizeCompetitionLevel(*req.CompetitionLevel); ok {
+		req.CompetitionLevel = &level
+	} else {
+		errs = append(errs, FieldError{
+			Field:   "competition_level",
+			Message: "harus salah satu dari: " + strings.Join(CompetitionLevels, ", "),
+		})
+	}
+
+	if req.DueDays < 1 || req.DueDays > maxReviewSLADays {
+		errs = append(errs, FieldError{
+			Field:   "due_days",
+			Message: fmt.Sprintf("harus antara 1 dan %d", maxReviewSLADays),
+		})
+	}
+
+	if blankString(req.EscalationEmail) {
+		req.EscalationEmail = nil
+	} else {
+		email := strings.TrimSpace(*req.EscalationEmail)
+		if _, err := mail.ParseAddress(email); err != nil {
+			errs = append(errs, FieldError{Field: "escalation_email", Message: "format email tidak valid"})
+		}
+		req.EscalationEmail = &email
+	}
+
+	return errs
+}
+
+// reviewSLAFromInput memvalidasi input dan membangun SLA yang siap disimpan
+// Jika tidak valid, response sudah ditulis dan ok bernilai false.
+func reviewSLAFromInput(c *fiber.Ctx, req *model.ReviewSLAInput) (*model.ReviewSLAs, bool, error) {
+	if errs := validateReviewSLAInput(req); len(errs) > 0 {
+		return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "SLA tidak valid: " + errs[0].Field + " " + errs[0].Message,
+			"fields": errs,
+		})
+	}
+
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	// Dosen pengganti harus terdaftar agar eskalasi tidak dialihkan ke dosen yang tidak ada
+	if req.BackupReviewerID != nil {
+		if _, err := repository.GetLecturerByID(*req.BackupReviewerID); err != nil {
+			return nil, false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Dosen pengganti tidak ditemukan",
+				"fields": []FieldError{{
+					Field:   "backup_reviewer_id",
+					Message: "dosen tidak ditemukan",
+				}},
+			})
+		}
+	}
+
+	sla := &model.ReviewSLAs{
+		AchievementType:  req.AchievementType,
+		CompetitionLevel: req.CompetitionLevel,
+		DueDays:          req.DueDays,
+		EscalationEmail:  req.EscalationEmail,
+		BackupReviewerID: req.BackupReviewerID,
+		UpdatedBy:        &userUUID,
+		UpdatedAt:        time.Now(),
+	}
+
+	return sla, true, nil
+}
+
+// reviewSLASaveErrorResponse response untuk error simpan SLA
+func reviewSLASaveErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrReviewSLAExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repository.ErrReviewSLANotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "SLA tidak ditemukan",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal menyimpan SLA review",
+	})
+}
@@ -0,0 +1,630 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetRolesService - List role
+// @Summary List roles
+// @Description Get all roles with their permissions and user count
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/v1/roles [get]
+func GetRolesService(c *fiber.Ctx) error {
+	roles, err := repository.GetRoles()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data role",
+		})
+	}
+
+	results := make([]fiber.Map, 0, len(roles))
+	for _, role := range roles {
+		permissions, err := repository.GetRolePermissions(role.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal mengambil data permission role",
+			})
+		}
+		userCount, _ := repository.CountUsersByRoleID(role.ID)
+
+		results = append(results, fiber.Map{
+			"id":                  role.ID,
+			"name":                role.Name,
+			"description":         role.Description,
+			"permissions_version": role.PermissionsVersion,
+			"created_at":          role.CreatedAt,
+			"permissions":         permissions,
+			"user_count":          userCount,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data role",
+		"data":    results,
+	})
+}
+
+// GetRoleDetailService - Detail role
+// @Summary Get role detail
+// @Description Get a role with its permissions and user count
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role UUID"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/roles/{id} [get]
+func GetRoleDetailService(c *fiber.Ctx) error {
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	role, err := repository.GetRoleByID(roleID)
+	if err != nil {
+		return roleLookupErrorResponse(c, err)
+	}
+
+	permissions, err := repository.GetRolePermissions(role.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permission role",
+		})
+	}
+	userCount, _ := repository.CountUsersByRoleID(role.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil detail role",
+		"data": fiber.Map{
+			"id":                  role.ID,
+			"name":                role.Name,
+			"description":         role.Description,
+			"permissions_version": role.PermissionsVersion,
+			"created_at":          role.CreatedAt,
+			"permissions":         permissions,
+			"user_count":          userCount,
+		},
+	})
+}
+
+// CreateRoleService - Buat role baru
+// @Summary Create role
+// @Description Create a new role without permissions
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.RoleRequest true "Role data"
+// @Success 201 {object} map[string]interface{} "Role created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 409 {object} map[string]interface{} "Conflict - name exists"
+// @Router /api/v1/roles [post]
+func CreateRoleService(c *fiber.Ctx) error {
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Nama role wajib diisi",
+		})
+	}
+
+	if existing, _ := repository.GetRoleByName(req.Name); existing != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Nama role sudah digunakan",
+		})
+	}
+
+	role := &model.Roles{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := repository.CreateRole(role); err != nil {
+		if errors.Is(err, repository.ErrRoleNameExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Nama role sudah digunakan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat role",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Role berhasil dibuat",
+		"data":    role,
+	})
+}
+
+// UpdateRoleService - Update role
+// @Summary Update role
+// @Description Update role name and description
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role UUID"
+// @Param body body model.RoleRequest true "Role data"
+// @Success 200 {object} map[string]interface{} "Updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Conflict - name exists"
+// @Router /api/v1/roles/{id} [put]
+func UpdateRoleService(c *fiber.Ctx) error {
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	var req model.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	role, err := repository.GetRoleByID(roleID)
+	if err != nil {
+		return roleLookupErrorResponse(c, err)
+	}
+
+	// Update field yang diisi saja
+	if name := strings.TrimSpace(req.Name); name != "" && name != role.Name {
+		if existing, _ := repository.GetRoleByName(name); existing != nil && existing.ID != role.ID {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Nama role sudah digunakan",
+			})
+		}
+		role.Name = name
+	}
+	if req.Description != "" {
+		role.Description = req.Description
+	}
+
+	if err := repository.UpdateRole(role); err != nil {
+		if errors.Is(err, repository.ErrRoleNameExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Nama role sudah digunakan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal update role",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role berhasil diupdate",
+		"data":    role,
+	})
+}
+
+// DeleteRoleService - Hapus role
+// @Summary Delete role
+// @Description Delete a role. Roles that still have users, or the last role holding manage_roles, cannot be deleted.
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role UUID"
+// @Success 200 {object} map[string]interface{} "Deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Role still in use"
+// @Router /api/v1/roles/{id} [delete]
+func DeleteRoleService(c *fiber.Ctx) error {
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	if err := repository.DeleteRole(roleID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoleHasUsers):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Role masih dipakai oleh user, pindahkan user ke role lain terlebih dahulu",
+			})
+		case errors.Is(err, repository.ErrLastManageRolesRole):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Role terakhir yang memiliki manage_roles tidak bisa dihapus",
+			})
+		case errors.Is(err, repository.ErrRoleNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Role tidak ditemukan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus role",
+		})
+	}
+
+	repository.RolePermissionCache().Invalidate(roleID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role berhasil dihapus",
+	})
+}
+
+// AttachRolePermissionService - Tambahkan permission ke role
+// @Summary Attach permission to role
+// @Description Grant a permission to a role; takes effect immediately for existing tokens
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role UUID"
+// @Param body body model.RolePermissionRequest true "Permission ID"
+// @Success 200 {object} map[string]interface{} "Attached successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/roles/{id}/permissions [post]
+func AttachRolePermissionService(c *fiber.Ctx) error {
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	var req model.RolePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	permissionID, err := uuid.Parse(req.PermissionID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid permission ID",
+		})
+	}
+
+	if _, err := repository.GetRoleByID(roleID); err != nil {
+		return roleLookupErrorResponse(c, err)
+	}
+
+	if _, err := repository.GetPermissionByID(permissionID); err != nil {
+		return permissionLookupErrorResponse(c, err)
+	}
+
+	if err := repository.AttachPermissionToRole(roleID, permissionID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menambahkan permission ke role",
+		})
+	}
+
+	repository.RolePermissionCache().Invalidate(roleID)
+
+	permissions, _ := repository.GetRolePermissions(roleID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission berhasil ditambahkan ke role",
+		"data": fiber.Map{
+			"role_id":     roleID,
+			"permissions": permissions,
+		},
+	})
+}
+
+// DetachRolePermissionService - Lepas permission dari role
+// @Summary Detach permission from role
+// @Description Revoke a permission from a role; takes effect immediately for existing tokens. manage_roles cannot be removed from the last role holding it.
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role UUID"
+// @Param permissionId path string true "Permission UUID"
+// @Success 200 {object} map[string]interface{} "Detached successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Role or permission not found, or permission not attached to the role"
+// @Failure 409 {object} map[string]interface{} "Last manage_roles role"
+// @Router /api/v1/roles/{id}/permissions/{permissionId} [delete]
+func DetachRolePermissionService(c *fiber.Ctx) error {
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	permissionID, err := uuid.Parse(c.Params("permissionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid permission ID",
+		})
+	}
+
+	if err := repository.DetachPermissionFromRole(roleID, permissionID); err != nil {
+		if errors.Is(err, repository.ErrLastManageRolesRole) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "manage_roles tidak bisa dilepas dari role terakhir yang memilikinya",
+			})
+		}
+		if errors.Is(err, repository.ErrRoleNotFound) || errors.Is(err, repository.ErrPermissionNotFound) ||
+			errors.Is(err, repository.ErrPermissionNotAttached) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal melepas permission dari role",
+		})
+	}
+
+	repository.RolePermissionCache().Invalidate(roleID)
+
+	permissions, _ := repository.GetRolePermissions(roleID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission berhasil dilepas dari role",
+		"data": fiber.Map{
+			"role_id":     roleID,
+			"permissions": permissions,
+		},
+	})
+}
+
+// GetPermissionsService - List permission
+// @Summary List permissions
+// @Description Get all permissions
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /api/v1/permissions [get]
+func GetPermissionsService(c *fiber.Ctx) error {
+	permissions, err := repository.GetPermissions()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permission",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data permission",
+		"data":    permissions,
+	})
+}
+
+// roleLookupErrorResponse 404 jika role tidak ada, 500 untuk error database lain
+func roleLookupErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrRoleNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Role tidak ditemukan",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal mengambil data role",
+	})
+}
+
+// permissionLookupErrorResponse 404 jika permission tidak ada, 500 untuk error database lain
+func permissionLookupErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrPermissionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Permission tidak ditemukan",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Gagal mengambil data permission",
+	})
+}
+
+// validatePermissionRequest memeriksa field wajib permission
+func validatePermissionRequest(req *model.PermissionRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Resource = strings.TrimSpace(req.Resource)
+	req.Action = strings.TrimSpace(req.Action)
+
+	if req.Name == "" || req.Resource == "" || req.Action == "" {
+		return errors.New("Nama, resource dan action wajib diisi")
+	}
+	if strings.ContainsAny(req.Name, " \t\n") {
+		return errors.New("Nama permission tidak boleh mengandung spasi")
+	}
+	return nil
+}
+
+// CreatePermissionService - Buat permission baru
+// @Summary Create permission
+// @Description Create a new permission
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.PermissionRequest true "Permission data"
+// @Success 201 {object} map[string]interface{} "Permission created"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 409 {object} map[string]interface{} "Conflict - name exists"
+// @Router /api/v1/permissions [post]
+func CreatePermissionService(c *fiber.Ctx) error {
+	var req model.PermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validatePermissionRequest(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if existing, _ := repository.GetPermissionByName(req.Name); existing != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Nama permission sudah digunakan",
+		})
+	}
+
+	permission := &model.Permissions{
+		Name:        req.Name,
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Description: req.Description,
+	}
+
+	if err := repository.CreatePermission(permission); err != nil {
+		if errors.Is(err, repository.ErrPermissionNameExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Nama permission sudah digunakan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat permission",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Permission berhasil dibuat",
+		"data":    permission,
+	})
+}
+
+// UpdatePermissionService - Update permission
+// @Summary Update permission
+// @Description Update a permission. manage_roles cannot be renamed.
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Permission UUID"
+// @Param body body model.PermissionRequest true "Permission data"
+// @Success 200 {object} map[string]interface{} "Updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Conflict"
+// @Router /api/v1/permissions/{id} [put]
+func UpdatePermissionService(c *fiber.Ctx) error {
+	permissionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid permission ID",
+		})
+	}
+
+	var req model.PermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validatePermissionRequest(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	permission, err := repository.GetPermissionByID(permissionID)
+	if err != nil {
+		return permissionLookupErrorResponse(c, err)
+	}
+
+	if permission.Name == repository.ManageRolesPermission && req.Name != permission.Name {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Permission manage_roles tidak bisa diganti namanya",
+		})
+	}
+
+	permission.Name = req.Name
+	permission.Resource = req.Resource
+	permission.Action = req.Action
+	permission.Description = req.Description
+
+	if err := repository.UpdatePermission(permission); err != nil {
+		if errors.Is(err, repository.ErrPermissionNameExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Nama permission sudah digunakan",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal update permission",
+		})
+	}
+
+	// Nama permission berubah untuk semua role yang memilikinya
+	repository.RolePermissionCache().InvalidateAll()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission berhasil diupdate",
+		"data":    permission,
+	})
+}
+
+// DeletePermissionService - Hapus permission
+// @Summary Delete permission
+// @Description Delete a permission and detach it from all roles. manage_roles cannot be deleted.
+// @Tags Role Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Permission UUID"
+// @Success 200 {object} map[string]interface{} "Deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Failure 409 {object} map[string]interface{} "Conflict"
+// @Router /api/v1/permissions/{id} [delete]
+func DeletePermissionService(c *fiber.Ctx) error {
+	permissionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid permission ID",
+		})
+	}
+
+	permission, err := repository.GetPermissionByID(permissionID)
+	if err != nil {
+		return permissionLookupErrorResponse(c, err)
+	}
+
+	if permission.Name == repository.ManageRolesPermission {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Permission manage_roles tidak bisa dihapus",
+		})
+	}
+
+	roleIDs, err := repository.DeletePermission(permissionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus permission",
+		})
+	}
+
+	for _, roleID := range roleIDs {
+		repository.RolePermissionCache().Invalidate(roleID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Permission berhasil dihapus",
+		"data": fiber.Map{
+			"detached_roles": len(roleIDs),
+		},
+	})
+}
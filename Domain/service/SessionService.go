@@ -0,0 +1,220 @@
+package service
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SessionResponse DTO sesi login dengan penanda sesi yang sedang dipakai
+type SessionResponse struct {
+	model.UserSessions
+	Current bool `json:"current"`
+}
+
+// toSessionResponses menandai sesi yang sedang dipakai oleh request ini
+func toSessionResponses(sessions []model.UserSessions, currentSessionID string) []SessionResponse {
+	results := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		results = append(results, SessionResponse{
+			UserSessions: session,
+			Current:      session.ID.String() == currentSessionID,
+		})
+	}
+	return results
+}
+
+// GetMySessionsService - List sesi aktif milik user yang sedang login
+// @Summary List my active sessions
+// @Description Get active login sessions (device, IP, user agent, issued/expires, last seen) of the caller
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/auth/sessions [get]
+func GetMySessionsService(c *fiber.Ctx) error {
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	sessions, err := repository.GetActiveSessionsByUserID(userUUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data sesi",
+		})
+	}
+
+	currentSessionID, _ := c.Locals("session_id").(string)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data sesi",
+		"data":    toSessionResponses(sessions, currentSessionID),
+	})
+}
+
+// RevokeMySessionService - Cabut salah satu sesi milik user yang sedang login
+// @Summary Revoke one of my sessions
+// @Description Revoke a session owned by the caller; tokens of that session stop working immediately
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session UUID"
+// @Success 200 {object} map[string]interface{} "Revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/auth/sessions/{id} [delete]
+func RevokeMySessionService(c *fiber.Ctx) error {
+	sessionUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid session ID",
+		})
+	}
+
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	// RevokeSession hanya mencabut sesi milik user ini
+	if err := repository.RevokeSession(sessionUUID, userUUID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Sesi tidak ditemukan",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Sesi berhasil dicabut",
+		"data": fiber.Map{
+			"session_id": sessionUUID,
+		},
+	})
+}
+
+// RevokeOtherSessionsService - Cabut semua sesi lain milik user yang sedang login
+// @Summary Revoke all my other sessions
+// @Description Revoke every session of the caller except the one used by this request
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /api/v1/auth/sessions [delete]
+func RevokeOtherSessionsService(c *fiber.Ctx) error {
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	currentSessionID, _ := c.Locals("session_id").(string)
+	currentSessionUUID, err := uuid.Parse(currentSessionID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Token ini tidak terikat ke sesi, silakan login ulang",
+		})
+	}
+
+	revoked, err := repository.RevokeOtherSessions(userUUID, currentSessionUUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mencabut sesi",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Semua sesi lain berhasil dicabut",
+		"data": fiber.Map{
+			"revoked": revoked,
+		},
+	})
+}
+
+// GetUserSessionsService - List sesi aktif seorang user (Admin)
+// @Summary List user sessions
+// @Description Get active login sessions of a user (Admin)
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User UUID"
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /api/v1/users/{id}/sessions [get]
+func GetUserSessionsService(c *fiber.Ctx) error {
+	userUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	sessions, err := repository.GetActiveSessionsByUserID(userUUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data sesi",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil data sesi user",
+		"data":    sessions,
+	})
+}
+
+// ForceLogoutUserService - Cabut semua sesi seorang user (Admin)
+// @Summary Force logout user
+// @Description Revoke all sessions and refresh tokens of a user (Admin)
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User UUID"
+// @Success 200 {object} map[string]interface{} "Revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/users/{id}/sessions [delete]
+func ForceLogoutUserService(c *fiber.Ctx) error {
+	userUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if _, err := repository.GetUserByID(userUUID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User tidak ditemukan",
+		})
+	}
+
+	revoked, err := repository.RevokeSessionsByUserID(userUUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mencabut sesi user",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User berhasil di-logout dari semua sesi",
+		"data": fiber.Map{
+			"user_id": userUUID,
+			"revoked": revoked,
+		},
+	})
+}
@@ -0,0 +1,612 @@
+package service
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	. "GOLANG/Domain/repository"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// twoFactorChallengeType nilai claim "typ" untuk challenge token login 2FA
+// JWTAuth menolak token yang memiliki claim "typ" sehingga challenge tidak bisa dipakai sebagai access token
+const twoFactorChallengeType = "2fa_challenge"
+
+const recoveryCodeCount = 10
+
+// twoFactorRequired mengecek apakah role dengan permissions ini wajib memakai 2FA
+func twoFactorRequired(permissions []string) bool {
+	for _, required := range config.GetTwoFactorRequiredPermissions() {
+		for _, p := range permissions {
+			if p == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTwoFactorEnabled mengecek apakah user sudah menyelesaikan enrollment 2FA
+func isTwoFactorEnabled(userID uuid.UUID) bool {
+	tfa, err := GetTwoFactorByUserID(userID)
+	return err == nil && tfa.EnabledAt != nil
+}
+
+// accessGrant isi otorisasi yang dimasukkan ke access token
+type accessGrant struct {
+	Permissions            []string
+	PermissionsVersion     int64
+	TwoFactorSetupRequired bool
+}
+
+// resolveAccess menentukan permission yang dimasukkan ke access token
+// User dengan role wajib 2FA yang belum enrollment mendapat token tanpa permission
+// sampai 2FA diaktifkan, sehingga hanya bisa mengakses endpoint 2FA, profile dan logout
+func resolveAccess(user *model.Users) (*accessGrant, error) {
+	permissions, version, err := RolePermissionCache().Get(user.RoleID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactorRequired(permissions) && !isTwoFactorEnabled(user.ID) {
+		return &accessGrant{
+			Permissions:            []string{},
+			PermissionsVersion:     version,
+			TwoFactorSetupRequired: true,
+		}, nil
+	}
+
+	return &accessGrant{
+		Permissions:        permissions,
+		PermissionsVersion: version,
+	}, nil
+}
+
+// generateTwoFactorChallenge membuat challenge token berumur pendek setelah password valid
+func generateTwoFactorChallenge(user *model.Users, device string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(config.GetTwoFactorChallengeExpiry())
+
+	claims := jwt.MapClaims{
+		"id":     user.ID.String(),
+		"typ":    twoFactorChallengeType,
+		"device": device,
+		"jti":    uuid.New().String(),
+		"exp":    expiresAt.Unix(),
+	}
+
+	signed, err := config.SignJWT(claims)
+	return signed, expiresAt, err
+}
+
+// parseTwoFactorChallenge memvalidasi challenge token dan mengembalikan user id serta device
+func parseTwoFactorChallenge(tokenString string) (uuid.UUID, string, error) {
+	token, err := config.ParseJWT(tokenString)
+	if err != nil || !token.Valid {
+		return uuid.Nil, "", errors.New("challenge token tidak valid")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != twoFactorChallengeType {
+		return uuid.Nil, "", errors.New("challenge token tidak valid")
+	}
+
+	id, _ := claims["id"].(string)
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, "", errors.New("challenge token tidak valid")
+	}
+
+	device, _ := claims["device"].(string)
+	return userID, device, nil
+}
+
+// generateRecoveryCodes membuat recovery code acak (format xxxxx-xxxxx) beserta hash-nya
+func generateRecoveryCodes() ([]string, []string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = alphabet[int(b[j])%len(alphabet)]
+		}
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashToken(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode mengabaikan tanda hubung, spasi dan huruf besar saat mencocokkan recovery code
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// verifySecondFactor memvalidasi kode TOTP atau recovery code untuk user dengan 2FA aktif
+func verifySecondFactor(tfa *model.UserTwoFactor, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		return UseRecoveryCode(tfa.UserID, HashToken(normalizeRecoveryCode(recoveryCode)))
+	}
+
+	step, ok := VerifyTOTP(tfa.Secret, code, time.Now(), tfa.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+
+	// Compare-and-set agar kode yang sama tidak bisa dipakai dua kali secara bersamaan
+	return MarkTwoFactorStepUsed(tfa.UserID, step)
+}
+
+// twoFactorChallengeResponse respon langkah pertama login untuk user dengan 2FA aktif
+func twoFactorChallengeResponse(c *fiber.Ctx, user *model.Users, device string) error {
+	challenge, expiresAt, err := generateTwoFactorChallenge(user, device)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat challenge 2FA",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":              "Masukkan kode 2FA untuk melanjutkan login",
+		"two_factor_required":  true,
+		"challenge_token":      challenge,
+		"challenge_expires_at": expiresAt,
+	})
+}
+
+// LoginTwoFactorService langkah kedua login: tukar challenge token + kode 2FA dengan token
+// @Summary Complete login with 2FA
+// @Description Exchange the challenge token returned by /auth/login and a TOTP code (or a recovery code) for access and refresh tokens
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param body body model.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} map[string]interface{} "Login successful"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Router /auth/login/2fa [post]
+func LoginTwoFactorService(c *fiber.Ctx) error {
+	var body model.TwoFactorLoginRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.ChallengeToken == "" || (body.Code == "" && body.RecoveryCode == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Challenge token dan kode 2FA harus diisi",
+		})
+	}
+
+	userID, device, err := parseTwoFactorChallenge(body.ChallengeToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Challenge 2FA tidak valid atau sudah kadaluarsa, silakan login ulang",
+		})
+	}
+	if body.Device != "" {
+		device = body.Device
+	}
+
+	// Kode 2FA ikut dibatasi seperti password agar tidak bisa di-brute force
+	accountKey := "2fa:" + userID.String()
+	ipKey := IPThrottleKey(c.IP())
+
+	wait, err := loginThrottler.Check(accountKey, ipKey)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memproses login, silakan coba lagi",
+		})
+	}
+	if wait > 0 {
+		return loginThrottledResponse(c, wait)
+	}
+
+	user, err := GetUserByID(userID)
+	if err != nil || !user.IsActive {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Akun tidak ditemukan atau dinonaktifkan",
+		})
+	}
+
+	tfa, err := GetTwoFactorByUserID(userID)
+	if err != nil || tfa.EnabledAt == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Challenge 2FA tidak valid atau sudah kadaluarsa, silakan login ulang",
+		})
+	}
+
+	ok, err := verifySecondFactor(tfa, body.Code, body.RecoveryCode)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memvalidasi kode 2FA",
+		})
+	}
+	if !ok {
+		_ = loginThrottler.RegisterFailure(accountKey, ipKey)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Kode 2FA salah",
+		})
+	}
+
+	_ = loginThrottler.RegisterSuccess(accountKey)
+
+	return completeLogin(c, user, device)
+}
+
+// GetTwoFactorStatusService - Status 2FA milik user yang sedang login
+// @Summary Get 2FA status
+// @Description Get whether 2FA is enabled, required for the caller's role, and how many recovery codes are left
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Success"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/auth/2fa [get]
+func GetTwoFactorStatusService(c *fiber.Ctx) error {
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	permissions, err := GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permissions",
+		})
+	}
+
+	data := fiber.Map{
+		"enabled":                  false,
+		"enabled_at":               nil,
+		"required":                 twoFactorRequired(permissions),
+		"recovery_codes_remaining": 0,
+	}
+
+	if tfa, err := GetTwoFactorByUserID(user.ID); err == nil && tfa.EnabledAt != nil {
+		remaining, _ := CountUnusedRecoveryCodes(user.ID)
+		data["enabled"] = true
+		data["enabled_at"] = tfa.EnabledAt
+		data["recovery_codes_remaining"] = remaining
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Berhasil mengambil status 2FA",
+		"data":    data,
+	})
+}
+
+// EnrollTwoFactorService - Mulai enrollment 2FA
+// @Summary Start 2FA enrollment
+// @Description Generate a new TOTP secret and otpauth URI. 2FA is not active until confirmed via /2fa/enable.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Secret generated"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Already enabled"
+// @Router /api/v1/auth/2fa/enroll [post]
+func EnrollTwoFactorService(c *fiber.Ctx) error {
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if isTwoFactorEnabled(user.ID) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "2FA sudah aktif, nonaktifkan terlebih dahulu untuk enrollment ulang",
+		})
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat secret 2FA",
+		})
+	}
+
+	if err := SaveTwoFactorSecret(user.ID, secret); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan secret 2FA",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Scan QR code di aplikasi authenticator lalu konfirmasi dengan kode pertama",
+		"data": fiber.Map{
+			"secret":      secret,
+			"otpauth_uri": TOTPURI(config.GetTwoFactorIssuer(), user.Username, secret),
+		},
+	})
+}
+
+// EnableTwoFactorService - Konfirmasi enrollment 2FA dengan kode pertama
+// @Summary Confirm 2FA enrollment
+// @Description Activate 2FA with the first TOTP code and return one-time recovery codes (shown only once)
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} map[string]interface{} "2FA enabled"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /api/v1/auth/2fa/enable [post]
+func EnableTwoFactorService(c *fiber.Ctx) error {
+	var body model.TwoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA harus diisi",
+		})
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	tfa, err := GetTwoFactorByUserID(user.ID)
+	if err != nil || tfa.EnabledAt != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Tidak ada enrollment 2FA yang menunggu konfirmasi",
+		})
+	}
+
+	step, ok := VerifyTOTP(tfa.Secret, body.Code, time.Now(), tfa.LastUsedStep)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA salah",
+		})
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat recovery code",
+		})
+	}
+
+	if err := EnableTwoFactor(user.ID, step, hashes); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengaktifkan 2FA",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "2FA berhasil diaktifkan. Simpan recovery code di tempat aman; login ulang untuk mendapatkan akses penuh",
+		"data": fiber.Map{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// DisableTwoFactorService - Nonaktifkan 2FA milik sendiri
+// @Summary Disable 2FA
+// @Description Disable 2FA after confirming a TOTP or recovery code. Not allowed for roles where 2FA is mandatory.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.TwoFactorCodeRequest true "TOTP code or recovery code"
+// @Success 200 {object} map[string]interface{} "2FA disabled"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 403 {object} map[string]interface{} "2FA mandatory"
+// @Router /api/v1/auth/2fa/disable [post]
+func DisableTwoFactorService(c *fiber.Ctx) error {
+	var body model.TwoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Code == "" && body.RecoveryCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA atau recovery code harus diisi",
+		})
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	permissions, err := GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data permissions",
+		})
+	}
+	if twoFactorRequired(permissions) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "2FA wajib untuk role Anda dan tidak bisa dinonaktifkan",
+		})
+	}
+
+	tfa, err := GetTwoFactorByUserID(user.ID)
+	if err != nil || tfa.EnabledAt == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "2FA belum aktif",
+		})
+	}
+
+	ok, err := verifySecondFactor(tfa, body.Code, body.RecoveryCode)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memvalidasi kode 2FA",
+		})
+	}
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA salah",
+		})
+	}
+
+	if err := DisableTwoFactor(user.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menonaktifkan 2FA",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "2FA berhasil dinonaktifkan",
+	})
+}
+
+// RegenerateRecoveryCodesService - Buat ulang recovery code
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes after confirming a TOTP code; old codes stop working
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body model.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} map[string]interface{} "Recovery codes regenerated"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /api/v1/auth/2fa/recovery-codes [post]
+func RegenerateRecoveryCodesService(c *fiber.Ctx) error {
+	var body model.TwoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if body.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA harus diisi",
+		})
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	tfa, err := GetTwoFactorByUserID(user.ID)
+	if err != nil || tfa.EnabledAt == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "2FA belum aktif",
+		})
+	}
+
+	ok, err := verifySecondFactor(tfa, body.Code, "")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal memvalidasi kode 2FA",
+		})
+	}
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Kode 2FA salah",
+		})
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal membuat recovery code",
+		})
+	}
+
+	if err := ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menyimpan recovery code",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Recovery code berhasil dibuat ulang",
+		"data": fiber.Map{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// ResetUserTwoFactorService - Reset 2FA seorang user (Admin)
+// @Summary Reset user 2FA
+// @Description Remove a user's 2FA (lost device and recovery codes) and revoke all their sessions (Admin)
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User UUID"
+// @Success 200 {object} map[string]interface{} "2FA reset"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Not found"
+// @Router /api/v1/users/{id}/2fa [delete]
+func ResetUserTwoFactorService(c *fiber.Ctx) error {
+	userUUID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if _, err := GetUserByID(userUUID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User tidak ditemukan",
+		})
+	}
+
+	if err := DisableTwoFactor(userUUID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mereset 2FA user",
+		})
+	}
+
+	if _, err := RevokeSessionsByUserID(userUUID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mencabut sesi user",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "2FA user berhasil direset",
+		"data": fiber.Map{
+			"user_id": userUUID,
+		},
+	})
+}
+
+// currentUser mengambil user yang sedang login dari context JWT
+func currentUser(c *fiber.Ctx) (*model.Users, error) {
+	userID, _ := c.Locals("id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	return GetUserByID(userUUID)
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// postJSON mengirim body JSON dan mengembalikan status response
+func postJSON(t *testing.T, app *fiber.App, path string, payload map[string]interface{}) int {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	return resp.StatusCode
+}
+
+// TestCreateAchievementCommentService_EmptyBody tests that a blank comment is refused
+func TestCreateAchievementCommentService_EmptyBody(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/comments", policyLoadedAchievement, service.CreateAchievementCommentService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/comments", map[string]interface{}{
+		"body": "   ",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status = postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/comments", map[string]interface{}{
+		"body": strings.Repeat("a", 5001),
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// TestCreateAchievementCommentService_InvalidParentID tests that parent_id must be a UUID
+func TestCreateAchievementCommentService_InvalidParentID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/comments", policyLoadedAchievement, service.CreateAchievementCommentService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/comments", map[string]interface{}{
+		"body":      "Sudah saya perbaiki",
+		"parent_id": "bukan-uuid",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// TestCreateAchievementCommentService_WithoutPolicy tests that commenting is refused when the access policy did not run
+func TestCreateAchievementCommentService_WithoutPolicy(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/comments", service.CreateAchievementCommentService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/comments", map[string]interface{}{
+		"body": "Mohon dicek ulang",
+	})
+	assert.Equal(t, fiber.StatusForbidden, status)
+}
+
+// TestRequestRevisionService_RequiresSubmitted tests that a revision can only be requested for a submitted achievement
+func TestRequestRevisionService_RequiresSubmitted(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/request-revision", policyLoadedAchievement, service.RequestRevisionService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/request-revision", map[string]interface{}{
+		"body": "",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	// policyLoadedAchievement memuat reference berstatus draft
+	status = postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/request-revision", map[string]interface{}{
+		"body": "Lampirkan sertifikat",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
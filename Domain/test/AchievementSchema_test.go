@@ -0,0 +1,140 @@
+package test
+
+import (
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fieldNames mengambil nama field dari daftar error validasi
+func fieldNames(errs []service.FieldError) []string {
+	names := make([]string, 0, len(errs))
+	for _, e := range errs {
+		names = append(names, e.Field)
+	}
+	return names
+}
+
+// TestValidateAchievementDetails_RejectsFieldsOfOtherTypes tests that a competition cannot carry publication fields
+func TestValidateAchievementDetails_RejectsFieldsOfOtherTypes(t *testing.T) {
+	details := &mongodb.AchievementDetails{
+		CompetitionName: stringPtr("Hackathon Nasional"),
+		ISSN:            stringPtr("1234-5678"),
+		Location:        stringPtr("Surabaya"),
+	}
+
+	errs := service.ValidateAchievementDetails("competition", details)
+	assert.ElementsMatch(t, []string{"details.competitionLevel", "details.issn"}, fieldNames(errs))
+}
+
+// TestValidateAchievementDetails_ValidPublication tests a publication with all required fields
+func TestValidateAchievementDetails_ValidPublication(t *testing.T) {
+	details := &mongodb.AchievementDetails{
+		PublicationTitle: stringPtr("Deteksi Plagiarisme"),
+		PublicationType:  stringPtr("journal"),
+		Authors:          []string{"Mahasiswa", "Dosen"},
+		ISSN:             stringPtr("1234-5678"),
+	}
+
+	assert.Empty(t, service.ValidateAchievementDetails("publication", details))
+}
+
+// TestCompileCustomFieldSchema_RejectsUnsupportedKeyword tests that unsupported keywords are refused instead of ignored
+func TestCompileCustomFieldSchema_RejectsUnsupportedKeyword(t *testing.T) {
+	_, err := service.CompileCustomFieldSchema([]byte(`{"type": "object", "oneOf": [{"required": ["a"]}]}`))
+	assert.Error(t, err)
+
+	_, err = service.CompileCustomFieldSchema([]byte(`{"type": "string"}`))
+	assert.Error(t, err)
+}
+
+// TestCustomFieldSchema_PerFieldErrors tests that every violation is reported with its field path
+func TestCustomFieldSchema_PerFieldErrors(t *testing.T) {
+	schema, err := service.CompileCustomFieldSchema([]byte(`{
+		"type": "object",
+		"required": ["sponsor", "teamSize"],
+		"additionalProperties": false,
+		"properties": {
+			"sponsor": {"type": "string", "minLength": 3},
+			"teamSize": {"type": "integer", "minimum": 1, "maximum": 5},
+			"category": {"enum": ["software", "hardware"]},
+			"members": {"type": "array", "items": {"type": "string", "format": "email"}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	errs := schema.Validate(map[string]any{
+		"teamSize": 2.5,
+		"category": "design",
+		"members":  []any{"a@kampus.ac.id", "bukan-email"},
+		"extra":    true,
+	}, "customFields")
+
+	assert.ElementsMatch(t, []string{
+		"customFields.sponsor",
+		"customFields.teamSize",
+		"customFields.category",
+		"customFields.members[1]",
+		"customFields.extra",
+	}, fieldNames(errs))
+
+	// Nilai dari MongoDB (primitive.D, int32) divalidasi sama dengan nilai dari body JSON
+	errs = schema.Validate(map[string]any{
+		"sponsor":  "Bank Daerah",
+		"teamSize": int32(3),
+		"members":  primitive.A{"a@kampus.ac.id"},
+	}, "customFields")
+	assert.Empty(t, errs)
+}
+
+// TestUpdateAchievementService_FieldErrors tests that the 400 response lists every invalid details field
+func TestUpdateAchievementService_FieldErrors(t *testing.T) {
+	app := fiber.New()
+	app.Put("/achievements/:id", service.UpdateAchievementService)
+
+	updateData := map[string]interface{}{
+		"title":           "Juara 1 Hackathon",
+		"achievementType": "competition",
+		"details": map[string]interface{}{
+			"competitionName": "Hackathon Nasional",
+			"issn":            "1234-5678",
+		},
+		"updatedAt": "2024-12-04T10:00:00Z",
+	}
+	body, _ := json.Marshal(updateData)
+
+	req := httptest.NewRequest("PUT", "/achievements/507f1f77bcf86cd799439011", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Fields []service.FieldError `json:"fields"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.ElementsMatch(t, []string{"details.competitionLevel", "details.issn"}, fieldNames(result.Fields))
+}
+
+// TestPutAchievementTypeSchemaService_InvalidSchema tests that an unsupported schema is refused before saving
+func TestPutAchievementTypeSchemaService_InvalidSchema(t *testing.T) {
+	app := fiber.New()
+	app.Put("/achievement-types/:type/custom-fields-schema", service.PutAchievementTypeSchemaService)
+
+	req := httptest.NewRequest("PUT", "/achievement-types/competition/custom-fields-schema",
+		bytes.NewBufferString(`{"type": "object", "properties": {"sponsor": {"type": "text"}}}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -1,13 +1,17 @@
 package test
 
 import (
+	model "GOLANG/Domain/model/Postgresql"
 	"GOLANG/Domain/service"
 	"bytes"
 	"encoding/json"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,3 +136,195 @@ func TestRejectAchievementService_MissingRejectionNote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
+
+// TestGetAchievementDetailService_InvalidAchievementID tests detail with invalid achievement ID
+func TestGetAchievementDetailService_InvalidAchievementID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/achievements/:id", service.GetAchievementDetailService)
+
+	req := httptest.NewRequest("GET", "/achievements/invalid-id", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestGetAchievementDetailService_WithoutPolicy tests that detail is refused when the access policy did not run
+func TestGetAchievementDetailService_WithoutPolicy(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		return c.Next()
+	})
+
+	app.Get("/achievements/:id", service.GetAchievementDetailService)
+
+	req := httptest.NewRequest("GET", "/achievements/507f1f77bcf86cd799439011", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// TestUpdateAchievementService_MissingUpdatedAt tests that update without the updatedAt precondition is refused
+func TestUpdateAchievementService_MissingUpdatedAt(t *testing.T) {
+	app := fiber.New()
+	app.Put("/achievements/:id", service.UpdateAchievementService)
+
+	updateData := map[string]interface{}{
+		"title":           "Juara 1 Hackathon",
+		"achievementType": "other",
+	}
+	body, _ := json.Marshal(updateData)
+
+	req := httptest.NewRequest("PUT", "/achievements/507f1f77bcf86cd799439011", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusPreconditionRequired, resp.StatusCode)
+}
+
+// TestUpdateAchievementService_InvalidCompetitionDetails tests type-specific validation of details
+func TestUpdateAchievementService_InvalidCompetitionDetails(t *testing.T) {
+	app := fiber.New()
+	app.Put("/achievements/:id", service.UpdateAchievementService)
+
+	updateData := map[string]interface{}{
+		"title":           "Juara 1 Hackathon",
+		"achievementType": "competition",
+		"details": map[string]interface{}{
+			"competitionName": "Hackathon Nasional",
+		},
+		"updatedAt": "2024-12-04T10:00:00Z",
+	}
+	body, _ := json.Marshal(updateData)
+
+	req := httptest.NewRequest("PUT", "/achievements/507f1f77bcf86cd799439011", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Contains(t, result["error"], "competitionLevel")
+}
+
+// TestUpdateAchievementService_WithoutPolicy tests that a valid update is refused when the access policy did not run
+func TestUpdateAchievementService_WithoutPolicy(t *testing.T) {
+	app := fiber.New()
+	app.Put("/achievements/:id", service.UpdateAchievementService)
+
+	updateData := map[string]interface{}{
+		"title":           "Sertifikasi Cloud",
+		"achievementType": "certification",
+		"details": map[string]interface{}{
+			"certificationName": "Cloud Practitioner",
+			"issuedBy":          "Cloud Provider",
+		},
+		"updatedAt": "2024-12-04T10:00:00Z",
+	}
+	body, _ := json.Marshal(updateData)
+
+	req := httptest.NewRequest("PUT", "/achievements/507f1f77bcf86cd799439011", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// TestGetAchievementHistoryService_InvalidAchievementID tests history with invalid achievement ID
+func TestGetAchievementHistoryService_InvalidAchievementID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/achievements/:id/history", service.GetAchievementHistoryService)
+
+	req := httptest.NewRequest("GET", "/achievements/invalid-id/history", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestGetAchievementHistoryService_WithoutPolicy tests that history is refused when the access policy did not run
+func TestGetAchievementHistoryService_WithoutPolicy(t *testing.T) {
+	app := fiber.New()
+	app.Get("/achievements/:id/history", service.GetAchievementHistoryService)
+
+	req := httptest.NewRequest("GET", "/achievements/507f1f77bcf86cd799439011/history", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// uploadRequest membuat request multipart dengan satu file di field "file"
+func uploadRequest(t *testing.T, url, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", url, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// policyLoadedAchievement mock Authorize: reference draft milik mahasiswa sudah dimuat
+func policyLoadedAchievement(c *fiber.Ctx) error {
+	studentID := uuid.New()
+	c.Locals("achievement_reference", &model.AchievementReferences{
+		ID:                 uuid.New(),
+		StudentID:          studentID,
+		MongoAchievementID: c.Params("id"),
+		Status:             "draft",
+	})
+	c.Locals("achievement_student", &model.Students{ID: studentID})
+	c.Locals("policy_rule", "owner")
+	return c.Next()
+}
+
+// TestUploadAttachmentService_WithoutPolicy tests that upload is refused when the access policy did not run
+func TestUploadAttachmentService_WithoutPolicy(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/attachments", service.UploadAttachmentService)
+
+	req := uploadRequest(t, "/achievements/507f1f77bcf86cd799439011/attachments", "bukti.pdf", []byte("%PDF-1.4"))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// TestUploadAttachmentService_RejectsDisguisedFile tests that the type is sniffed from content, not the file name
+func TestUploadAttachmentService_RejectsDisguisedFile(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/attachments", policyLoadedAchievement, service.UploadAttachmentService)
+
+	req := uploadRequest(t, "/achievements/507f1f77bcf86cd799439011/attachments", "bukti.pdf", []byte("<html><script>alert(1)</script></html>"))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+// TestUploadAttachmentService_FileTooLarge tests the attachment size limit
+func TestUploadAttachmentService_FileTooLarge(t *testing.T) {
+	t.Setenv("ATTACHMENT_MAX_SIZE_MB", "1")
+
+	app := fiber.New()
+	app.Post("/achievements/:id/attachments", policyLoadedAchievement, service.UploadAttachmentService)
+
+	content := append([]byte("%PDF-1.4\n"), make([]byte, 1<<20)...)
+	req := uploadRequest(t, "/achievements/507f1f77bcf86cd799439011/attachments", "bukti.pdf", content)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/repository"
+	"GOLANG/Domain/service"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// policyLoadedAchievementWith memuat reference dengan status dan rule policy tertentu
+func policyLoadedAchievementWith(status model.AchievementStatus, rule string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		studentID := uuid.New()
+		c.Locals("achievement_reference", &model.AchievementReferences{
+			ID:                 uuid.New(),
+			StudentID:          studentID,
+			MongoAchievementID: c.Params("id"),
+			Status:             status,
+			CurrentStage:       1,
+		})
+		c.Locals("achievement_student", &model.Students{ID: studentID})
+		c.Locals("policy_rule", rule)
+		return c.Next()
+	}
+}
+
+// TestFindAchievementTransition tests the transition table lookup
+func TestFindAchievementTransition(t *testing.T) {
+	rule, err := model.FindAchievementTransition(model.AchievementActionSubmit, model.AchievementStatusRevisionRequested)
+	assert.NoError(t, err)
+	assert.Equal(t, model.AchievementStatusSubmitted, rule.To)
+	assert.Equal(t, model.AchievementActorOwner, rule.Actor)
+
+	rule, err = model.FindAchievementTransition(model.AchievementActionVerify, model.AchievementStatusSubmitted)
+	assert.NoError(t, err)
+	assert.Equal(t, model.AchievementStatusVerified, rule.To)
+	assert.Equal(t, model.AchievementActorApprover, rule.Actor)
+
+	_, err = model.FindAchievementTransition(model.AchievementActionVerify, model.AchievementStatusVerified)
+	assert.ErrorIs(t, err, model.ErrAchievementTransitionNotAllowed)
+
+	_, err = model.FindAchievementTransition(model.AchievementActionDelete, model.AchievementStatusSubmitted)
+	assert.ErrorIs(t, err, model.ErrAchievementTransitionNotAllowed)
+
+	assert.Equal(t,
+		[]model.AchievementStatus{model.AchievementStatusDraft, model.AchievementStatusRevisionRequested},
+		model.AchievementActionSources(model.AchievementActionSubmit),
+	)
+}
+
+// TestDeleteAchievementService_RequiresDraft tests that only a draft achievement can be deleted
+func TestDeleteAchievementService_RequiresDraft(t *testing.T) {
+	app := fiber.New()
+	app.Delete("/achievements/:id", policyLoadedAchievementWith(model.AchievementStatusSubmitted, "owner"), service.DeleteAchievementService)
+
+	req := httptest.NewRequest("DELETE", "/achievements/507f1f77bcf86cd799439011", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestVerifyAchievementService_ActorMismatch tests that the owner cannot run an approver transition
+func TestVerifyAchievementService_ActorMismatch(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", policyLoadedAchievementWith(model.AchievementStatusSubmitted, "owner"), service.VerifyAchievementService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/verify", map[string]interface{}{})
+	assert.Equal(t, fiber.StatusForbidden, status)
+}
+
+// TestWithdrawAchievementService_RequiresSubmitted tests that only a submitted achievement can be withdrawn
+func TestWithdrawAchievementService_RequiresSubmitted(t *testing.T) {
+	app := fiber.New()
+	app.Post("/achievements/:id/withdraw", policyLoadedAchievementWith(model.AchievementStatusVerified, "owner"), service.WithdrawAchievementService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/withdraw", map[string]interface{}{})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// TestWithdrawAchievementService_AfterStageApproved tests that withdraw is refused once a reviewer approved a stage
+func TestWithdrawAchievementService_AfterStageApproved(t *testing.T) {
+	app := fiber.New()
+	approvedFirstStage := func(c *fiber.Ctx) error {
+		c.Locals("achievement_reference").(*model.AchievementReferences).CurrentStage = 2
+		return c.Next()
+	}
+	app.Post("/achievements/:id/withdraw", policyLoadedAchievementWith(model.AchievementStatusSubmitted, "owner"), approvedFirstStage, service.WithdrawAchievementService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/withdraw", map[string]interface{}{})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// TestRevokeAchievementVerificationService_Validation tests the mandatory reason and that only a verified achievement can be revoked
+func TestRevokeAchievementVerificationService_Validation(t *testing.T) {
+	app := fiber.New()
+	app.Post("/verified/:id/revoke", policyLoadedAchievementWith(model.AchievementStatusVerified, "admin"), service.RevokeAchievementVerificationService)
+	app.Post("/submitted/:id/revoke", policyLoadedAchievementWith(model.AchievementStatusSubmitted, "admin"), service.RevokeAchievementVerificationService)
+
+	status := postJSON(t, app, "/verified/507f1f77bcf86cd799439011/revoke", map[string]interface{}{
+		"reason": "  ",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status = postJSON(t, app, "/submitted/507f1f77bcf86cd799439011/revoke", map[string]interface{}{
+		"reason": "Sertifikat palsu",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// recordingPointsDocuments dokumen achievement in-memory yang mencatat penulisan poin
+type recordingPointsDocuments struct {
+	achievement *mongodb.Achievement
+	writes      int
+}
+
+func (d *recordingPointsDocuments) GetAchievementByID(id primitive.ObjectID) (*mongodb.Achievement, error) {
+	return d.achievement, nil
+}
+
+func (d *recordingPointsDocuments) SetAchievementPoints(id primitive.ObjectID, points, version int) error {
+	d.writes++
+	d.achievement.Points = points
+	return nil
+}
+
+// conflictingReferences penyimpanan reference yang selalu kalah compare-and-set
+type conflictingReferences struct{}
+
+func (conflictingReferences) TransitionAchievementReference(ref *model.AchievementReferences, fromStatus model.AchievementStatus, fromStage int, history *model.AchievementStatusHistory, comment *model.AchievementComments) (bool, error) {
+	return false, nil
+}
+
+func (conflictingReferences) DeleteAchievementReference(id uuid.UUID, status model.AchievementStatus) (bool, error) {
+	return false, nil
+}
+
+// TestRevokeAchievementVerificationService_ConflictKeepsPoints tests that a revoke losing the compare-and-set leaves the points untouched
+func TestRevokeAchievementVerificationService_ConflictKeepsPoints(t *testing.T) {
+	objectID, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	documents := &recordingPointsDocuments{
+		achievement: &mongodb.Achievement{ID: objectID, Title: "Juara 1 Hackathon", Points: 30, PointsVersion: 2},
+	}
+	service.SetAchievementDocuments(documents)
+	service.SetAchievementReferences(conflictingReferences{})
+	t.Cleanup(func() {
+		service.SetAchievementDocuments(repository.MongoAchievementDocuments{})
+		service.SetAchievementReferences(repository.PostgresAchievementReferences{})
+	})
+
+	app := fiber.New()
+	withUserID := func(c *fiber.Ctx) error {
+		c.Locals("id", uuid.New().String())
+		return c.Next()
+	}
+	app.Post("/achievements/:id/revoke", policyLoadedAchievementWith(model.AchievementStatusVerified, "admin"), withUserID, service.RevokeAchievementVerificationService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/revoke", map[string]interface{}{
+		"reason": "Sertifikat palsu",
+	})
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, 0, documents.writes)
+	assert.Equal(t, 30, documents.achievement.Points)
+}
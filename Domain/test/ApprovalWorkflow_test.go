@@ -0,0 +1,140 @@
+package test
+
+import (
+	"GOLANG/Domain/middleware"
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateApprovalWorkflowService_FieldErrors tests that every invalid workflow field is reported before saving
+func TestCreateApprovalWorkflowService_FieldErrors(t *testing.T) {
+	app := fiber.New()
+	app.Post("/approval-workflows", service.CreateApprovalWorkflowService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"achievement_type":  "publication",
+		"competition_level": "nasional",
+		"name":              " ",
+		"stages": []map[string]interface{}{
+			{"name": "Dosen Wali", "required_permission": "verify_achievements", "advisor_only": true},
+			{"name": "", "required_permission": ""},
+		},
+	})
+	req := httptest.NewRequest("POST", "/approval-workflows", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Fields []service.FieldError `json:"fields"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.ElementsMatch(t, []string{
+		"name",
+		"competition_level",
+		"stages[1].name",
+		"stages[1].required_permission",
+	}, fieldNames(result.Fields))
+}
+
+// TestCreateApprovalWorkflowService_UnknownCompetitionLevel tests that competition_level must follow the taxonomy
+func TestCreateApprovalWorkflowService_UnknownCompetitionLevel(t *testing.T) {
+	app := fiber.New()
+	app.Post("/approval-workflows", service.CreateApprovalWorkflowService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"achievement_type":  "competition",
+		"competition_level": "antar-galaksi",
+		"name":              "Kompetisi",
+		"stages":            []map[string]interface{}{},
+	})
+	req := httptest.NewRequest("POST", "/approval-workflows", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Fields []service.FieldError `json:"fields"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.ElementsMatch(t, []string{"competition_level", "stages"}, fieldNames(result.Fields))
+}
+
+// TestResolveApprovalStage_OutOfRange tests that a stage outside the workflow is never treated as the final stage
+func TestResolveApprovalStage_OutOfRange(t *testing.T) {
+	faculty := []model.ApprovalStage{
+		{Order: 1, Name: "Dosen Wali", RequiredPermission: "verify_achievements", AdvisorOnly: true},
+		{Order: 2, Name: "Kemahasiswaan", RequiredPermission: "approve_student_affairs"},
+		{Order: 3, Name: "Wakil Dekan", RequiredPermission: "approve_vice_dean"},
+	}
+
+	stage, isFinal, err := model.ResolveApprovalStage(faculty, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Kemahasiswaan", stage.Name)
+	assert.False(t, isFinal)
+
+	_, isFinal, err = model.ResolveApprovalStage(faculty, 3)
+	require.NoError(t, err)
+	assert.True(t, isFinal)
+
+	// Workflow dihapus saat prestasi di tahap 2: reference kembali ke alur bawaan satu tahap
+	stage, isFinal, err = model.ResolveApprovalStage(model.DefaultApprovalStages, 2)
+	assert.ErrorIs(t, err, model.ErrApprovalStageOutOfRange)
+	assert.Nil(t, stage)
+	assert.False(t, isFinal)
+
+	// Workflow dipendekkan menjadi dua tahap saat prestasi di tahap 3
+	_, isFinal, err = model.ResolveApprovalStage(faculty[:2], 3)
+	assert.ErrorIs(t, err, model.ErrApprovalStageOutOfRange)
+	assert.False(t, isFinal)
+}
+
+// TestVerifyAchievementService_WorkflowDeletedAtStageTwo tests that an item left at stage 2 by a deleted workflow cannot be verified by the advisor
+func TestVerifyAchievementService_WorkflowDeletedAtStageTwo(t *testing.T) {
+	app := fiber.New()
+	workflowDeleted := func(c *fiber.Ctx) error {
+		ref := c.Locals("achievement_reference").(*model.AchievementReferences)
+		ref.WorkflowID = nil
+		ref.CurrentStage = 2
+		return c.Next()
+	}
+	app.Post("/achievements/:id/verify", policyLoadedAchievementWith(model.AchievementStatusSubmitted, "approver"), workflowDeleted, service.VerifyAchievementService)
+
+	status := postJSON(t, app, "/achievements/507f1f77bcf86cd799439011/verify", map[string]interface{}{})
+	assert.Equal(t, fiber.StatusConflict, status)
+}
+
+// TestEvaluatePolicy_AchievementStageMissing tests that no approver, including the advisor, may act on a stage missing from the workflow
+func TestEvaluatePolicy_AchievementStageMissing(t *testing.T) {
+	advisorID := uuid.New()
+	res := &middleware.Resource{
+		Type:           "achievement",
+		OwnerStudentID: uuid.New(),
+		AdvisorID:      advisorID,
+		Status:         "submitted",
+		StageMissing:   true,
+	}
+
+	advisor := policySubject("verify_achievements")
+	advisor.LecturerID = &advisorID
+	_, ok := middleware.EvaluatePolicy("achievement", "verify", advisor, res)
+	assert.False(t, ok)
+
+	// Dosen wali tetap bisa membaca prestasi mahasiswa bimbingannya
+	rule, ok := middleware.EvaluatePolicy("achievement", "read", advisor, res)
+	assert.True(t, ok)
+	assert.Equal(t, "advisor", rule)
+}
@@ -63,5 +63,52 @@ func TestLoginService_InvalidEmail(t *testing.T) {
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
 
+// TestRefreshTokenService_InvalidRequestBody tests refresh with invalid request body
+func TestRefreshTokenService_InvalidRequestBody(t *testing.T) {
+	app := fiber.New()
+	app.Post("/refresh", service.RefreshTokenService)
+
+	req := httptest.NewRequest("POST", "/refresh", bytes.NewBufferString("invalid json"))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestRefreshTokenService_MissingToken tests refresh without refresh token
+func TestRefreshTokenService_MissingToken(t *testing.T) {
+	app := fiber.New()
+	app.Post("/refresh", service.RefreshTokenService)
+
+	body, _ := json.Marshal(map[string]string{"device": "laptop"})
+
+	req := httptest.NewRequest("POST", "/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestGetProfileService_InvalidUserID tests get profile with invalid user ID in context
+func TestGetProfileService_InvalidUserID(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "invalid-uuid")
+		return c.Next()
+	})
+
+	app.Get("/profile", service.GetProfileService)
+
+	req := httptest.NewRequest("GET", "/profile", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
 // Note: Tests for successful login require database mocking
 // which will be implemented in integration tests
@@ -0,0 +1,69 @@
+package test
+
+import (
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeCompetitionLevel_Aliases tests that Indonesian and English aliases map to the canonical level
+func TestNormalizeCompetitionLevel_Aliases(t *testing.T) {
+	cases := map[string]string{
+		"Nasional":       "national",
+		" INTERNASIONAL": "international",
+		"provinsi":       "regional",
+		"Tingkat Kampus": "",
+		"kampus":         "campus",
+	}
+
+	for input, expected := range cases {
+		level, ok := service.NormalizeCompetitionLevel(input)
+		assert.Equal(t, expected != "", ok, input)
+		assert.Equal(t, expected, level, input)
+	}
+
+	medal, ok := service.NormalizeMedalType("Juara Harapan")
+	assert.True(t, ok)
+	assert.Equal(t, "honorable_mention", medal)
+}
+
+// TestValidateAchievementDetails_NormalizesTaxonomy tests that aliases are rewritten and unknown values are refused
+func TestValidateAchievementDetails_NormalizesTaxonomy(t *testing.T) {
+	details := &mongodb.AchievementDetails{
+		CompetitionName:  stringPtr("Gemastik"),
+		CompetitionLevel: stringPtr("Nasional"),
+		MedalType:        stringPtr("emas"),
+	}
+
+	assert.Empty(t, service.ValidateAchievementDetails("competition", details))
+	assert.Equal(t, "national", *details.CompetitionLevel)
+	assert.Equal(t, "gold", *details.MedalType)
+
+	details.CompetitionLevel = stringPtr("antar kelas")
+	errs := service.ValidateAchievementDetails("competition", details)
+	assert.Equal(t, []string{"details.competitionLevel"}, fieldNames(errs))
+}
+
+// TestCreatePointRuleSetService_UnknownCompetitionLevel tests that rule conditions must use the level taxonomy
+func TestCreatePointRuleSetService_UnknownCompetitionLevel(t *testing.T) {
+	app := fiber.New()
+	app.Post("/point-rules", service.CreatePointRuleSetService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"rules": []map[string]interface{}{
+			{"achievement_type": "competition", "conditions": map[string]string{"competitionLevel": "antar kelas"}, "points": 5},
+		},
+	})
+	req := httptest.NewRequest("POST", "/point-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
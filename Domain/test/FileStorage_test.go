@@ -0,0 +1,131 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 object storage in-memory yang mencatat header request terakhir
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.headers = r.Header.Clone()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// storageRoundTrip Put -> Open -> Delete -> Open (not found)
+func storageRoundTrip(t *testing.T, storage config.FileStorage, key string) {
+	content := []byte("%PDF-1.4 bukti prestasi")
+
+	err := storage.Put(key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+	require.NoError(t, err)
+
+	reader, err := storage.Open(key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	require.NoError(t, storage.Delete(key))
+
+	_, err = storage.Open(key)
+	assert.ErrorIs(t, err, config.ErrFileNotFound)
+}
+
+// TestLocalFileStorage_RoundTrip tests put, open and delete on the local filesystem
+func TestLocalFileStorage_RoundTrip(t *testing.T) {
+	storage := config.NewLocalFileStorage(t.TempDir())
+	storageRoundTrip(t, storage, "achievements/507f1f77bcf86cd799439011/bukti.pdf")
+}
+
+// TestLocalFileStorage_RejectsPathTraversal tests that keys cannot escape the storage directory
+func TestLocalFileStorage_RejectsPathTraversal(t *testing.T) {
+	storage := config.NewLocalFileStorage(t.TempDir())
+
+	for _, key := range []string{"../escape.pdf", "/etc/passwd", "achievements/../../escape.pdf", ""} {
+		err := storage.Put(key, strings.NewReader("x"), 1, "application/pdf")
+		assert.Error(t, err, key)
+	}
+}
+
+// TestS3FileStorage_SignedPathStyleRequests tests the S3 backend against an in-memory S3 server
+func TestS3FileStorage_SignedPathStyleRequests(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	storage := &config.S3FileStorage{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "evidence",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+		Now:             func() time.Time { return time.Date(2024, 12, 4, 10, 0, 0, 0, time.UTC) },
+	}
+
+	content := []byte("%PDF-1.4")
+	require.NoError(t, storage.Put("achievements/a b/bukti.pdf", bytes.NewReader(content), int64(len(content)), "application/pdf"))
+
+	// Path-style: /<bucket>/<key>, spasi di-encode
+	assert.Contains(t, fake.objects, "/evidence/achievements/a b/bukti.pdf")
+	assert.Equal(t, "UNSIGNED-PAYLOAD", fake.headers.Get("X-Amz-Content-Sha256"))
+	assert.Equal(t, "20241204T100000Z", fake.headers.Get("X-Amz-Date"))
+	assert.True(t, strings.HasPrefix(fake.headers.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20241204/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+
+	storageRoundTrip(t, storage, "achievements/507f1f77bcf86cd799439011/bukti.pdf")
+}
+
+// TestS3FileStorage_MinIO runs the round trip against a real S3-compatible server (e.g. local MinIO)
+// Set S3_TEST_ENDPOINT, S3_TEST_BUCKET, S3_TEST_ACCESS_KEY_ID and S3_TEST_SECRET_ACCESS_KEY to enable.
+func TestS3FileStorage_MinIO(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT tidak diisi")
+	}
+
+	storage := &config.S3FileStorage{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		Bucket:          os.Getenv("S3_TEST_BUCKET"),
+		AccessKeyID:     os.Getenv("S3_TEST_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_TEST_SECRET_ACCESS_KEY"),
+		PathStyle:       true,
+	}
+	storageRoundTrip(t, storage, "test/"+time.Now().Format("20060102150405")+".pdf")
+}
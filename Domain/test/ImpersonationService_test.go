@@ -0,0 +1,79 @@
+package test
+
+import (
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStartImpersonationService_InvalidUserID tests start impersonation with invalid target UUID
+func TestStartImpersonationService_InvalidUserID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/impersonate", service.StartImpersonationService)
+
+	body, _ := json.Marshal(map[string]string{"user_id": "bukan-uuid", "reason": "Tiket #123"})
+	req := httptest.NewRequest("POST", "/impersonate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestStartImpersonationService_MissingReason tests start impersonation without a reason
+func TestStartImpersonationService_MissingReason(t *testing.T) {
+	app := fiber.New()
+	app.Post("/impersonate", service.StartImpersonationService)
+
+	body, _ := json.Marshal(map[string]string{"user_id": "550e8400-e29b-41d4-a716-446655440000", "reason": "  "})
+	req := httptest.NewRequest("POST", "/impersonate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestStartImpersonationService_Nested tests that an impersonation token cannot start another impersonation
+func TestStartImpersonationService_Nested(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware dengan token impersonation
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		c.Locals("impersonator_id", "660e8400-e29b-41d4-a716-446655440000")
+		return c.Next()
+	})
+
+	app.Post("/impersonate", service.StartImpersonationService)
+
+	body, _ := json.Marshal(map[string]string{"user_id": "770e8400-e29b-41d4-a716-446655440000", "reason": "Tiket #123"})
+	req := httptest.NewRequest("POST", "/impersonate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// TestStopImpersonationService_NotImpersonating tests stop with a regular token
+func TestStopImpersonationService_NotImpersonating(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		return c.Next()
+	})
+
+	app.Post("/impersonate/stop", service.StopImpersonationService)
+
+	req := httptest.NewRequest("POST", "/impersonate/stop", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePrivateKeyPEM menulis private key PKCS8 ke <dir>/<kid>.pem
+func writePrivateKeyPEM(t *testing.T, dir, kid string, key interface{}) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600))
+}
+
+// useJWTKeysDir memuat key set dari folder dan mengembalikan ke HS256 setelah test selesai
+func useJWTKeysDir(t *testing.T, dir, activeKID string) {
+	t.Setenv("JWT_KEYS_DIR", dir)
+	t.Setenv("JWT_ACTIVE_KID", activeKID)
+	ks, err := config.LoadJWTKeySet()
+	require.NoError(t, err)
+	config.SetJWTKeySet(ks)
+	t.Cleanup(func() { config.SetJWTKeySet(nil) })
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"id":  "550e8400-e29b-41d4-a716-446655440000",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// TestJWTKeySet_RotationKeepsOldTokensValid tests that tokens signed by a previous key still verify after rotation
+func TestJWTKeySet_RotationKeepsOldTokensValid(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	writePrivateKeyPEM(t, dir, "2025-01", rsaKey)
+	useJWTKeysDir(t, dir, "2025-01")
+
+	oldToken, err := config.SignJWT(testClaims())
+	require.NoError(t, err)
+
+	// Rotasi: tambah kunci baru dan jadikan aktif
+	writePrivateKeyPEM(t, dir, "2025-02", edKey)
+	useJWTKeysDir(t, dir, "2025-02")
+
+	newToken, err := config.SignJWT(testClaims())
+	require.NoError(t, err)
+
+	parsed, err := config.ParseJWT(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+
+	parsed, err = config.ParseJWT(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01", parsed.Header["kid"])
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+
+	jwks := config.JWKS()["keys"].([]map[string]string)
+	require.Len(t, jwks, 2)
+	assert.Equal(t, "RSA", jwks[0]["kty"])
+	assert.Equal(t, "OKP", jwks[1]["kty"])
+}
+
+// TestJWTKeySet_RejectsHS256InAsymmetricMode tests that HS256 tokens are rejected once asymmetric keys are configured
+func TestJWTKeySet_RejectsHS256InAsymmetricMode(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKeyPEM(t, dir, "k1", edKey)
+	useJWTKeysDir(t, dir, "")
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	token.Header["kid"] = "k1"
+	hsToken, err := token.SignedString([]byte(config.GetJWTSecret()))
+	require.NoError(t, err)
+
+	_, err = config.ParseJWT(hsToken)
+	assert.Error(t, err)
+}
+
+// TestJWTKeySet_UnknownKID tests that tokens with an unknown kid are rejected
+func TestJWTKeySet_UnknownKID(t *testing.T) {
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKeyPEM(t, dir, "k1", edKey)
+	useJWTKeysDir(t, dir, "k1")
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims())
+	token.Header["kid"] = "k2"
+	signed, err := token.SignedString(otherKey)
+	require.NoError(t, err)
+
+	_, err = config.ParseJWT(signed)
+	assert.Error(t, err)
+}
+
+// TestLoadJWTKeySet_RefusesDefaultSecretOutsideDev tests startup refusal with the built-in secret
+func TestLoadJWTKeySet_RefusesDefaultSecretOutsideDev(t *testing.T) {
+	t.Setenv("JWT_KEYS_DIR", "")
+	t.Setenv("JWT_SECRET", "")
+
+	t.Setenv("APP_ENV", "production")
+	_, err := config.LoadJWTKeySet()
+	assert.Error(t, err)
+
+	t.Setenv("APP_ENV", "development")
+	_, err = config.LoadJWTKeySet()
+	assert.NoError(t, err)
+
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", "a-real-secret-from-the-vault")
+	_, err = config.LoadJWTKeySet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"GOLANG/Domain/repository"
+	"GOLANG/Domain/service"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock clock yang bisa dimajukan secara manual
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newTestThrottler() (*service.LoginThrottler, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)}
+	policy := service.LoginThrottlePolicy{
+		AccountMaxFailures: 3,
+		IPMaxFailures:      5,
+		BaseDelay:          time.Second,
+		MaxDelay:           4 * time.Second,
+		LockoutDuration:    15 * time.Minute,
+		FailureWindow:      15 * time.Minute,
+	}
+	return service.NewLoginThrottler(repository.NewInMemoryLoginAttempts(), clock, policy), clock
+}
+
+// TestLoginThrottler_ExponentialBackoff tests delay doubling after each failure
+func TestLoginThrottler_ExponentialBackoff(t *testing.T) {
+	throttler, clock := newTestThrottler()
+	account := service.AccountThrottleKey("Budi@Example.com")
+	ip := service.IPThrottleKey("10.0.0.1")
+
+	wait, err := throttler.Check(account, ip)
+	assert.NoError(t, err)
+	assert.Zero(t, wait)
+
+	assert.NoError(t, throttler.RegisterFailure(account, ip))
+	wait, _ = throttler.Check(account, ip)
+	assert.Equal(t, time.Second, wait)
+
+	clock.Advance(time.Second)
+	wait, _ = throttler.Check(account, ip)
+	assert.Zero(t, wait)
+
+	assert.NoError(t, throttler.RegisterFailure(account, ip))
+	wait, _ = throttler.Check(account, ip)
+	assert.Equal(t, 2*time.Second, wait)
+}
+
+// TestLoginThrottler_LockoutAfterMaxFailures tests temporary lockout after N failures
+func TestLoginThrottler_LockoutAfterMaxFailures(t *testing.T) {
+	throttler, clock := newTestThrottler()
+	account := service.AccountThrottleKey("budi")
+	ip := service.IPThrottleKey("10.0.0.1")
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, throttler.RegisterFailure(account, ip))
+	}
+
+	wait, _ := throttler.Check(account, ip)
+	assert.Equal(t, 15*time.Minute, wait)
+
+	clock.Advance(15 * time.Minute)
+	wait, _ = throttler.Check(account, ip)
+	assert.Zero(t, wait)
+}
+
+// TestLoginThrottler_SuccessResetsAccount tests counter reset after successful login
+func TestLoginThrottler_SuccessResetsAccount(t *testing.T) {
+	throttler, _ := newTestThrottler()
+	account := service.AccountThrottleKey("budi")
+	ip := service.IPThrottleKey("10.0.0.1")
+
+	assert.NoError(t, throttler.RegisterFailure(account, ip))
+	assert.NoError(t, throttler.RegisterSuccess(account))
+
+	wait, _ := throttler.Check(account, ip)
+	assert.Zero(t, wait)
+}
+
+// TestLoginThrottler_IPLockout tests lockout per IP across different accounts
+func TestLoginThrottler_IPLockout(t *testing.T) {
+	throttler, _ := newTestThrottler()
+	ip := service.IPThrottleKey("10.0.0.1")
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, throttler.RegisterFailure(service.AccountThrottleKey(name), ip))
+	}
+
+	wait, _ := throttler.Check(service.AccountThrottleKey("f"), ip)
+	assert.Equal(t, 15*time.Minute, wait)
+
+	wait, _ = throttler.Check(service.AccountThrottleKey("f"), service.IPThrottleKey("10.0.0.2"))
+	assert.Zero(t, wait)
+}
+
+// TestLoginThrottler_Unlock tests admin unlock clearing the lockout
+func TestLoginThrottler_Unlock(t *testing.T) {
+	throttler, _ := newTestThrottler()
+	account := service.AccountThrottleKey("budi")
+	ip := service.IPThrottleKey("10.0.0.1")
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, throttler.RegisterFailure(account, ip))
+	}
+
+	assert.NoError(t, throttler.Unlock(account))
+	wait, _ := throttler.Check(account, ip)
+	assert.Zero(t, wait)
+}
+
+// TestUnlockUserService_InvalidUserID tests unlock with invalid user ID
+func TestUnlockUserService_InvalidUserID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/users/:id/unlock", service.UnlockUserService)
+
+	req := httptest.NewRequest("POST", "/users/invalid-id/unlock", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
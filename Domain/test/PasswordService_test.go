@@ -0,0 +1,97 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestForgotPasswordService_InvalidEmail tests forgot password with invalid email format
+func TestForgotPasswordService_InvalidEmail(t *testing.T) {
+	app := fiber.New()
+	app.Post("/password/forgot", service.ForgotPasswordService)
+
+	body, _ := json.Marshal(map[string]string{"email": "bukan-email"})
+	req := httptest.NewRequest("POST", "/password/forgot", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestResetPasswordService_MissingFields tests reset password without token
+func TestResetPasswordService_MissingFields(t *testing.T) {
+	app := fiber.New()
+	app.Post("/password/reset", service.ResetPasswordService)
+
+	body, _ := json.Marshal(map[string]string{"new_password": "passwordbaru"})
+	req := httptest.NewRequest("POST", "/password/reset", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestResetPasswordService_ShortPassword tests reset password with a too short password
+func TestResetPasswordService_ShortPassword(t *testing.T) {
+	app := fiber.New()
+	app.Post("/password/reset", service.ResetPasswordService)
+
+	body, _ := json.Marshal(map[string]string{"token": "abc", "new_password": "123"})
+	req := httptest.NewRequest("POST", "/password/reset", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestChangePasswordService_SamePassword tests change password with identical new password
+func TestChangePasswordService_SamePassword(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		return c.Next()
+	})
+
+	app.Post("/password/change", service.ChangePasswordService)
+
+	body, _ := json.Marshal(map[string]string{
+		"current_password": "password123",
+		"new_password":     "password123",
+	})
+	req := httptest.NewRequest("POST", "/password/change", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestNewMailer_Drivers tests mailer selection from MAIL_DRIVER
+func TestNewMailer_Drivers(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "log")
+	m, err := config.NewMailer()
+	assert.NoError(t, err)
+	assert.IsType(t, config.LogMailer{}, m)
+
+	t.Setenv("MAIL_DRIVER", "smtp")
+	t.Setenv("SMTP_HOST", "")
+	_, err = config.NewMailer()
+	assert.Error(t, err)
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	m, err = config.NewMailer()
+	assert.NoError(t, err)
+	assert.IsType(t, &config.SMTPMailer{}, m)
+}
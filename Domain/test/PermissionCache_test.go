@@ -0,0 +1,140 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePermissionLoader loader in-memory yang mencatat jumlah query
+type fakePermissionLoader struct {
+	permissions  map[uuid.UUID][]string
+	versions     map[uuid.UUID]int64
+	loads        int
+	versionReads int
+}
+
+func newFakePermissionLoader() *fakePermissionLoader {
+	return &fakePermissionLoader{
+		permissions: make(map[uuid.UUID][]string),
+		versions:    make(map[uuid.UUID]int64),
+	}
+}
+
+func (f *fakePermissionLoader) set(roleID uuid.UUID, permissions ...string) {
+	f.permissions[roleID] = permissions
+	f.versions[roleID]++
+}
+
+func (f *fakePermissionLoader) LoadRolePermissions(roleID uuid.UUID) ([]string, int64, error) {
+	f.loads++
+	return f.permissions[roleID], f.versions[roleID], nil
+}
+
+func (f *fakePermissionLoader) GetRolePermissionsVersion(roleID uuid.UUID) (int64, error) {
+	f.versionReads++
+	return f.versions[roleID], nil
+}
+
+// TestPermissionCache_VersionCheck tests that cache reloads only when the role version changes
+func TestPermissionCache_VersionCheck(t *testing.T) {
+	roleID := uuid.New()
+	loader := newFakePermissionLoader()
+	loader.set(roleID, "read_achievements", "verify_achievements")
+
+	now := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	cache := repository.NewPermissionCache(loader, 5*time.Second).WithClock(func() time.Time { return now })
+
+	perms, version, err := cache.Get(roleID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read_achievements", "verify_achievements"}, perms)
+	assert.Equal(t, int64(1), version)
+
+	// Masih segar: tidak ada query
+	_, _, _ = cache.Get(roleID, 0)
+	assert.Equal(t, 1, loader.loads)
+	assert.Equal(t, 0, loader.versionReads)
+
+	// Lewat interval, versi sama: hanya cek versi
+	now = now.Add(6 * time.Second)
+	_, _, _ = cache.Get(roleID, 0)
+	assert.Equal(t, 1, loader.loads)
+	assert.Equal(t, 1, loader.versionReads)
+
+	// Permission dicabut (versi naik): reload setelah interval
+	loader.set(roleID, "read_achievements")
+	now = now.Add(6 * time.Second)
+	perms, version, _ = cache.Get(roleID, 0)
+	assert.Equal(t, []string{"read_achievements"}, perms)
+	assert.Equal(t, int64(2), version)
+	assert.Equal(t, 2, loader.loads)
+}
+
+// TestPermissionCache_TokenVersionForcesReload tests that a token with a newer pv claim forces reload
+func TestPermissionCache_TokenVersionForcesReload(t *testing.T) {
+	roleID := uuid.New()
+	loader := newFakePermissionLoader()
+	loader.set(roleID, "read_achievements")
+
+	cache := repository.NewPermissionCache(loader, time.Hour)
+	_, _, _ = cache.Get(roleID, 0)
+
+	loader.set(roleID, "read_achievements", "verify_achievements")
+
+	perms, _, err := cache.Get(roleID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read_achievements", "verify_achievements"}, perms)
+	assert.Equal(t, 2, loader.loads)
+}
+
+// TestJWTAuth_PermissionRevokedImmediately tests that JWTAuth uses current role permissions instead of token claims
+func TestJWTAuth_PermissionRevokedImmediately(t *testing.T) {
+	roleID := uuid.New()
+	loader := newFakePermissionLoader()
+	loader.set(roleID, "verify_achievements")
+
+	cache := repository.NewPermissionCache(loader, time.Hour)
+	previous := repository.RolePermissionCache()
+	repository.SetPermissionCache(cache)
+	defer repository.SetPermissionCache(previous)
+
+	claims := jwt.MapClaims{
+		"id":          "550e8400-e29b-41d4-a716-446655440000",
+		"role_id":     roleID.String(),
+		"permissions": []string{"verify_achievements"},
+		"pv":          1,
+		"exp":         time.Now().Add(time.Hour).Unix(),
+	}
+	tokenString, err := config.SignJWT(claims)
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
+	app.Get("/verify", middleware.RequirePermission("verify_achievements"), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	req := httptest.NewRequest("GET", "/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// Admin mencabut permission lalu cache di-invalidate
+	loader.set(roleID)
+	cache.Invalidate(roleID)
+
+	req = httptest.NewRequest("GET", "/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
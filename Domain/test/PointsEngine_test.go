@@ -0,0 +1,134 @@
+package test
+
+import (
+	model "GOLANG/Domain/model/Postgresql"
+	mongodb "GOLANG/Domain/model/mongoDB"
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// samplePointRules rule set contoh: poin dasar per tipe ditambah bonus tingkat dan juara
+func samplePointRules() []model.PointRules {
+	return []model.PointRules{
+		{AchievementType: "competition", Points: 10, Description: "Dasar kompetisi"},
+		{AchievementType: "competition", Conditions: map[string]string{"competitionLevel": "national"}, Points: 20, Description: "Tingkat nasional"},
+		{AchievementType: "competition", Conditions: map[string]string{"competitionLevel": "national", "rank": "1"}, Points: 15, Description: "Juara 1 nasional"},
+		{AchievementType: "publication", Conditions: map[string]string{"publicationType": "journal"}, Points: 30, Description: "Jurnal"},
+		{AchievementType: "*", Conditions: map[string]string{"medalType": "gold"}, Points: 5, Description: "Medali emas"},
+	}
+}
+
+// TestCalculatePoints_SumsMatchingRules tests that every matching rule adds its points
+func TestCalculatePoints_SumsMatchingRules(t *testing.T) {
+	achievement := &mongodb.Achievement{
+		AchievementType: "competition",
+		Details: mongodb.AchievementDetails{
+			CompetitionLevel: stringPtr("National"),
+			Rank:             intPtr(1),
+			MedalType:        stringPtr("gold"),
+		},
+	}
+
+	points, breakdown := service.CalculatePoints(samplePointRules(), achievement)
+	assert.Equal(t, 50, points)
+	assert.Len(t, breakdown, 4)
+}
+
+// TestCalculatePoints_MissingDetailDoesNotMatch tests that a condition on an empty detail field never matches
+func TestCalculatePoints_MissingDetailDoesNotMatch(t *testing.T) {
+	achievement := &mongodb.Achievement{
+		AchievementType: "competition",
+		Details: mongodb.AchievementDetails{
+			CompetitionLevel: stringPtr("regional"),
+		},
+	}
+
+	points, breakdown := service.CalculatePoints(samplePointRules(), achievement)
+	assert.Equal(t, 10, points)
+	assert.Len(t, breakdown, 1)
+
+	other := &mongodb.Achievement{AchievementType: "organization"}
+	points, breakdown = service.CalculatePoints(samplePointRules(), other)
+	assert.Equal(t, 0, points)
+	assert.Empty(t, breakdown)
+}
+
+// TestCreatePointRuleSetService_UnknownConditionField tests create rule set with an unsupported condition field
+func TestCreatePointRuleSetService_UnknownConditionField(t *testing.T) {
+	app := fiber.New()
+	app.Post("/point-rules", service.CreatePointRuleSetService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"rules": []map[string]interface{}{
+			{"achievement_type": "competition", "conditions": map[string]string{"organizer": "Kemendikbud"}, "points": 10},
+		},
+	})
+	req := httptest.NewRequest("POST", "/point-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestCreatePointRuleSetService_InvalidAchievementType tests create rule set with an unknown achievement type
+func TestCreatePointRuleSetService_InvalidAchievementType(t *testing.T) {
+	app := fiber.New()
+	app.Post("/point-rules", service.CreatePointRuleSetService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"rules": []map[string]interface{}{
+			{"achievement_type": "sports", "points": 10},
+		},
+	})
+	req := httptest.NewRequest("POST", "/point-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestPreviewAchievementPointsService_InvalidVersion tests preview with a non-numeric rule set version
+func TestPreviewAchievementPointsService_InvalidVersion(t *testing.T) {
+	app := fiber.New()
+	app.Get("/achievements/:id/points/preview", policyLoadedAchievement, service.PreviewAchievementPointsService)
+
+	req := httptest.NewRequest("GET", "/achievements/507f1f77bcf86cd799439011/points/preview?version=abc", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestCreatePointRuleSetService_MissingUserID tests that a valid rule set without an authenticated user id is rejected
+func TestCreatePointRuleSetService_MissingUserID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/point-rules", service.CreatePointRuleSetService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"rules": []map[string]interface{}{
+			{"achievement_type": "competition", "conditions": map[string]string{"competitionLevel": "national"}, "points": 10},
+		},
+	})
+	req := httptest.NewRequest("POST", "/point-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
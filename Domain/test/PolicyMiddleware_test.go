@@ -0,0 +1,156 @@
+package test
+
+import (
+	"GOLANG/Domain/middleware"
+	model "GOLANG/Domain/model/Postgresql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func policySubject(permissions ...string) *middleware.Subject {
+	sub := &middleware.Subject{
+		UserID:      uuid.New(),
+		Permissions: make(map[string]bool),
+	}
+	for _, p := range permissions {
+		sub.Permissions[p] = true
+	}
+	return sub
+}
+
+// TestEvaluatePolicy_AchievementRead tests owner, advisor and admin read access
+func TestEvaluatePolicy_AchievementRead(t *testing.T) {
+	studentID := uuid.New()
+	advisorID := uuid.New()
+	res := &middleware.Resource{Type: "achievement", OwnerStudentID: studentID, AdvisorID: advisorID}
+
+	owner := policySubject("read_achievements")
+	owner.StudentID = &studentID
+	rule, ok := middleware.EvaluatePolicy("achievement", "read", owner, res)
+	assert.True(t, ok)
+	assert.Equal(t, "owner", rule)
+
+	advisor := policySubject("verify_achievements")
+	advisor.LecturerID = &advisorID
+	rule, ok = middleware.EvaluatePolicy("achievement", "read", advisor, res)
+	assert.True(t, ok)
+	assert.Equal(t, "advisor", rule)
+
+	admin := policySubject(middleware.AdminAchievementsPermission)
+	rule, ok = middleware.EvaluatePolicy("achievement", "read", admin, res)
+	assert.True(t, ok)
+	assert.Equal(t, "admin", rule)
+}
+
+// TestEvaluatePolicy_AchievementReadOtherStudent tests that a student cannot read another student's achievement
+func TestEvaluatePolicy_AchievementReadOtherStudent(t *testing.T) {
+	res := &middleware.Resource{Type: "achievement", OwnerStudentID: uuid.New(), AdvisorID: uuid.New()}
+
+	otherStudentID := uuid.New()
+	other := policySubject("read_achievements")
+	other.StudentID = &otherStudentID
+
+	_, ok := middleware.EvaluatePolicy("achievement", "read", other, res)
+	assert.False(t, ok)
+
+	otherLecturerID := uuid.New()
+	lecturer := policySubject("verify_achievements")
+	lecturer.LecturerID = &otherLecturerID
+
+	_, ok = middleware.EvaluatePolicy("achievement", "read", lecturer, res)
+	assert.False(t, ok)
+}
+
+// TestEvaluatePolicy_AchievementVerifyAdvisorOnly tests that only the advisor may verify, not the owner or admin
+func TestEvaluatePolicy_AchievementVerifyAdvisorOnly(t *testing.T) {
+	studentID := uuid.New()
+	advisorID := uuid.New()
+	res := &middleware.Resource{Type: "achievement", OwnerStudentID: studentID, AdvisorID: advisorID}
+
+	owner := policySubject("write_achievements")
+	owner.StudentID = &studentID
+	_, ok := middleware.EvaluatePolicy("achievement", "verify", owner, res)
+	assert.False(t, ok)
+
+	admin := policySubject(middleware.AdminAchievementsPermission)
+	_, ok = middleware.EvaluatePolicy("achievement", "verify", admin, res)
+	assert.False(t, ok)
+
+	advisor := policySubject("verify_achievements")
+	advisor.LecturerID = &advisorID
+	_, ok = middleware.EvaluatePolicy("achievement", "verify", advisor, res)
+	assert.True(t, ok)
+}
+
+// TestEvaluatePolicy_AdvisorWithoutAdvisee tests that a student without advisor does not match a lecturer
+func TestEvaluatePolicy_AdvisorWithoutAdvisee(t *testing.T) {
+	res := &middleware.Resource{Type: "student", OwnerStudentID: uuid.New()}
+
+	nilID := uuid.Nil
+	lecturer := policySubject()
+	lecturer.LecturerID = &nilID
+
+	_, ok := middleware.EvaluatePolicy("student", "read", lecturer, res)
+	assert.False(t, ok)
+}
+
+// TestAuthorize_InvalidAchievementID tests that an invalid ID is rejected before any lookup
+func TestAuthorize_InvalidAchievementID(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		c.Locals("permissions", []interface{}{"read_achievements"})
+		return c.Next()
+	})
+
+	app.Get("/achievements/:id", middleware.Authorize("achievement", "read"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/achievements/invalid-id", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestAuthorize_UnknownPolicy tests that registering a route with an unknown policy fails fast
+func TestAuthorize_UnknownPolicy(t *testing.T) {
+	assert.Panics(t, func() { middleware.Authorize("achievement", "publish") })
+	assert.Panics(t, func() { middleware.Authorize("course", "read") })
+}
+
+// TestEvaluatePolicy_AchievementVerifyWorkflowStage tests that a later workflow stage is approved by its permission holder, not the advisor
+func TestEvaluatePolicy_AchievementVerifyWorkflowStage(t *testing.T) {
+	advisorID := uuid.New()
+	res := &middleware.Resource{
+		Type:           "achievement",
+		OwnerStudentID: uuid.New(),
+		AdvisorID:      advisorID,
+		Status:         "submitted",
+		ApprovalStage:  &model.ApprovalStage{Order: 2, Name: "Kemahasiswaan", RequiredPermission: "approve_student_affairs"},
+	}
+
+	advisor := policySubject("verify_achievements")
+	advisor.LecturerID = &advisorID
+	_, ok := middleware.EvaluatePolicy("achievement", "verify", advisor, res)
+	assert.False(t, ok)
+
+	staff := policySubject("approve_student_affairs", "read_achievements")
+	rule, ok := middleware.EvaluatePolicy("achievement", "verify", staff, res)
+	assert.True(t, ok)
+	assert.Equal(t, "approver", rule)
+
+	// Approver tahap hanya bisa membaca prestasi yang sedang menunggu persetujuannya
+	_, ok = middleware.EvaluatePolicy("achievement", "read", staff, res)
+	assert.True(t, ok)
+
+	res.Status = "verified"
+	_, ok = middleware.EvaluatePolicy("achievement", "read", staff, res)
+	assert.False(t, ok)
+}
@@ -0,0 +1,296 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	model "GOLANG/Domain/model/Postgresql"
+	"GOLANG/Domain/repository"
+	"GOLANG/Domain/service"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReviewEscalations repository eskalasi in-memory
+type fakeReviewEscalations struct {
+	reviews     []repository.OverdueReview
+	escalatedTo map[uuid.UUID]*uuid.UUID
+}
+
+func (f *fakeReviewEscalations) GetOverdueReviews(now time.Time, limit int) ([]repository.OverdueReview, error) {
+	var overdue []repository.OverdueReview
+	for _, review := range f.reviews {
+		if _, done := f.escalatedTo[review.ReferenceID]; !done && review.ReviewDueAt.Before(now) {
+			overdue = append(overdue, review)
+		}
+	}
+	return overdue, nil
+}
+
+func (f *fakeReviewEscalations) MarkReviewEscalated(referenceID uuid.UUID, stage int, escalatedTo *uuid.UUID, at time.Time) (bool, error) {
+	if _, done := f.escalatedTo[referenceID]; done {
+		return false, nil
+	}
+	for _, review := range f.reviews {
+		if review.ReferenceID == referenceID && review.CurrentStage != stage {
+			return false, nil
+		}
+	}
+	f.escalatedTo[referenceID] = escalatedTo
+	return true, nil
+}
+
+// advanceStage meniru persetujuan satu tahap: batas waktu dan eskalasi tahap baru diambil dari ref
+func (f *fakeReviewEscalations) advanceStage(referenceID uuid.UUID, ref *model.AchievementReferences, advisorOnly bool) {
+	for i := range f.reviews {
+		if f.reviews[i].ReferenceID == referenceID {
+			f.reviews[i].CurrentStage++
+			f.reviews[i].ReviewDueAt = *ref.ReviewDueAt
+			f.reviews[i].AdvisorOnlyStage = advisorOnly
+		}
+	}
+	if ref.EscalatedAt == nil {
+		delete(f.escalatedTo, referenceID)
+	}
+}
+
+// fakeAdvisoryLock lock yang dibagi beberapa "replica" dalam satu proses
+type fakeAdvisoryLock struct {
+	mu   sync.Mutex
+	held bool
+}
+
+func (l *fakeAdvisoryLock) TryLock() (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held {
+		return nil, false, nil
+	}
+	l.held = true
+	return func() {
+		l.mu.Lock()
+		l.held = false
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// recordingMailer mencatat penerima email
+type recordingMailer struct {
+	to []string
+}
+
+func (m *recordingMailer) Send(to, subject, body string) error {
+	m.to = append(m.to, to)
+	return nil
+}
+
+func newTestEscalation(t *testing.T, reviews ...repository.OverdueReview) (*service.ReviewEscalationScheduler, *fakeReviewEscalations, *fakeAdvisoryLock, *fakeClock, *recordingMailer) {
+	repo := &fakeReviewEscalations{reviews: reviews, escalatedTo: map[uuid.UUID]*uuid.UUID{}}
+	lock := &fakeAdvisoryLock{}
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)}
+	mailer := &recordingMailer{}
+
+	service.SetMailer(mailer)
+	t.Cleanup(func() { service.SetMailer(config.LogMailer{}) })
+
+	scheduler := service.NewReviewEscalationScheduler(repo, lock, clock, service.ReviewEscalationPolicy{
+		DefaultEscalationEmail: "kadep@example.com",
+		BatchSize:              100,
+	})
+	return scheduler, repo, lock, clock, mailer
+}
+
+// TestReviewEscalationScheduler_EscalatesOverdueOnce tests that an overdue advisor review is reassigned and notified exactly once
+func TestReviewEscalationScheduler_EscalatesOverdueOnce(t *testing.T) {
+	advisorID, backupID := uuid.New(), uuid.New()
+	submittedAt := time.Date(2025, 2, 25, 8, 0, 0, 0, time.UTC)
+	review := repository.OverdueReview{
+		ReferenceID:         uuid.New(),
+		MongoAchievementID:  "507f1f77bcf86cd799439011",
+		StudentNumber:       "2021001",
+		AdvisorID:           &advisorID,
+		SubmittedAt:         submittedAt,
+		ReviewDueAt:         submittedAt.AddDate(0, 0, 7),
+		AdvisorOnlyStage:    true,
+		EscalationEmail:     stringPtr("kaprodi@example.com"),
+		BackupReviewerID:    &backupID,
+		BackupReviewerEmail: stringPtr("backup@example.com"),
+	}
+	scheduler, repo, _, clock, mailer := newTestEscalation(t, review)
+
+	// Belum melewati batas waktu
+	n, err := scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	clock.Advance(4 * 24 * time.Hour)
+	n, err = scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, &backupID, repo.escalatedTo[review.ReferenceID])
+	assert.Equal(t, []string{"kaprodi@example.com", "backup@example.com"}, mailer.to)
+
+	// Putaran berikutnya tidak mengeskalasi ulang
+	clock.Advance(24 * time.Hour)
+	n, err = scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, mailer.to, 2)
+}
+
+// TestReviewEscalationScheduler_EscalatesEachStage tests that every approval stage gets its own deadline and escalation
+func TestReviewEscalationScheduler_EscalatesEachStage(t *testing.T) {
+	advisorID, backupID := uuid.New(), uuid.New()
+	submittedAt := time.Date(2025, 2, 20, 8, 0, 0, 0, time.UTC)
+	review := repository.OverdueReview{
+		ReferenceID:         uuid.New(),
+		StudentNumber:       "2021001",
+		AdvisorID:           &advisorID,
+		SubmittedAt:         submittedAt,
+		CurrentStage:        1,
+		ReviewDueAt:         submittedAt.AddDate(0, 0, 7),
+		AdvisorOnlyStage:    true,
+		BackupReviewerID:    &backupID,
+		BackupReviewerEmail: stringPtr("backup@example.com"),
+	}
+	scheduler, repo, _, clock, mailer := newTestEscalation(t, review)
+
+	// Tahap dosen wali terlambat dan dialihkan ke dosen pengganti
+	n, err := scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, &backupID, repo.escalatedTo[review.ReferenceID])
+
+	// Dosen pengganti menyetujui tahap 1: tahap 2 mulai dengan batas waktu baru
+	escalatedAt := clock.Now()
+	ref := &model.AchievementReferences{
+		CurrentStage: 2,
+		ReviewDueAt:  &review.ReviewDueAt,
+		EscalatedAt:  &escalatedAt,
+		EscalatedTo:  &backupID,
+	}
+	service.StartReviewSLA(ref, 7, clock.Now())
+	assert.Nil(t, ref.EscalatedAt)
+	assert.Nil(t, ref.EscalatedTo)
+	repo.advanceStage(review.ReferenceID, ref, false)
+
+	// Tahap 2 belum terlambat tepat setelah dimulai
+	n, err = scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	clock.Advance(8 * 24 * time.Hour)
+	n, err = scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Nil(t, repo.escalatedTo[review.ReferenceID])
+	assert.Equal(t, []string{"kadep@example.com", "backup@example.com", "kadep@example.com"}, mailer.to)
+}
+
+// TestReviewEscalationScheduler_NotifyOnlyForLaterStage tests that a non-advisor stage is not reassigned and uses the default recipient
+func TestReviewEscalationScheduler_NotifyOnlyForLaterStage(t *testing.T) {
+	backupID := uuid.New()
+	review := repository.OverdueReview{
+		ReferenceID:      uuid.New(),
+		SubmittedAt:      time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC),
+		ReviewDueAt:      time.Date(2025, 2, 8, 8, 0, 0, 0, time.UTC),
+		AdvisorOnlyStage: false,
+		BackupReviewerID: &backupID,
+	}
+	scheduler, repo, _, _, mailer := newTestEscalation(t, review)
+
+	n, err := scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Nil(t, repo.escalatedTo[review.ReferenceID])
+	assert.Equal(t, []string{"kadep@example.com"}, mailer.to)
+}
+
+// TestReviewEscalationScheduler_SkipsWhenLockHeld tests that a replica without the advisory lock skips the run
+func TestReviewEscalationScheduler_SkipsWhenLockHeld(t *testing.T) {
+	review := repository.OverdueReview{
+		ReferenceID: uuid.New(),
+		SubmittedAt: time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC),
+		ReviewDueAt: time.Date(2025, 2, 8, 8, 0, 0, 0, time.UTC),
+	}
+	scheduler, repo, lock, _, mailer := newTestEscalation(t, review)
+
+	unlock, acquired, _ := lock.TryLock()
+	assert.True(t, acquired)
+
+	n, err := scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, repo.escalatedTo)
+	assert.Empty(t, mailer.to)
+
+	unlock()
+	n, err = scheduler.RunOnce()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// TestReviewSLAProgress tests days waiting and the overdue flag
+func TestReviewSLAProgress(t *testing.T) {
+	submittedAt := time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC)
+	dueAt := submittedAt.AddDate(0, 0, 7)
+	ref := &model.AchievementReferences{
+		Status:      model.AchievementStatusSubmitted,
+		SubmittedAt: &submittedAt,
+		ReviewDueAt: &dueAt,
+	}
+
+	days, overdue := service.ReviewSLAProgress(ref, submittedAt.Add(3*24*time.Hour+time.Hour))
+	assert.Equal(t, 3, *days)
+	assert.False(t, overdue)
+
+	days, overdue = service.ReviewSLAProgress(ref, submittedAt.AddDate(0, 0, 9))
+	assert.Equal(t, 9, *days)
+	assert.True(t, overdue)
+
+	ref.Status = model.AchievementStatusVerified
+	days, overdue = service.ReviewSLAProgress(ref, submittedAt.AddDate(0, 0, 9))
+	assert.Nil(t, days)
+	assert.False(t, overdue)
+}
+
+// TestCreateReviewSLAService_InvalidInput tests field validation of review SLA rules
+func TestCreateReviewSLAService_InvalidInput(t *testing.T) {
+	app := fiber.New()
+	app.Post("/review-slas", service.CreateReviewSLAService)
+
+	status := postJSON(t, app, "/review-slas", map[string]interface{}{
+		"achievement_type": "competition",
+		"due_days":         0,
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status = postJSON(t, app, "/review-slas", map[string]interface{}{
+		"achievement_type":  "academic",
+		"competition_level": "national",
+		"due_days":          7,
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status = postJSON(t, app, "/review-slas", map[string]interface{}{
+		"achievement_type": "competition",
+		"due_days":         7,
+		"escalation_email": "bukan-email",
+	})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// TestCreateReviewSLAService_MissingUserID tests that a valid rule without an authenticated user id is rejected
+func TestCreateReviewSLAService_MissingUserID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/review-slas", service.CreateReviewSLAService)
+
+	status := postJSON(t, app, "/review-slas", map[string]interface{}{
+		"achievement_type": "competition",
+		"due_days":         5,
+	})
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateRoleService_MissingName tests create role without name
+func TestCreateRoleService_MissingName(t *testing.T) {
+	app := fiber.New()
+	app.Post("/roles", service.CreateRoleService)
+
+	body, _ := json.Marshal(map[string]string{"description": "Tanpa nama"})
+	req := httptest.NewRequest("POST", "/roles", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestDeleteRoleService_InvalidID tests delete role with invalid UUID
+func TestDeleteRoleService_InvalidID(t *testing.T) {
+	app := fiber.New()
+	app.Delete("/roles/:id", service.DeleteRoleService)
+
+	req := httptest.NewRequest("DELETE", "/roles/invalid-uuid", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestAttachRolePermissionService_InvalidPermissionID tests attach with invalid permission UUID
+func TestAttachRolePermissionService_InvalidPermissionID(t *testing.T) {
+	app := fiber.New()
+	app.Post("/roles/:id/permissions", service.AttachRolePermissionService)
+
+	body, _ := json.Marshal(map[string]string{"permission_id": "bukan-uuid"})
+	req := httptest.NewRequest("POST", "/roles/550e8400-e29b-41d4-a716-446655440000/permissions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestDetachRolePermissionService_InvalidID tests detach with invalid role UUID
+func TestDetachRolePermissionService_InvalidID(t *testing.T) {
+	app := fiber.New()
+	app.Delete("/roles/:id/permissions/:permissionId", service.DetachRolePermissionService)
+
+	req := httptest.NewRequest("DELETE", "/roles/invalid-uuid/permissions/550e8400-e29b-41d4-a716-446655440000", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestCreatePermissionService_MissingFields tests create permission without resource and action
+func TestCreatePermissionService_MissingFields(t *testing.T) {
+	app := fiber.New()
+	app.Post("/permissions", service.CreatePermissionService)
+
+	body, _ := json.Marshal(map[string]string{"name": "export_reports"})
+	req := httptest.NewRequest("POST", "/permissions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestCreatePermissionService_NameWithSpaces tests create permission with spaces in name
+func TestCreatePermissionService_NameWithSpaces(t *testing.T) {
+	app := fiber.New()
+	app.Post("/permissions", service.CreatePermissionService)
+
+	body, _ := json.Marshal(map[string]string{
+		"name":     "export reports",
+		"resource": "reports",
+		"action":   "export",
+	})
+	req := httptest.NewRequest("POST", "/permissions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"GOLANG/Domain/service"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRevokeMySessionService_InvalidSessionID tests revoke session with invalid session ID
+func TestRevokeMySessionService_InvalidSessionID(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		return c.Next()
+	})
+
+	app.Delete("/sessions/:id", service.RevokeMySessionService)
+
+	req := httptest.NewRequest("DELETE", "/sessions/invalid-id", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestRevokeOtherSessionsService_TokenWithoutSession tests revoke others with a token not bound to a session
+func TestRevokeOtherSessionsService_TokenWithoutSession(t *testing.T) {
+	app := fiber.New()
+
+	// Mock JWT middleware (token lama tanpa claim sid)
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("id", "550e8400-e29b-41d4-a716-446655440000")
+		c.Locals("session_id", "")
+		return c.Next()
+	})
+
+	app.Delete("/sessions", service.RevokeOtherSessionsService)
+
+	req := httptest.NewRequest("DELETE", "/sessions", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestForceLogoutUserService_InvalidUserID tests force logout with invalid user ID
+func TestForceLogoutUserService_InvalidUserID(t *testing.T) {
+	app := fiber.New()
+	app.Delete("/users/:id/sessions", service.ForceLogoutUserService)
+
+	req := httptest.NewRequest("DELETE", "/users/invalid-id/sessions", nil)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
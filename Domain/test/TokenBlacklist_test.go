@@ -0,0 +1,56 @@
+package test
+
+import (
+	"GOLANG/Domain/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInMemoryTokenBlacklist_AddAndExists tests adding and checking tokens
+func TestInMemoryTokenBlacklist_AddAndExists(t *testing.T) {
+	blacklist := repository.NewInMemoryTokenBlacklist()
+
+	exists, err := blacklist.Exists("token-a")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, blacklist.Add("token-a", time.Now().Add(time.Hour)))
+
+	exists, err = blacklist.Exists("token-a")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, blacklist.Remove("token-a"))
+
+	exists, err = blacklist.Exists("token-a")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// TestInMemoryTokenBlacklist_ExpiredToken tests that expired tokens are ignored and cleaned up
+func TestInMemoryTokenBlacklist_ExpiredToken(t *testing.T) {
+	blacklist := repository.NewInMemoryTokenBlacklist()
+
+	assert.NoError(t, blacklist.Add("token-expired", time.Now().Add(-time.Minute)))
+
+	exists, err := blacklist.Exists("token-expired")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, blacklist.Cleanup())
+}
+
+// TestNewTokenBlacklistRepository tests driver selection from configuration
+func TestNewTokenBlacklistRepository(t *testing.T) {
+	repo, err := repository.NewTokenBlacklistRepository("memory", nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &repository.InMemoryTokenBlacklist{}, repo)
+
+	_, err = repository.NewTokenBlacklistRepository("postgres", nil)
+	assert.Error(t, err)
+
+	_, err = repository.NewTokenBlacklistRepository("redis", nil)
+	assert.Error(t, err)
+}
@@ -1,11 +1,15 @@
 package test
 
 import (
+	"GOLANG/Domain/config"
 	"GOLANG/Domain/middleware"
+	"GOLANG/Domain/repository"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,7 +17,7 @@ import (
 func TestJWTAuth_MissingToken(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.JWTAuth())
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendString("Protected route")
 	})
@@ -29,7 +33,7 @@ func TestJWTAuth_MissingToken(t *testing.T) {
 func TestJWTAuth_InvalidTokenFormat(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.JWTAuth())
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendString("Protected route")
 	})
@@ -47,7 +51,7 @@ func TestJWTAuth_InvalidTokenFormat(t *testing.T) {
 func TestJWTAuth_BearerWithoutToken(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.JWTAuth())
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendString("Protected route")
 	})
@@ -65,7 +69,7 @@ func TestJWTAuth_BearerWithoutToken(t *testing.T) {
 func TestJWTAuth_InvalidToken(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.JWTAuth())
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendString("Protected route")
 	})
@@ -79,5 +83,85 @@ func TestJWTAuth_InvalidToken(t *testing.T) {
 	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
 }
 
-// Note: Test for valid token requires actual JWT generation
-// which will be tested in integration tests
+// TestJWTAuth_BlacklistedToken tests JWT middleware with a logged-out token
+func TestJWTAuth_BlacklistedToken(t *testing.T) {
+	claims := jwt.MapClaims{
+		"id":       "550e8400-e29b-41d4-a716-446655440000",
+		"username": "testuser",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.GetJWTSecret()))
+	assert.NoError(t, err)
+
+	blacklist := repository.NewInMemoryTokenBlacklist()
+
+	app := fiber.New()
+	app.Use(middleware.JWTAuth(blacklist))
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		return c.SendString("Protected route")
+	})
+
+	// Token valid sebelum logout
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// Token ditolak setelah masuk blacklist
+	assert.NoError(t, blacklist.Add(tokenString, time.Now().Add(time.Hour)))
+
+	req = httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestJWTAuth_ChallengeTokenRejected tests that a 2FA challenge token cannot be used as access token
+func TestJWTAuth_ChallengeTokenRejected(t *testing.T) {
+	claims := jwt.MapClaims{
+		"id":  "550e8400-e29b-41d4-a716-446655440000",
+		"typ": "2fa_challenge",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.GetJWTSecret()))
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		return c.SendString("Protected route")
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestJWTAuth_ImpersonationTokenWithoutActor tests that an impersonation token missing its act claim is rejected
+func TestJWTAuth_ImpersonationTokenWithoutActor(t *testing.T) {
+	claims := jwt.MapClaims{
+		"id":  "550e8400-e29b-41d4-a716-446655440000",
+		"imp": "not-a-uuid",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.GetJWTSecret()))
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(middleware.JWTAuth(repository.NewInMemoryTokenBlacklist()))
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		return c.SendString("Protected route")
+	})
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"GOLANG/Domain/service"
+	"bytes"
+	"encoding/base32"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret secret SHA1 dari test vector RFC 6238 ("12345678901234567890")
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// TestTOTPCode_RFC6238Vectors tests TOTP against the RFC 6238 SHA1 test vectors (6 digit)
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := service.TOTPCode(rfc6238Secret, time.Unix(unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "unix time %d", unix)
+	}
+}
+
+// TestVerifyTOTP_SkewAndReplay tests clock skew tolerance and rejection of reused steps
+func TestVerifyTOTP_SkewAndReplay(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	code, err := service.TOTPCode(rfc6238Secret, now.Add(-30*time.Second))
+	assert.NoError(t, err)
+
+	// Kode dari step sebelumnya masih diterima
+	step, ok := service.VerifyTOTP(rfc6238Secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, service.TOTPStep(now)-1, step)
+
+	// Step yang sudah dipakai ditolak
+	_, ok = service.VerifyTOTP(rfc6238Secret, code, now, step)
+	assert.False(t, ok)
+
+	// Kode yang terlalu lama ditolak
+	old, _ := service.TOTPCode(rfc6238Secret, now.Add(-2*time.Minute))
+	_, ok = service.VerifyTOTP(rfc6238Secret, old, now, 0)
+	assert.False(t, ok)
+}
+
+// TestGenerateTOTPSecret tests generated secret and otpauth URI
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := service.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := service.TOTPURI("Kampus", "budi", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Kampus:budi?"))
+	assert.Contains(t, uri, "secret="+secret)
+}
+
+// TestLoginTwoFactorService_MissingFields tests 2FA login without challenge or code
+func TestLoginTwoFactorService_MissingFields(t *testing.T) {
+	app := fiber.New()
+	app.Post("/login/2fa", service.LoginTwoFactorService)
+
+	body, _ := json.Marshal(map[string]string{"code": "123456"})
+	req := httptest.NewRequest("POST", "/login/2fa", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// TestLoginTwoFactorService_InvalidChallenge tests 2FA login with an invalid challenge token
+func TestLoginTwoFactorService_InvalidChallenge(t *testing.T) {
+	app := fiber.New()
+	app.Post("/login/2fa", service.LoginTwoFactorService)
+
+	body, _ := json.Marshal(map[string]string{
+		"challenge_token": "invalid.jwt.token",
+		"code":            "123456",
+	})
+	req := httptest.NewRequest("POST", "/login/2fa", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// TestEnableTwoFactorService_MissingCode tests enabling 2FA without code
+func TestEnableTwoFactorService_MissingCode(t *testing.T) {
+	app := fiber.New()
+	app.Post("/2fa/enable", service.EnableTwoFactorService)
+
+	req := httptest.NewRequest("POST", "/2fa/enable", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"GOLANG/Domain/config"
+	"GOLANG/Domain/service"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const signedAttachmentPath = "/api/v1/files/achievements/507f1f77bcf86cd799439011/attachments/att-1"
+
+// signedQuery memecah URL hasil Sign menjadi expires dan signature
+func signedQuery(t *testing.T, signed string) url.Values {
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	return parsed.Query()
+}
+
+// TestURLSigner_SignAndVerify tests that a signed URL verifies until it expires
+func TestURLSigner_SignAndVerify(t *testing.T) {
+	signer := config.NewURLSigner([]byte("test-secret"))
+	now := time.Date(2024, 12, 4, 10, 0, 0, 0, time.UTC)
+
+	query := signedQuery(t, signer.Sign(signedAttachmentPath, now.Add(15*time.Minute)))
+
+	assert.NoError(t, signer.Verify(signedAttachmentPath, query.Get("expires"), query.Get("signature"), now))
+	assert.ErrorIs(t, signer.Verify(signedAttachmentPath, query.Get("expires"), query.Get("signature"), now.Add(16*time.Minute)), config.ErrURLExpired)
+}
+
+// TestURLSigner_RejectsTampering tests that changing the path, expiry or secret invalidates the signature
+func TestURLSigner_RejectsTampering(t *testing.T) {
+	signer := config.NewURLSigner([]byte("test-secret"))
+	now := time.Date(2024, 12, 4, 10, 0, 0, 0, time.UTC)
+
+	query := signedQuery(t, signer.Sign(signedAttachmentPath, now.Add(15*time.Minute)))
+	expires, signature := query.Get("expires"), query.Get("signature")
+
+	otherPath := "/api/v1/files/achievements/507f1f77bcf86cd799439011/attachments/att-2"
+	assert.ErrorIs(t, signer.Verify(otherPath, expires, signature, now), config.ErrURLSignatureInvalid)
+
+	extended := query.Get("expires") + "0"
+	assert.ErrorIs(t, signer.Verify(signedAttachmentPath, extended, signature, now), config.ErrURLSignatureInvalid)
+
+	otherSigner := config.NewURLSigner([]byte("other-secret"))
+	assert.ErrorIs(t, otherSigner.Verify(signedAttachmentPath, expires, signature, now), config.ErrURLSignatureInvalid)
+
+	assert.ErrorIs(t, signer.Verify(signedAttachmentPath, expires, "", now), config.ErrURLSignatureInvalid)
+}
+
+// TestLoadURLSigner_RequiresSecretInProduction tests that the default secret is refused outside development
+func TestLoadURLSigner_RequiresSecretInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("ATTACHMENT_URL_SECRET", "")
+
+	_, err := config.LoadURLSigner()
+	assert.Error(t, err)
+
+	t.Setenv("ATTACHMENT_URL_SECRET", "a-real-secret")
+	_, err = config.LoadURLSigner()
+	assert.NoError(t, err)
+}
+
+// TestDownloadSignedAttachmentService_SignatureAndExpiry tests the public download route checks
+func TestDownloadSignedAttachmentService_SignatureAndExpiry(t *testing.T) {
+	signer := config.NewURLSigner([]byte("test-secret"))
+	service.SetURLSigner(signer)
+	defer service.SetURLSigner(nil)
+
+	app := fiber.New()
+	app.Get("/api/v1/files/achievements/:id/attachments/:attachmentId", service.DownloadSignedAttachmentService)
+
+	// Signature untuk lampiran lain
+	forged := signer.Sign("/api/v1/files/achievements/507f1f77bcf86cd799439011/attachments/att-2", time.Now().Add(time.Minute))
+	forgedQuery := signedQuery(t, forged)
+	resp, err := app.Test(httptest.NewRequest("GET", signedAttachmentPath+"?"+forgedQuery.Encode(), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	// Link yang sudah lewat masa berlakunya
+	expired := signer.Sign(signedAttachmentPath, time.Now().Add(-time.Minute))
+	resp, err = app.Test(httptest.NewRequest("GET", expired, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusGone, resp.StatusCode)
+}
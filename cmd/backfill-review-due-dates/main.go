@@ -0,0 +1,28 @@
+// Command backfill-review-due-dates mengisi review_due_at prestasi yang sudah submitted sebelum
+// migration 019 dengan submitted_at + REVIEW_SLA_DAYS, sama dengan batas waktu bawaan submit baru.
+//
+// Jalankan sekali setelah migration 019; aman diulang karena hanya mengisi yang masih kosong:
+//
+//	go run ./cmd/backfill-review-due-dates
+package main
+
+import (
+	"GOLANG/Domain/config"
+	"GOLANG/Domain/repository"
+	"log"
+)
+
+func main() {
+	config.LoadEnv()
+
+	db := config.ConnectDB()
+	if err := db.Ping(); err != nil {
+		log.Fatal("Koneksi PostgreSQL gagal: ", err)
+	}
+
+	n, err := repository.NewPostgresReviewEscalations(db).BackfillReviewDueDates(config.GetReviewSLADays())
+	if err != nil {
+		log.Fatal("Gagal mengisi batas waktu review: ", err)
+	}
+	log.Printf("Batas waktu review diisi untuk %d achievement", n)
+}
@@ -0,0 +1,39 @@
+// Command normalize-taxonomy menormalisasi details.competitionLevel dan details.medalType
+// di dokumen achievement MongoDB yang sudah tersimpan ke nilai baku taxonomy.
+//
+// Jalankan sekali setelah deploy, sebaiknya dengan -dry-run terlebih dahulu:
+//
+//	go run ./cmd/normalize-taxonomy -dry-run
+//	go run ./cmd/normalize-taxonomy
+package main
+
+import (
+	"GOLANG/Domain/config"
+	"GOLANG/Domain/service"
+	"flag"
+	"log"
+	"strings"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "tampilkan perubahan tanpa menulis ke MongoDB")
+	flag.Parse()
+
+	config.LoadEnv()
+	config.ConnectMongoDB()
+
+	report, err := service.NormalizeStoredAchievementTaxonomy(*dryRun)
+	if err != nil {
+		log.Fatal("Normalisasi taxonomy gagal: ", err)
+	}
+
+	verb := "diperbarui"
+	if *dryRun {
+		verb = "akan diperbarui"
+	}
+	log.Printf("%d achievement diperiksa, %d %s", report.Scanned, report.Updated, verb)
+
+	for field, ids := range report.Unknown {
+		log.Printf("%d achievement dengan %s tidak dikenal (perbaiki manual): %s", len(ids), field, strings.Join(ids, ", "))
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	. "GOLANG/Domain/config"
+	"GOLANG/Domain/repository"
 	"GOLANG/Domain/route"
+	"GOLANG/Domain/service"
 	"log"
 
 	_ "GOLANG/docs" // Import generated swagger docs
@@ -33,6 +35,13 @@ import (
 func main() {
 	LoadEnv()
 
+	// Kunci JWT (RS256/EdDSA dari JWT_KEYS_DIR, atau HS256 dengan JWT_SECRET)
+	jwtKeys, err := LoadJWTKeySet()
+	if err != nil {
+		log.Fatal("Konfigurasi JWT tidak valid: ", err)
+	}
+	SetJWTKeySet(jwtKeys)
+
 	// Connect PostgreSQL
 	db := ConnectDB()
 	if err := db.Ping(); err != nil {
@@ -42,17 +51,71 @@ func main() {
 	// Connect MongoDB
 	ConnectMongoDB()
 
+	// Token blacklist (postgres/memory) sesuai TOKEN_BLACKLIST_DRIVER
+	blacklist, err := repository.NewTokenBlacklistRepository(GetTokenBlacklistDriver(), db)
+	if err != nil {
+		log.Fatal("Token blacklist gagal dibuat: ", err)
+	}
+	stopCleanup := repository.StartTokenBlacklistCleanup(blacklist, GetTokenBlacklistCleanupInterval())
+	defer stopCleanup()
+
+	// Throttling login (postgres/memory) sesuai LOGIN_THROTTLE_DRIVER
+	loginAttempts, err := repository.NewLoginAttemptRepository(GetLoginThrottleDriver(), db)
+	if err != nil {
+		log.Fatal("Login throttle gagal dibuat: ", err)
+	}
+	service.SetLoginThrottler(service.NewLoginThrottler(loginAttempts, service.SystemClock{}, service.DefaultLoginThrottlePolicy()))
+
+	// Cache role -> permission untuk JWTAuth
+	repository.SetPermissionCache(repository.NewPermissionCache(repository.PostgresPermissionLoader{}, GetPermissionCacheCheckInterval()))
+
+	// Mailer (log/smtp) sesuai MAIL_DRIVER
+	mailer, err := NewMailer()
+	if err != nil {
+		log.Fatal("Mailer gagal dibuat: ", err)
+	}
+	service.SetMailer(mailer)
+
+	// Storage lampiran (local/s3) sesuai STORAGE_DRIVER
+	storage, err := NewFileStorage()
+	if err != nil {
+		log.Fatal("File storage gagal dibuat: ", err)
+	}
+	service.SetFileStorage(storage)
+
+	// Signer link download lampiran (ATTACHMENT_URL_SECRET)
+	signer, err := LoadURLSigner()
+	if err != nil {
+		log.Fatal("Konfigurasi link download tidak valid: ", err)
+	}
+	service.SetURLSigner(signer)
+
+	// Eskalasi review yang melewati SLA; advisory lock mencegah putaran bersamaan antar replica
+	escalation := service.NewReviewEscalationScheduler(
+		repository.NewPostgresReviewEscalations(db),
+		repository.NewPostgresAdvisoryLock(db, repository.ReviewEscalationLockKey),
+		service.SystemClock{},
+		service.DefaultReviewEscalationPolicy(),
+	)
+	stopEscalation := escalation.Start(GetReviewEscalationInterval())
+	defer stopEscalation()
+
 	app := route.NewApp(db)
 
 	// Swagger documentation
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
 	// Register routes
-	route.AuthRoute(app)
-	route.UserRoute(app)
-	route.AchievementRoute(app)
-	route.StudentRoute(app)
-	route.ReportRoute(app)
+	route.AuthRoute(app, blacklist)
+	route.UserRoute(app, blacklist)
+	route.AchievementRoute(app, blacklist)
+	route.StudentRoute(app, blacklist)
+	route.ReportRoute(app, blacklist)
+	route.RoleRoute(app, blacklist)
+	route.PointRuleRoute(app, blacklist)
+	route.AchievementTypeRoute(app, blacklist)
+	route.ApprovalWorkflowRoute(app, blacklist)
+	route.ReviewSLARoute(app, blacklist)
 
 	port := "4000"
 	log.Printf("Server running on port %s", port)
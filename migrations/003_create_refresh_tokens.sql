@@ -0,0 +1,19 @@
+-- Refresh token untuk memperpanjang sesi tanpa login ulang.
+-- Token disimpan dalam bentuk hash (SHA-256), bukan token mentah.
+-- Setiap login membuat "family" baru; rotasi token tetap berada di family yang sama.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          UUID PRIMARY KEY,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id   UUID NOT NULL,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    device      VARCHAR(255) NOT NULL DEFAULT '',
+    user_agent  TEXT NOT NULL DEFAULT '',
+    ip_address  VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at  TIMESTAMP NOT NULL,
+    revoked_at  TIMESTAMP NULL,
+    replaced_by UUID NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
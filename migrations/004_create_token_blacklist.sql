@@ -0,0 +1,10 @@
+-- Blacklist access token yang sudah logout.
+-- Yang disimpan hanya hash SHA-256 dari token, bukan token mentah.
+-- Baris yang sudah expired dibersihkan secara berkala oleh aplikasi.
+CREATE TABLE IF NOT EXISTS token_blacklist (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_blacklist_expires_at ON token_blacklist(expires_at);
@@ -0,0 +1,16 @@
+-- Sesi login per perangkat. Setiap login membuat satu sesi;
+-- refresh token dari login tersebut memakai id sesi sebagai family_id.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id           UUID PRIMARY KEY,
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device       VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address   VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent   TEXT NOT NULL DEFAULT '',
+    issued_at    TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at   TIMESTAMP NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
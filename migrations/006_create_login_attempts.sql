@@ -0,0 +1,8 @@
+-- Penghitung percobaan login gagal untuk throttling dan lockout.
+-- key berbentuk "account:<email/username>" atau "ip:<alamat ip>".
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key             VARCHAR(320) PRIMARY KEY,
+    failures        INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until    TIMESTAMP NULL
+);
@@ -0,0 +1,23 @@
+-- TOTP two-factor authentication (RFC 6238).
+-- enabled_at NULL berarti enrollment belum dikonfirmasi dengan kode pertama.
+-- last_used_step mencegah kode TOTP yang sama dipakai dua kali.
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id        UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret         VARCHAR(64) NOT NULL,
+    enabled_at     TIMESTAMP NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Recovery code sekali pakai; hanya hash SHA-256 yang disimpan
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
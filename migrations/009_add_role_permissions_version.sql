@@ -0,0 +1,36 @@
+-- Versi permission per role. Naik otomatis setiap role_permissions berubah
+-- (termasuk perubahan lewat SQL langsung), sehingga cache permission di
+-- JWTAuth tahu kapan harus dimuat ulang. Access token membawa claim "pv".
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS permissions_version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_role_permissions_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE roles SET permissions_version = permissions_version + 1 WHERE id = NEW.role_id;
+    END IF;
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE roles SET permissions_version = permissions_version + 1 WHERE id = OLD.role_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_role_permissions_version ON role_permissions;
+CREATE TRIGGER trg_role_permissions_version
+    AFTER INSERT OR UPDATE OR DELETE ON role_permissions
+    FOR EACH ROW EXECUTE FUNCTION bump_role_permissions_version();
+
+-- Rename permission mengubah isi permission semua role yang memilikinya
+CREATE OR REPLACE FUNCTION bump_permission_roles_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE roles SET permissions_version = permissions_version + 1
+    WHERE id IN (SELECT role_id FROM role_permissions WHERE permission_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_permissions_name_version ON permissions;
+CREATE TRIGGER trg_permissions_name_version
+    AFTER UPDATE OF name ON permissions
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION bump_permission_roles_version();
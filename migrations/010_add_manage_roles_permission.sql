@@ -0,0 +1,24 @@
+-- Permission untuk API administrasi role dan permission (/api/v1/roles, /api/v1/permissions).
+-- Diberikan ke semua role yang saat ini memiliki manage_users (Admin). Permission admin baru di
+-- migration berikutnya diberikan lewat grant_permission_to_admin_roles agar Admin langsung bisa memakainya.
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'manage_roles', 'roles', 'manage', 'Kelola role dan permission'
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'manage_roles');
+
+-- Memberikan permission ke semua role yang memiliki manage_users (idempotent)
+CREATE OR REPLACE FUNCTION grant_permission_to_admin_roles(permission_name TEXT) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO role_permissions (role_id, permission_id)
+    SELECT rp.role_id, p.id
+    FROM role_permissions rp
+    JOIN permissions mu ON mu.id = rp.permission_id AND mu.name = 'manage_users'
+    CROSS JOIN permissions p
+    WHERE p.name = permission_name
+      AND NOT EXISTS (
+          SELECT 1 FROM role_permissions x
+          WHERE x.role_id = rp.role_id AND x.permission_id = p.id
+      );
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT grant_permission_to_admin_roles('manage_roles');
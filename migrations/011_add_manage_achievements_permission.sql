@@ -0,0 +1,7 @@
+-- Permission admin untuk policy achievement: pemiliknya boleh membaca semua achievement
+-- tanpa cek kepemilikan/dosen wali.
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'manage_achievements', 'achievements', 'manage', 'Akses semua achievement (admin)'
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'manage_achievements');
+
+SELECT grant_permission_to_admin_roles('manage_achievements');
@@ -0,0 +1,39 @@
+-- Impersonation ("login as") oleh admin. Tidak memakai foreign key ke users agar
+-- jejak audit tetap ada walaupun user dihapus; username disimpan sebagai snapshot.
+CREATE TABLE IF NOT EXISTS impersonations (
+    id                    UUID PRIMARY KEY,
+    impersonator_id       UUID NOT NULL,
+    impersonator_username VARCHAR(255) NOT NULL,
+    target_user_id        UUID NOT NULL,
+    target_username       VARCHAR(255) NOT NULL,
+    reason                TEXT NOT NULL,
+    ip_address            VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent            TEXT NOT NULL DEFAULT '',
+    started_at            TIMESTAMP NOT NULL,
+    expires_at            TIMESTAMP NOT NULL,
+    ended_at              TIMESTAMP NULL,
+    end_reason            VARCHAR(32) NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonations_impersonator_id ON impersonations(impersonator_id);
+CREATE INDEX IF NOT EXISTS idx_impersonations_target_user_id ON impersonations(target_user_id);
+
+-- Jejak kejadian selama impersonation: start, end, dan aksi tulis yang diblokir
+CREATE TABLE IF NOT EXISTS impersonation_events (
+    id               UUID PRIMARY KEY,
+    impersonation_id UUID NOT NULL REFERENCES impersonations(id),
+    event            VARCHAR(32) NOT NULL,
+    method           VARCHAR(16) NOT NULL DEFAULT '',
+    path             TEXT NOT NULL DEFAULT '',
+    ip_address       VARCHAR(64) NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_events_impersonation_id ON impersonation_events(impersonation_id);
+
+-- Permission khusus untuk memulai impersonation
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'impersonate_users', 'users', 'impersonate', 'Login sebagai user lain untuk support'
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'impersonate_users');
+
+SELECT grant_permission_to_admin_roles('impersonate_users');
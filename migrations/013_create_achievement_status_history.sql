@@ -0,0 +1,24 @@
+-- Riwayat perubahan status prestasi (draft -> submitted -> verified/rejected -> ...)
+-- Ditulis dalam transaksi yang sama dengan update achievement_references.
+-- actor_id tanpa foreign key ke users agar jejak tetap ada walaupun user dihapus.
+CREATE TABLE IF NOT EXISTS achievement_status_history (
+    id                       UUID PRIMARY KEY,
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    from_status              VARCHAR(32) NULL,
+    to_status                VARCHAR(32) NOT NULL,
+    actor_id                 UUID NULL,
+    actor_role               VARCHAR(100) NOT NULL DEFAULT '',
+    note                     TEXT NULL,
+    created_at               TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_status_history_reference
+    ON achievement_status_history(achievement_reference_id, created_at);
+
+-- Prestasi yang sudah ada sebelum tabel ini dibuat: catat status terakhirnya sebagai titik awal
+INSERT INTO achievement_status_history (id, achievement_reference_id, from_status, to_status, actor_role, note, created_at)
+SELECT gen_random_uuid(), ar.id, NULL, ar.status, 'system', 'Status saat riwayat mulai dicatat', ar.updated_at
+FROM achievement_references ar
+WHERE NOT EXISTS (
+    SELECT 1 FROM achievement_status_history h WHERE h.achievement_reference_id = ar.id
+);
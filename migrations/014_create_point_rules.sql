@@ -0,0 +1,35 @@
+-- Aturan poin SKPI yang versioned. Satu versi = satu rule set yang tidak bisa diubah setelah dibuat;
+-- perubahan aturan dilakukan dengan membuat versi baru lalu mengaktifkannya. Hanya satu versi aktif.
+CREATE TABLE IF NOT EXISTS point_rule_sets (
+    id           UUID PRIMARY KEY,
+    version      INT NOT NULL UNIQUE,
+    description  TEXT NOT NULL DEFAULT '',
+    is_active    BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by   UUID NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    activated_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_point_rule_sets_single_active
+    ON point_rule_sets(is_active) WHERE is_active;
+
+-- Satu rule: jika tipe prestasi sama dan semua conditions cocok dengan details, points ditambahkan.
+-- conditions berupa objek JSON field -> nilai, contoh {"competitionLevel": "national", "rank": "1"}
+CREATE TABLE IF NOT EXISTS point_rules (
+    id               UUID PRIMARY KEY,
+    rule_set_id      UUID NOT NULL REFERENCES point_rule_sets(id) ON DELETE CASCADE,
+    achievement_type VARCHAR(50) NOT NULL,
+    conditions       JSONB NOT NULL DEFAULT '{}'::jsonb,
+    points           INT NOT NULL,
+    description      TEXT NOT NULL DEFAULT '',
+    position         INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_rules_rule_set_id ON point_rules(rule_set_id);
+
+-- Permission untuk mengelola aturan poin
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'manage_point_rules', 'point_rules', 'manage', 'Kelola aturan poin prestasi'
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'manage_point_rules');
+
+SELECT grant_permission_to_admin_roles('manage_point_rules');
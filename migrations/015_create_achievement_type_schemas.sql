@@ -0,0 +1,15 @@
+-- JSON Schema tambahan untuk customFields per tipe prestasi, dikelola admin lewat API.
+-- Tipe tanpa baris di tabel ini menerima customFields bebas.
+CREATE TABLE IF NOT EXISTS achievement_type_schemas (
+    achievement_type     VARCHAR(50) PRIMARY KEY,
+    custom_fields_schema JSONB NOT NULL,
+    updated_by           UUID NULL,
+    updated_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Permission untuk mengelola schema tipe prestasi
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'manage_achievement_types', 'achievement_types', 'manage', 'Kelola schema custom field tipe prestasi'
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'manage_achievement_types');
+
+SELECT grant_permission_to_admin_roles('manage_achievement_types');
@@ -0,0 +1,27 @@
+-- Status baru revision_requested: dosen wali meminta perbaikan tanpa menolak prestasi.
+-- Jika kolom status memakai tipe enum achievement_status, nilai baru ditambahkan ke enum tersebut.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'achievement_status') THEN
+        ALTER TYPE achievement_status ADD VALUE IF NOT EXISTS 'revision_requested';
+    END IF;
+END
+$$;
+
+-- Thread diskusi per prestasi antara mahasiswa pemilik dan dosen wali.
+-- kind = 'revision_request' untuk komentar yang dibuat bersama aksi request revision.
+-- author_id tanpa foreign key ke users agar thread tetap utuh walaupun user dihapus.
+CREATE TABLE IF NOT EXISTS achievement_comments (
+    id                       UUID PRIMARY KEY,
+    achievement_reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    parent_id                UUID NULL REFERENCES achievement_comments(id) ON DELETE CASCADE,
+    author_id                UUID NULL,
+    author_role              VARCHAR(100) NOT NULL DEFAULT '',
+    kind                     VARCHAR(32) NOT NULL DEFAULT 'comment',
+    body                     TEXT NOT NULL,
+    attachment_ids           JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at               TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_comments_reference
+    ON achievement_comments(achievement_reference_id, created_at);
@@ -0,0 +1,72 @@
+-- Workflow persetujuan bertahap per tipe prestasi (dan tingkat kompetisi).
+-- competition_level NULL berarti berlaku untuk semua tingkat tipe tersebut; workflow yang
+-- paling spesifik dipakai. Tanpa workflow yang cocok, verifikasi cukup oleh dosen wali (1 tahap).
+CREATE TABLE IF NOT EXISTS approval_workflows (
+    id                UUID PRIMARY KEY,
+    achievement_type  VARCHAR(50) NOT NULL,
+    competition_level VARCHAR(32) NULL,
+    name              VARCHAR(150) NOT NULL,
+    updated_by        UUID NULL,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_workflows_scope
+    ON approval_workflows(achievement_type, COALESCE(competition_level, ''));
+
+-- Tahap berurutan (stage_order mulai dari 1). Approver tahap adalah pemilik required_permission;
+-- advisor_only membatasi tahap ke dosen wali mahasiswa pemilik prestasi.
+CREATE TABLE IF NOT EXISTS approval_workflow_stages (
+    workflow_id         UUID NOT NULL REFERENCES approval_workflows(id) ON DELETE CASCADE,
+    stage_order         INT NOT NULL,
+    name                VARCHAR(150) NOT NULL,
+    required_permission VARCHAR(100) NOT NULL,
+    advisor_only        BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (workflow_id, stage_order)
+);
+
+-- Workflow dipilih saat submit; current_stage menunjuk tahap yang sedang menunggu persetujuan
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS workflow_id UUID NULL REFERENCES approval_workflows(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS current_stage INT NOT NULL DEFAULT 1;
+
+-- Persetujuan per tahap dicatat di riwayat status dengan nomor tahapnya
+ALTER TABLE achievement_status_history
+    ADD COLUMN IF NOT EXISTS stage INT NULL;
+
+-- Permission approver tahap lanjutan (diberikan ke role lewat manajemen role) dan permission
+-- untuk mengelola workflow
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), p.name, p.resource, p.action, p.description
+FROM (VALUES
+    ('approve_student_affairs', 'achievements', 'approve_student_affairs', 'Persetujuan prestasi tahap kemahasiswaan'),
+    ('approve_vice_dean', 'achievements', 'approve_vice_dean', 'Persetujuan prestasi tahap wakil dekan'),
+    ('manage_approval_workflows', 'approval_workflows', 'manage', 'Kelola workflow persetujuan prestasi')
+) AS p(name, resource, action, description)
+WHERE NOT EXISTS (SELECT 1 FROM permissions x WHERE x.name = p.name);
+
+SELECT grant_permission_to_admin_roles('manage_approval_workflows');
+
+-- Workflow fakultas: kompetisi nasional dan internasional melalui dosen wali -> kemahasiswaan -> wakil dekan
+INSERT INTO approval_workflows (id, achievement_type, competition_level, name)
+SELECT gen_random_uuid(), 'competition', w.level, w.name
+FROM (VALUES
+    ('national', 'Kompetisi nasional'),
+    ('international', 'Kompetisi internasional')
+) AS w(level, name)
+WHERE NOT EXISTS (
+    SELECT 1 FROM approval_workflows x
+    WHERE x.achievement_type = 'competition' AND x.competition_level = w.level
+);
+
+INSERT INTO approval_workflow_stages (workflow_id, stage_order, name, required_permission, advisor_only)
+SELECT w.id, s.stage_order, s.name, s.required_permission, s.advisor_only
+FROM approval_workflows w
+CROSS JOIN (VALUES
+    (1, 'Dosen Wali', 'verify_achievements', TRUE),
+    (2, 'Kemahasiswaan', 'approve_student_affairs', FALSE),
+    (3, 'Wakil Dekan', 'approve_vice_dean', FALSE)
+) AS s(stage_order, name, required_permission, advisor_only)
+WHERE w.achievement_type = 'competition'
+  AND w.competition_level IN ('national', 'international')
+  AND NOT EXISTS (SELECT 1 FROM approval_workflow_stages x WHERE x.workflow_id = w.id);
@@ -0,0 +1,16 @@
+-- Status baru revoked: admin mencabut verifikasi prestasi yang ternyata tidak valid.
+-- Jika kolom status memakai tipe enum achievement_status, nilai baru ditambahkan ke enum tersebut.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'achievement_status') THEN
+        ALTER TYPE achievement_status ADD VALUE IF NOT EXISTS 'revoked';
+    END IF;
+END
+$$;
+
+-- Data pencabutan verifikasi; verified_at dan verified_by tetap disimpan sebagai jejak verifikasi awal.
+-- revoked_by = users.id admin yang mencabut (admin belum tentu dosen).
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS revoked_at        TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS revoked_by        UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS revocation_reason TEXT NULL;
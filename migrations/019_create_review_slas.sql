@@ -0,0 +1,42 @@
+-- Batas waktu review prestasi per tipe prestasi (dan tingkat kompetisi).
+-- competition_level NULL berarti berlaku untuk semua tingkat tipe tersebut; aturan yang paling
+-- spesifik dipakai. Tanpa aturan yang cocok, batas waktu diambil dari env REVIEW_SLA_DAYS.
+-- Eskalasi: escalation_email (misalnya ketua departemen) diberi tahu, dan jika backup_reviewer_id
+-- diisi, tahap dosen wali dialihkan ke dosen tersebut.
+CREATE TABLE IF NOT EXISTS review_slas (
+    id                 UUID PRIMARY KEY,
+    achievement_type   VARCHAR(50) NOT NULL,
+    competition_level  VARCHAR(32) NULL,
+    due_days           INT NOT NULL CHECK (due_days > 0),
+    escalation_email   VARCHAR(255) NULL,
+    backup_reviewer_id UUID NULL REFERENCES lecturers(id) ON DELETE SET NULL,
+    updated_by         UUID NULL,
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_slas_scope
+    ON review_slas(achievement_type, COALESCE(competition_level, ''));
+
+-- Batas waktu dihitung saat submit; escalated_at diisi scheduler sekali per submit,
+-- escalated_to = dosen pengganti yang ikut boleh memproses tahap dosen wali
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS review_due_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS review_sla_id UUID NULL REFERENCES review_slas(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS escalated_at  TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS escalated_to  UUID NULL REFERENCES lecturers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_review_due
+    ON achievement_references(review_due_at)
+    WHERE status = 'submitted' AND escalated_at IS NULL;
+
+-- Prestasi yang sudah menunggu sebelum migration ini tidak diisi di sini: jalankan sekali
+-- go run ./cmd/backfill-review-due-dates, yang mengisi review_due_at dengan REVIEW_SLA_DAYS
+-- agar sama dengan batas waktu bawaan submit baru
+
+-- Permission untuk mengelola SLA review
+INSERT INTO permissions (id, name, resource, action, description)
+SELECT gen_random_uuid(), 'manage_review_slas', 'review_slas', 'manage', 'Kelola batas waktu review dan eskalasi prestasi'
+WHERE NOT EXISTS (SELECT 1 FROM permissions x WHERE x.name = 'manage_review_slas');
+
+SELECT grant_permission_to_admin_roles('manage_review_slas');